@@ -9,6 +9,7 @@ import (
 	"github.com/godyy/gexcels/export"
 	"github.com/godyy/gexcels/export/code"
 	"github.com/godyy/gexcels/export/data"
+	"github.com/godyy/gexcels/export/report"
 	"github.com/godyy/gexcels/parse"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -25,8 +26,20 @@ var (
 	goPackage        = flag.String("go-package", "", "go package name for exporting go code")
 	csharpNamespace  = flag.String("csharp-namespace", "", "namespace for exporting csharp code")
 	csharpTablesType = flag.String("csharp-tables-class", "Tables", "static manager class name for exporting csharp code")
+	csharpUnity      = flag.Bool("csharp-unity", false, "map vec2, vec3, color to UnityEngine.Vector2, Vector3, Color for exporting csharp code")
+	textDir          = flag.String("text-dir", "", "output directory of text string tables, disabled if empty")
+	textLangs        = flag.String("text-langs", "zh", "languages of text string tables, separated by ',', the first one is the source language")
 	mongoURI         = flag.String("mongo-uri", "", "mongo uri for exporting bson data, must specified when data kind is \"bson\"")
 	mongoDB          = flag.String("mongo-db", "", "mongo db name for exporting bson data, must specified when data kind is \"bson\"")
+	cacheDir         = flag.String("cache-dir", "", "directory for incremental parse cache, disabled if empty")
+	collectErrors    = flag.Bool("collect-errors", false, "collect all parse errors instead of stopping at the first one")
+	concurrency      = flag.Int("concurrency", 0, "max number of excel files parsed concurrently, default GOMAXPROCS")
+	timeZone         = flag.String("time-zone", "", "IANA time zone for datetime fields without offset, e.g. \"Asia/Shanghai\", default UTC")
+	reportJson       = flag.String("report-json", "", "output path of json validation report, disabled if empty")
+	reportSarif      = flag.String("report-sarif", "", "output path of SARIF 2.1.0 validation report, disabled if empty")
+	lint             = flag.Bool("lint", false, "check suspicious but legal data and report warnings")
+	lintDisable      = flag.String("lint-disable", "", "lints to disable, separated by ',', e.g. \"unlinked-table,duplicate-desc\", \"all\" for all lints")
+	lintError        = flag.String("lint-error", "", "lints to promote to errors, separated by ',', \"all\" for all lints")
 )
 
 func main() {
@@ -85,10 +98,22 @@ func main() {
 			parseOptions.Tags[i] = gexcels.Tag(v)
 		}
 	}
+	parseOptions.Concurrency = *concurrency
+	parseOptions.CacheDir = *cacheDir
+	parseOptions.CollectErrors = *collectErrors
+	parseOptions.TimeZone = *timeZone
+	if *lint {
+		parseOptions.Lint = &parse.LintOptions{
+			Disable: splitList(*lintDisable),
+			Errors:  splitList(*lintError),
+		}
+	}
 	parser, err := parse.Parse(*excelDir, &parseOptions)
 	if err != nil {
+		exportReport(parse.Diagnostics(err))
 		log.Fatalf("parse failed: %v", err)
 	}
+	exportReport(parser.Warnings)
 
 	// 导出代码
 	switch codeKind {
@@ -100,6 +125,7 @@ func main() {
 		if err := code.ExportCSharp(parser, *codeDir, &codeOptions, &code.CSharpOptions{
 			Namespace:       *csharpNamespace,
 			TablesClassName: *csharpTablesType,
+			UnityTypes:      *csharpUnity,
 		}); err != nil {
 			log.Fatalf("export code failed: %v", err)
 		}
@@ -134,5 +160,37 @@ func main() {
 		}
 	}
 
+	// 导出文本字符串表
+	if *textDir != "" {
+		if err := data.ExportTexts(parser, *textDir, splitList(*textLangs)); err != nil {
+			log.Fatalf("export texts failed: %v", err)
+		}
+	}
+
 	log.Println("export completed.")
 }
+
+// exportReport 导出诊断报告
+func exportReport(diags []*parse.Diagnostic) {
+	if *reportJson != "" {
+		if err := report.ExportJson(diags, *reportJson); err != nil {
+			log.Fatalf("export json report failed: %v", err)
+		}
+	}
+	if *reportSarif != "" {
+		if err := report.ExportSarif(diags, *reportSarif); err != nil {
+			log.Fatalf("export sarif report failed: %v", err)
+		}
+	}
+}
+
+// splitList 分割以','分隔的列表
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
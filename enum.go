@@ -34,6 +34,7 @@ type Enum struct {
 	Name        string               // 枚举名称
 	Type        FieldType            // 值类型
 	Desc        string               // 描述
+	Flags       bool                 // 是否为位标志枚举, 值可以是多个枚举项值的按位或
 	Items       []*EnumItem          // 枚举项列表
 	ItemByName  map[string]*EnumItem // 枚举项名称映射
 	ItemByValue map[string]*EnumItem // 枚举项值映射
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -26,6 +27,7 @@ var ErrCSharpDataKindUnsupported = errors.New("export code: csharp: data kind un
 type CSharpOptions struct {
 	Namespace       string // 代码命名空间
 	TablesClassName string // 静态管理类名称
+	UnityTypes      bool   // vec2, vec3, color 映射为 UnityEngine.Vector2, Vector3, Color
 }
 
 // kind 返回代码导出类型。
@@ -94,9 +96,21 @@ func (e *csharpExporter) export() error {
 	if err := e.exportEnumsFile(); err != nil {
 		return err
 	}
+	if err := e.exportDecimalsFile(); err != nil {
+		return err
+	}
+	if err := e.exportTuplesFile(); err != nil {
+		return err
+	}
+	if err := e.exportTextsFile(); err != nil {
+		return err
+	}
 	if err := e.exportStructsFile(); err != nil {
 		return err
 	}
+	if err := e.exportUnionsFile(); err != nil {
+		return err
+	}
 	if err := e.exportTableFiles(); err != nil {
 		return err
 	}
@@ -124,6 +138,56 @@ func (e *csharpExporter) exportEnumsFile() error {
 	return nil
 }
 
+// exportDecimalsFile 导出定点小数类型文件。
+func (e *csharpExporter) exportDecimalsFile() error {
+	scales := decimalScales(e.parser)
+	if len(scales) == 0 {
+		return nil
+	}
+
+	filePath := filepath.Join(e.path, e.namespaceFilePrefix()+"_decimals.cs")
+	if err := os.WriteFile(filePath, []byte(e.GenDecimalsFile(scales)), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: csharp: decimals to [%s]", filePath)
+	}
+	log.PrintfGreen("export code: csharp: decimals to [%s]", filePath)
+	return nil
+}
+
+// exportTuplesFile 导出元组类型及其序列化转换器文件。
+// 映射为 Unity 类型的元组不生成类型定义, 但仍需生成转换器。
+func (e *csharpExporter) exportTuplesFile() error {
+	tuples := tupleTypes(e.parser)
+	if len(tuples) == 0 {
+		return nil
+	}
+	if e.options.DataKind == export.DataBytes && !slices.ContainsFunc(tuples, func(ti *gexcels.FieldTypeInfo) bool {
+		return !e.isUnityTuple(ti)
+	}) {
+		return nil
+	}
+
+	filePath := filepath.Join(e.path, e.namespaceFilePrefix()+"_tuples.cs")
+	if err := os.WriteFile(filePath, []byte(e.GenTuplesFile(tuples)), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: csharp: tuples to [%s]", filePath)
+	}
+	log.PrintfGreen("export code: csharp: tuples to [%s]", filePath)
+	return nil
+}
+
+// exportTextsFile 导出文本查找文件。
+func (e *csharpExporter) exportTextsFile() error {
+	if !usesText(e.parser) {
+		return nil
+	}
+
+	filePath := filepath.Join(e.path, e.namespaceFilePrefix()+"_texts.cs")
+	if err := os.WriteFile(filePath, []byte(e.GenTextsFile()), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: csharp: texts to [%s]", filePath)
+	}
+	log.PrintfGreen("export code: csharp: texts to [%s]", filePath)
+	return nil
+}
+
 // exportStructsFile 导出结构体定义文件。
 func (e *csharpExporter) exportStructsFile() error {
 	if len(e.parser.Structs) == 0 {
@@ -138,6 +202,20 @@ func (e *csharpExporter) exportStructsFile() error {
 	return nil
 }
 
+// exportUnionsFile 导出联合类型定义文件。
+func (e *csharpExporter) exportUnionsFile() error {
+	if len(e.parser.Unions) == 0 {
+		return nil
+	}
+
+	filePath := filepath.Join(e.path, e.namespaceFilePrefix()+"_unions.cs")
+	if err := os.WriteFile(filePath, []byte(e.GenUnionsFile()), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: csharp: unions to [%s]", filePath)
+	}
+	log.PrintfGreen("export code: csharp: unions to [%s]", filePath)
+	return nil
+}
+
 // exportTableFiles 逐表导出配置表文件。
 func (e *csharpExporter) exportTableFiles() error {
 	for _, td := range e.parser.Tables {
@@ -240,6 +318,59 @@ func (e *csharpExporter) GenEnumItemName(name string) string {
 	return utils.CamelCase(name, true)
 }
 
+// GenDecimalName 返回小数位数为 scale 的定点小数类型名。
+func (e *csharpExporter) GenDecimalName(scale int) string {
+	return fmt.Sprintf("Decimal%d", scale)
+}
+
+// unityTupleTypes 内置元组对应的 Unity 类型。
+var unityTupleTypes = map[string]string{
+	gexcels.TupleVec2:  "UnityEngine.Vector2",
+	gexcels.TupleVec3:  "UnityEngine.Vector3",
+	gexcels.TupleColor: "UnityEngine.Color",
+}
+
+// isUnityTuple 判断元组是否映射为 Unity 类型。
+func (e *csharpExporter) isUnityTuple(ti *gexcels.FieldTypeInfo) bool {
+	return e.kindOptions.UnityTypes && unityTupleTypes[ti.GetName()] != ""
+}
+
+// GenTupleName 返回元组类型名, 也用作转换器名称的前缀。
+func (e *csharpExporter) GenTupleName(ti *gexcels.FieldTypeInfo) string {
+	return tupleName(ti, func(name string) string {
+		return e.GetEnumName(e.parser.GetEnum(name))
+	})
+}
+
+// GenTupleType 返回元组在代码中使用的类型, 启用 Unity 类型时内置元组映射为 Unity 类型。
+func (e *csharpExporter) GenTupleType(ti *gexcels.FieldTypeInfo) string {
+	if e.isUnityTuple(ti) {
+		return e.GenGlobalTypeName(unityTupleTypes[ti.GetName()])
+	}
+	return e.GenTupleName(ti)
+}
+
+// GenTupleMemberName 返回元组第 i 个元素的成员名, Unity 类型的成员为小写字段, e.g. x, r。
+func (e *csharpExporter) GenTupleMemberName(ti *gexcels.FieldTypeInfo, i int) string {
+	name := ti.GetTupleElementNames()[i]
+	if e.isUnityTuple(ti) {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// GenTupleBsonElementSerializer 返回元组元素的 bson 序列化器表达式。
+// duration 存储为纳秒数, 需指定 TimeSpan 的序列化方式。
+func (e *csharpExporter) GenTupleBsonElementSerializer(et *gexcels.FieldTypeInfo) string {
+	if et.Type == gexcels.FTDuration {
+		return fmt.Sprintf("new %s(%s, %s)",
+			e.GenGlobalTypeName("MongoDB.Bson.Serialization.Serializers.TimeSpanSerializer"),
+			e.GenGlobalTypeName("MongoDB.Bson.BsonType.Int64"),
+			e.GenGlobalTypeName("MongoDB.Bson.Serialization.Options.TimeSpanUnits.Nanoseconds"))
+	}
+	return fmt.Sprintf("%s<%s>()", e.GenGlobalTypeName("MongoDB.Bson.Serialization.BsonSerializer.LookupSerializer"), e.GenTypeInfo(et))
+}
+
 // GetStructName 返回结构体的导出类型名，并做结果缓存。
 func (e *csharpExporter) GetStructName(sd *parse.Struct) string {
 	if name, ok := e.structNames[sd.Name]; ok {
@@ -250,6 +381,51 @@ func (e *csharpExporter) GetStructName(sd *parse.Struct) string {
 	return name
 }
 
+// GenStructClassHead 返回结构体类声明，被继承的结构体不能为 sealed，子结构体继承父结构体类。
+func (e *csharpExporter) GenStructClassHead(sd *parse.Struct) string {
+	head := "public sealed class " + e.GetStructName(sd)
+	for _, other := range e.parser.Structs {
+		if other.Parent != nil && other.Parent.Name == sd.Name {
+			head = "public class " + e.GetStructName(sd)
+			break
+		}
+	}
+	if sd.Parent != nil {
+		head += " : " + e.GetStructName(e.parser.GetStructByName(sd.Parent.Name))
+	}
+	return head
+}
+
+// GenUnionName 返回联合类型的导出类型名。
+func (e *csharpExporter) GenUnionName(u *parse.Union) string {
+	return utils.CamelCase(u.Name, true)
+}
+
+// GenUnionCaseType 返回联合类型判别值的类型, string 枚举退化为 string。
+func (e *csharpExporter) GenUnionCaseType(u *parse.Union) string {
+	enum := e.parser.GetEnum(u.Enum)
+	if e.canUseNativeEnum(enum) {
+		return e.GetEnumName(enum)
+	}
+	return "string"
+}
+
+// GenUnionCaseValue 返回联合类型分支对应的判别值表达式。
+func (e *csharpExporter) GenUnionCaseValue(u *parse.Union, c *gexcels.UnionCase) string {
+	return e.GetEnumName(e.parser.GetEnum(u.Enum)) + "." + e.GenEnumItemName(c.Name)
+}
+
+// GenUnionCaseClassName 返回联合类型分支的嵌套类名。
+func (e *csharpExporter) GenUnionCaseClassName(c *gexcels.UnionCase) string {
+	return utils.CamelCase(c.Name, true)
+}
+
+// GenUnionCaseStructType 返回联合类型分支结构体的全限定类型名。
+// 分支类嵌套在联合类型中, 使用全限定名称避免与同名分支类冲突。
+func (e *csharpExporter) GenUnionCaseStructType(c *gexcels.UnionCase) string {
+	return e.GenGlobalTypeName(e.kindOptions.Namespace + "." + e.GetStructName(e.parser.GetStructByName(c.Struct)))
+}
+
 // GetEntryClassName 返回普通表条目类名，并做结果缓存。
 func (e *csharpExporter) GetEntryClassName(td *parse.Table) string {
 	if name, ok := e.entryClassNames[td.Name]; ok {
@@ -318,14 +494,30 @@ func (e *csharpExporter) GenPrimitiveType(ft gexcels.FieldType) string {
 		return "int"
 	case gexcels.FTInt64:
 		return "long"
+	case gexcels.FTInt8:
+		return "sbyte"
+	case gexcels.FTInt16:
+		return "short"
+	case gexcels.FTUint8:
+		return "byte"
+	case gexcels.FTUint16:
+		return "ushort"
+	case gexcels.FTUint32:
+		return "uint"
+	case gexcels.FTUint64:
+		return "ulong"
 	case gexcels.FTFloat32:
 		return "float"
 	case gexcels.FTFloat64:
 		return "double"
 	case gexcels.FTBool:
 		return "bool"
-	case gexcels.FTString:
+	case gexcels.FTString, gexcels.FTText:
 		return "string"
+	case gexcels.FTDatetime:
+		return e.GenGlobalTypeName("System.DateTime")
+	case gexcels.FTDuration:
+		return e.GenGlobalTypeName("System.TimeSpan")
 	default:
 		return ""
 	}
@@ -341,15 +533,18 @@ func (e *csharpExporter) canUseNativeEnum(enum *parse.Enum) bool {
 // 这里会处理 primitive、enum、struct、array、map 等所有导出场景。
 func (e *csharpExporter) GenTypeInfo(ti *gexcels.FieldTypeInfo) string {
 	switch ti.Type {
-	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString:
-		return e.GenPrimitiveType(ti.Type)
+	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64,
+		gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString, gexcels.FTText, gexcels.FTDatetime, gexcels.FTDuration:
+		return e.genOptionalType(ti, e.GenPrimitiveType(ti.Type))
+	case gexcels.FTDecimal:
+		return e.genOptionalType(ti, e.GenDecimalName(ti.GetScale()))
 	case gexcels.FTEnum:
 		enum := e.parser.GetEnum(ti.GetName())
 		if enum == nil {
 			panic(fmt.Sprintf("export code: csharp: enum %s not found", ti.GetName()))
 		}
 		if e.canUseNativeEnum(enum) {
-			return e.GetEnumName(enum)
+			return e.genOptionalType(ti, e.GetEnumName(enum))
 		}
 		return "string"
 	case gexcels.FTStruct:
@@ -358,6 +553,14 @@ func (e *csharpExporter) GenTypeInfo(ti *gexcels.FieldTypeInfo) string {
 			panic(fmt.Sprintf("export code: csharp: struct %s not found", ti.GetName()))
 		}
 		return e.GetStructName(sd)
+	case gexcels.FTUnion:
+		u := e.parser.GetUnionByName(ti.GetName())
+		if u == nil {
+			panic(fmt.Sprintf("export code: csharp: union %s not found", ti.GetName()))
+		}
+		return e.GenUnionName(u)
+	case gexcels.FTTuple:
+		return e.GenTupleType(ti)
 	case gexcels.FTArray:
 		return e.GenListType(e.GenTypeInfo(ti.GetElementType()))
 	case gexcels.FTMap:
@@ -367,6 +570,23 @@ func (e *csharpExporter) GenTypeInfo(ti *gexcels.FieldTypeInfo) string {
 	}
 }
 
+// genOptionalType 可选的值类型使用 Nullable 表示。
+// string 本身可为 null，无需额外标记。
+func (e *csharpExporter) genOptionalType(ti *gexcels.FieldTypeInfo, typ string) string {
+	if ti.Optional && typ != "string" {
+		return typ + "?"
+	}
+	return typ
+}
+
+// GenOptionalValue 返回可选属性在已判空后的取值表达式。
+func (e *csharpExporter) GenOptionalValue(ti *gexcels.FieldTypeInfo, propName string) string {
+	if strings.HasSuffix(e.GenTypeInfo(ti), "?") {
+		return propName + ".Value"
+	}
+	return propName
+}
+
 // GenMapKeyType 返回 map key 的 C# 类型。
 // 若 key 是枚举，则退化到底层 primitive，避免把 string 常量枚举用作字典键类型。
 func (e *csharpExporter) GenMapKeyType(ti *gexcels.FieldTypeInfo) string {
@@ -396,6 +616,31 @@ func (e *csharpExporter) GenUniqueMethodName(fd *gexcels.TableField) string {
 	return "By" + e.GetEntryFieldName(fd)
 }
 
+// GenHasPropertyName 返回可选属性是否设置的属性名。
+func (e *csharpExporter) GenHasPropertyName(propName string) string {
+	return "Has" + propName
+}
+
+// GetTextsClassName 返回文本查找类名。
+func (e *csharpExporter) GetTextsClassName() string {
+	return "Texts"
+}
+
+// IsTextField 返回字段是否文本类型。
+func (e *csharpExporter) IsTextField(fd *gexcels.Field) bool {
+	return fd.Type == gexcels.FTText
+}
+
+// GenTextPropertyName 返回文本属性查找文本的属性名。
+func (e *csharpExporter) GenTextPropertyName(propName string) string {
+	return propName + "Text"
+}
+
+// GenEntryLinkMethodName 返回表项链接访问方法名。
+func (e *csharpExporter) GenEntryLinkMethodName(fd *gexcels.TableField) string {
+	return e.GetEntryFieldName(fd) + "Link"
+}
+
 // GenCompositeIndexFieldName 返回组合键索引字段名。
 func (e *csharpExporter) GenCompositeIndexFieldName(ck *parse.TableCompositeKey) string {
 	return "byCompositeKey" + utils.CamelCase(ck.Name, true)
@@ -478,6 +723,32 @@ func (e *csharpExporter) GenPropertyAttributes(dataName string, isID bool) []str
 	}
 }
 
+// GenTypeAttributes 根据字段类型生成额外的属性特性。
+// bson 中 duration 存储为纳秒数, 需指定 TimeSpan 的序列化方式。
+func (e *csharpExporter) GenTypeAttributes(ti *gexcels.FieldTypeInfo) []string {
+	if e.needsBsonAnnotations() && typeUsesDuration(ti) {
+		return []string{fmt.Sprintf("[%s(%s, %s)]",
+			e.GenGlobalTypeName("MongoDB.Bson.Serialization.Attributes.BsonTimeSpanOptionsAttribute"),
+			e.GenGlobalTypeName("MongoDB.Bson.BsonType.Int64"),
+			e.GenGlobalTypeName("MongoDB.Bson.Serialization.Options.TimeSpanUnits.Nanoseconds"))}
+	}
+	return nil
+}
+
+// typeUsesDuration 返回类型是否包含 duration。
+func typeUsesDuration(ti *gexcels.FieldTypeInfo) bool {
+	switch ti.Type {
+	case gexcels.FTDuration:
+		return true
+	case gexcels.FTArray:
+		return typeUsesDuration(ti.GetElementType())
+	case gexcels.FTMap:
+		return typeUsesDuration(ti.GetMapValueType())
+	default:
+		return false
+	}
+}
+
 // GenClassAttributes 返回当前导出场景需要附加到类型上的公共特性。
 func (e *csharpExporter) GenClassAttributes() []string {
 	if e.needsBsonAnnotations() {
@@ -64,7 +64,8 @@ var templateCSharpNativeEnum = template.Must(template.New("csharp_native_enum").
 	Funcs(csharpTemplateFuncMap).
 	Parse(`{{$enumName := .Exporter.GetEnumName .Enum -}}
 {{xmlDocBlock .Enum.Desc}}
-public enum {{$enumName}} : int
+{{if .Enum.Flags}}[global::System.Flags]
+{{end}}public enum {{$enumName}} : int
 {
 {{range $index, $item := .Enum.Items -}}
 {{if $index}}{{"\n"}}{{end -}}
@@ -73,7 +74,20 @@ public enum {{$enumName}} : int
     /// </summary>
 {{end}}    {{$.Exporter.GenEnumItemName $item.Name}} = {{formatValue ($.Enum.GetItemValue $index)}},
 {{- end}}
-}`))
+}
+{{- if .Enum.Flags}}
+
+/// <summary>
+/// {{$enumName}}Extensions provides helpers for {{$enumName}} flags.
+/// </summary>
+public static class {{$enumName}}Extensions
+{
+    /// <summary>
+    /// Has reports whether all bits of flag are set in value.
+    /// </summary>
+    public static bool Has(this {{$enumName}} value, {{$enumName}} flag) => (value & flag) == flag;
+}
+{{- end}}`))
 
 // templateCSharpStringEnum C# 字符串常量枚举模版。
 var templateCSharpStringEnum = template.Must(template.New("csharp_string_enum").
@@ -103,18 +117,335 @@ namespace {{.Namespace}};
 {{$.Exporter.GenEnum $enum}}
 {{end}}`))
 
+// templateCSharpDecimal C# 定点小数类型模版。
+// 序列化特性按导出格式生成, json 与 bson 均以缩放后的整数存储。
+var templateCSharpDecimal = template.Must(template.New("csharp_decimal").
+	Parse(`{{$name := .Name -}}
+/// <summary>
+/// {{$name}} is a fixed-point decimal with {{.Scale}} decimal places, stored as value * {{.One}}.
+/// </summary>
+{{if .Json}}[global::System.Text.Json.Serialization.JsonConverterAttribute(typeof({{$name}}.JsonValueConverter))]
+{{end}}{{if .Bson}}[global::MongoDB.Bson.Serialization.Attributes.BsonSerializerAttribute(typeof({{$name}}.BsonValueSerializer))]
+{{end}}public readonly struct {{$name}} : global::System.IEquatable<{{$name}}>, global::System.IComparable<{{$name}}>
+{
+    /// <summary>
+    /// Scale is the number of decimal places.
+    /// </summary>
+    public const int Scale = {{.Scale}};
+
+    private const long One = {{.One}};
+
+    /// <summary>
+    /// Raw is the scaled integer value.
+    /// </summary>
+    public long Raw { get; }
+
+    private {{$name}}(long raw)
+    {
+        Raw = raw;
+    }
+
+    public static {{$name}} FromRaw(long raw) => new(raw);
+
+    public static {{$name}} FromInt(long value) => new(checked(value * One));
+
+    /// <summary>
+    /// ToInt returns the integer part, truncated toward zero.
+    /// </summary>
+    public long ToInt() => Raw / One;
+
+    /// <summary>
+    /// ToDouble returns the double value, which is not deterministic across platforms.
+    /// </summary>
+    public double ToDouble() => (double)Raw / One;
+
+    public decimal ToDecimal() => (decimal)Raw / One;
+
+    public static {{$name}} operator +({{$name}} a, {{$name}} b) => new(checked(a.Raw + b.Raw));
+
+    public static {{$name}} operator -({{$name}} a, {{$name}} b) => new(checked(a.Raw - b.Raw));
+
+    public static {{$name}} operator -({{$name}} a) => new(checked(-a.Raw));
+
+    public static bool operator ==({{$name}} a, {{$name}} b) => a.Raw == b.Raw;
+
+    public static bool operator !=({{$name}} a, {{$name}} b) => a.Raw != b.Raw;
+
+    public static bool operator <({{$name}} a, {{$name}} b) => a.Raw < b.Raw;
+
+    public static bool operator >({{$name}} a, {{$name}} b) => a.Raw > b.Raw;
+
+    public static bool operator <=({{$name}} a, {{$name}} b) => a.Raw <= b.Raw;
+
+    public static bool operator >=({{$name}} a, {{$name}} b) => a.Raw >= b.Raw;
+
+    public bool Equals({{$name}} other) => Raw == other.Raw;
+
+    public override bool Equals(object obj) => obj is {{$name}} other && Equals(other);
+
+    public override int GetHashCode() => Raw.GetHashCode();
+
+    public int CompareTo({{$name}} other) => Raw.CompareTo(other.Raw);
+
+    public override string ToString() => ToDecimal().ToString("F{{.Scale}}", global::System.Globalization.CultureInfo.InvariantCulture);
+{{- if .Json}}
+
+    /// <summary>
+    /// JsonValueConverter reads and writes the scaled integer value.
+    /// </summary>
+    public sealed class JsonValueConverter : global::System.Text.Json.Serialization.JsonConverter<{{$name}}>
+    {
+        public override {{$name}} Read(ref global::System.Text.Json.Utf8JsonReader reader, global::System.Type typeToConvert, global::System.Text.Json.JsonSerializerOptions options)
+        {
+            return new(reader.GetInt64());
+        }
+
+        public override void Write(global::System.Text.Json.Utf8JsonWriter writer, {{$name}} value, global::System.Text.Json.JsonSerializerOptions options)
+        {
+            writer.WriteNumberValue(value.Raw);
+        }
+    }
+{{- end}}
+{{- if .Bson}}
+
+    /// <summary>
+    /// BsonValueSerializer reads and writes the scaled integer value.
+    /// </summary>
+    public sealed class BsonValueSerializer : global::MongoDB.Bson.Serialization.Serializers.SerializerBase<{{$name}}>
+    {
+        public override {{$name}} Deserialize(global::MongoDB.Bson.Serialization.BsonDeserializationContext context, global::MongoDB.Bson.Serialization.BsonDeserializationArgs args)
+        {
+            return new(context.Reader.ReadInt64());
+        }
+
+        public override void Serialize(global::MongoDB.Bson.Serialization.BsonSerializationContext context, global::MongoDB.Bson.Serialization.BsonSerializationArgs args, {{$name}} value)
+        {
+            context.Writer.WriteInt64(value.Raw);
+        }
+    }
+{{- end}}
+}`))
+
+// templateCSharpDecimalsFile C# 定点小数类型文件模版。
+var templateCSharpDecimalsFile = template.Must(template.New("csharp_decimals_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+namespace {{.Namespace}};
+
+{{range $index, $scale := .Scales -}}
+{{if $index}}{{"\n\n"}}{{end -}}
+{{$.Exporter.GenDecimal $scale}}
+{{end}}`))
+
+// templateCSharpTuple C# 元组类型模版。
+// 元组生成为只读值类型, 元素通过构造函数按顺序传入。
+var templateCSharpTuple = template.Must(template.New("csharp_tuple").
+	Funcs(csharpTemplateFuncMap).
+	Parse(`{{$name := .Exporter.GenTupleName .Tuple -}}
+{{$names := .Tuple.GetTupleElementNames -}}
+{{$elems := .Tuple.GetTupleElements -}}
+/// <summary>
+/// {{$name}} is the value of {{.Tuple}}.
+/// </summary>
+public readonly struct {{$name}}
+{
+{{- range $i, $et := $elems}}
+    public {{$.Exporter.GenTypeInfo $et}} {{index $names $i}} { get; }
+{{end}}
+    public {{$name}}({{range $i, $et := $elems}}{{if $i}}, {{end}}{{$.Exporter.GenTypeInfo $et}} {{$.Exporter.GenParamName (index $names $i)}}{{end}})
+    {
+{{- range $i, $et := $elems}}
+        {{index $names $i}} = {{$.Exporter.GenParamName (index $names $i)}};
+{{- end}}
+    }
+
+    public override string ToString() => $"({{range $i, $et := $elems}}{{if $i}}, {{end}}{{"{"}}{{index $names $i}}{{"}"}}{{end}})";
+}`))
+
+// templateCSharpTupleJsonConverter C# 元组 json 转换器模版。
+// 元组以元素数组存储, 转换器独立于类型定义, 以便同样适用于 Unity 类型。
+var templateCSharpTupleJsonConverter = template.Must(template.New("csharp_tuple_json_converter").
+	Parse(`{{$type := .Exporter.GenTupleType .Tuple -}}
+{{$elems := .Tuple.GetTupleElements -}}
+/// <summary>
+/// {{.Name}}JsonConverter reads and writes {{.Tuple}} as an array of elements.
+/// </summary>
+internal sealed class {{.Name}}JsonConverter : global::System.Text.Json.Serialization.JsonConverter<{{$type}}>
+{
+    public override {{$type}} Read(ref global::System.Text.Json.Utf8JsonReader reader, global::System.Type typeToConvert, global::System.Text.Json.JsonSerializerOptions options)
+    {
+        if (reader.TokenType == global::System.Text.Json.JsonTokenType.Null)
+        {
+            return default;
+        }
+        if (reader.TokenType != global::System.Text.Json.JsonTokenType.StartArray)
+        {
+            throw new global::System.Text.Json.JsonException("{{.Tuple}} must be an array");
+        }
+{{- range $i, $et := $elems}}
+        reader.Read();
+        var item{{$i}} = global::System.Text.Json.JsonSerializer.Deserialize<{{$.Exporter.GenTypeInfo $et}}>(ref reader, options);
+{{- end}}
+        reader.Read();
+        if (reader.TokenType != global::System.Text.Json.JsonTokenType.EndArray)
+        {
+            throw new global::System.Text.Json.JsonException("{{.Tuple}} must have {{len $elems}} elements");
+        }
+        return new {{$type}}({{range $i, $et := $elems}}{{if $i}}, {{end}}item{{$i}}{{end}});
+    }
+
+    public override void Write(global::System.Text.Json.Utf8JsonWriter writer, {{$type}} value, global::System.Text.Json.JsonSerializerOptions options)
+    {
+        writer.WriteStartArray();
+{{- range $i, $et := $elems}}
+        global::System.Text.Json.JsonSerializer.Serialize(writer, value.{{$.Exporter.GenTupleMemberName $.Tuple $i}}, options);
+{{- end}}
+        writer.WriteEndArray();
+    }
+}`))
+
+// templateCSharpTupleBsonSerializer C# 元组 bson 序列化器模版。
+var templateCSharpTupleBsonSerializer = template.Must(template.New("csharp_tuple_bson_serializer").
+	Parse(`{{$type := .Exporter.GenTupleType .Tuple -}}
+{{$elems := .Tuple.GetTupleElements -}}
+/// <summary>
+/// {{.Name}}BsonSerializer reads and writes {{.Tuple}} as an array of elements.
+/// </summary>
+internal sealed class {{.Name}}BsonSerializer : global::MongoDB.Bson.Serialization.Serializers.SerializerBase<{{$type}}>
+{
+    public override {{$type}} Deserialize(global::MongoDB.Bson.Serialization.BsonDeserializationContext context, global::MongoDB.Bson.Serialization.BsonDeserializationArgs args)
+    {
+        if (context.Reader.GetCurrentBsonType() == global::MongoDB.Bson.BsonType.Null)
+        {
+            context.Reader.ReadNull();
+            return default;
+        }
+        context.Reader.ReadStartArray();
+{{- range $i, $et := $elems}}
+        var item{{$i}} = global::MongoDB.Bson.Serialization.IBsonSerializerExtensions.Deserialize({{$.Exporter.GenTupleBsonElementSerializer $et}}, context);
+{{- end}}
+        context.Reader.ReadEndArray();
+        return new {{$type}}({{range $i, $et := $elems}}{{if $i}}, {{end}}item{{$i}}{{end}});
+    }
+
+    public override void Serialize(global::MongoDB.Bson.Serialization.BsonSerializationContext context, global::MongoDB.Bson.Serialization.BsonSerializationArgs args, {{$type}} value)
+    {
+        context.Writer.WriteStartArray();
+{{- range $i, $et := $elems}}
+        global::MongoDB.Bson.Serialization.IBsonSerializerExtensions.Serialize({{$.Exporter.GenTupleBsonElementSerializer $et}}, context, value.{{$.Exporter.GenTupleMemberName $.Tuple $i}});
+{{- end}}
+        context.Writer.WriteEndArray();
+    }
+}`))
+
+// templateCSharpTuplesFile C# 元组类型文件模版。
+var templateCSharpTuplesFile = template.Must(template.New("csharp_tuples_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+namespace {{.Namespace}};
+
+{{range $index, $text := .Blocks -}}
+{{if $index}}{{"\n\n"}}{{end -}}
+{{$text}}
+{{end}}`))
+
+// templateCSharpTextsFile C# 文本查找文件模版。
+var templateCSharpTextsFile = template.Must(template.New("csharp_texts_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+namespace {{.Namespace}};
+
+/// <summary>
+/// {{.ClassName}} looks up localized text by the keys stored in text fields.
+/// </summary>
+public static class {{.ClassName}}
+{
+    private static volatile global::System.Func<string, string> lookup;
+
+    /// <summary>
+    /// Lookup is the function used to look up localized text by key, null to reset.
+    /// </summary>
+    public static global::System.Func<string, string> Lookup
+    {
+        get => lookup;
+        set => lookup = value;
+    }
+
+    /// <summary>
+    /// Get returns the localized text of key, returns key itself when key is empty or Lookup is null.
+    /// </summary>
+    public static string Get(string key)
+    {
+        var fn = lookup;
+        if (string.IsNullOrEmpty(key) || fn == null)
+        {
+            return key;
+        }
+        return fn(key);
+    }
+
+    /// <summary>
+    /// Load uses texts to look up localized text, returns key itself when the text of key is missing or empty.
+    /// </summary>
+    public static void Load(global::System.Collections.Generic.IReadOnlyDictionary<string, string> texts)
+    {
+        if (texts == null)
+        {
+            throw new global::System.ArgumentNullException(nameof(texts));
+        }
+        Lookup = key => texts.TryGetValue(key, out var text) && !string.IsNullOrEmpty(text) ? text : key;
+    }
+{{- if .Json}}
+
+    /// <summary>
+    /// LoadAsync loads the string table of lang in basePath and uses it to look up localized text.
+    /// </summary>
+    public static async global::System.Threading.Tasks.Task LoadAsync(string basePath, string lang)
+    {
+        if (string.IsNullOrWhiteSpace(basePath))
+        {
+            throw new global::System.ArgumentException("basePath is empty", nameof(basePath));
+        }
+        if (string.IsNullOrWhiteSpace(lang))
+        {
+            throw new global::System.ArgumentException("lang is empty", nameof(lang));
+        }
+        var filePath = global::System.IO.Path.Combine(basePath, {{.TextFilePrefix}} + lang + ".json");
+        await using var stream = global::System.IO.File.OpenRead(filePath);
+        var texts = await global::System.Text.Json.JsonSerializer.DeserializeAsync<global::System.Collections.Generic.Dictionary<string, string>>(stream);
+        if (texts == null)
+        {
+            throw new global::System.InvalidOperationException($"texts[{lang}] deserialize returned null");
+        }
+        Load(texts);
+    }
+{{- end}}
+}
+`))
+
+// templateCSharpTextProperty C# 文本属性查找文本的属性模版。
+var templateCSharpTextProperty = template.Must(template.New("csharp_text_property").
+	Funcs(csharpTemplateFuncMap).
+	Parse(`{{$textName := .Exporter.GenTextPropertyName .PropertyName -}}
+{{xmlDocBlock (printf "%s returns the localized text of %s." $textName .PropertyName)}}
+public string {{$textName}} => {{.ClassName}}.Get({{.PropertyName}});`))
+
 // templateCSharpStruct C# 结构体模版。
 var templateCSharpStruct = template.Must(template.New("csharp_struct").
 	Funcs(csharpTemplateFuncMap).
-	Parse(`{{$structName := .Exporter.GetStructName .Struct -}}
-{{$attrs := .Exporter.GenClassAttributeBlock -}}
+	Parse(`{{$attrs := .Exporter.GenClassAttributeBlock -}}
 {{xmlDocBlock .Struct.Desc}}
 {{if $attrs}}{{$attrs}}
-{{end}}public sealed class {{$structName}}
+{{end}}{{.Exporter.GenStructClassHead .Struct}}
 {
-{{range $index, $field := .Struct.Fields -}}
+{{range $index, $field := .Struct.OwnFields -}}
 {{if $index}}{{"\n"}}{{end -}}
 {{indent 4 ($.Exporter.GenStructProperty $field)}}
+{{- if $field.Optional}}{{"\n\n"}}{{indent 4 ($.Exporter.GenHasProperty ($.Exporter.GetFieldName $field))}}{{end}}
+{{- if $.Exporter.IsTextField $field}}{{"\n\n"}}{{indent 4 ($.Exporter.GenTextProperty ($.Exporter.GetFieldName $field))}}{{end}}
 {{end}}
 }`))
 
@@ -131,6 +462,141 @@ var templateCSharpStructsFile = template.Must(template.New("csharp_structs_file"
 {{$.Exporter.GenStruct $struct}}
 {{end}}`))
 
+// templateCSharpUnion C# 联合类型模版。
+// 联合类型生成为抽象类, 各分支生成为嵌套的派生类, 序列化转换器按导出格式生成。
+var templateCSharpUnion = template.Must(template.New("csharp_union").
+	Funcs(csharpTemplateFuncMap).
+	Parse(`{{$name := .Exporter.GenUnionName .Union -}}
+{{$caseType := .Exporter.GenUnionCaseType .Union -}}
+{{xmlDocBlock .Union.Desc}}
+{{if .Json}}[global::System.Text.Json.Serialization.JsonConverterAttribute(typeof({{$name}}.JsonValueConverter))]
+{{end}}{{if .Bson}}[global::MongoDB.Bson.Serialization.Attributes.BsonSerializerAttribute(typeof({{$name}}.BsonValueSerializer))]
+{{end}}public abstract class {{$name}}
+{
+    /// <summary>
+    /// Case is the discriminator of the selected variant.
+    /// </summary>
+    public abstract {{$caseType}} Case { get; }
+
+    /// <summary>
+    /// ValueObject is the case struct of the selected variant.
+    /// </summary>
+    internal abstract object ValueObject { get; set; }
+
+    /// <summary>
+    /// ValueType is the type of the case struct.
+    /// </summary>
+    internal abstract global::System.Type ValueType { get; }
+
+    /// <summary>
+    /// Create creates the variant of case c.
+    /// </summary>
+    internal static {{$name}} Create({{$caseType}} c)
+    {
+        switch (c)
+        {
+{{- range .Union.Cases}}
+            case {{$.Exporter.GenUnionCaseValue $.Union .}}:
+                return new {{$.Exporter.GenUnionCaseClassName .}}();
+{{- end}}
+            default:
+                throw new global::System.InvalidOperationException($"{{$name}} case[{c}] invalid");
+        }
+    }
+{{range .Union.Cases}}{{$struct := $.Exporter.GenUnionCaseStructType .}}
+    /// <summary>
+    /// {{$.Exporter.GenUnionCaseClassName .}} is the variant of case {{.Name}}.
+    /// </summary>
+    public sealed class {{$.Exporter.GenUnionCaseClassName .}} : {{$name}}
+    {
+        public override {{$caseType}} Case => {{$.Exporter.GenUnionCaseValue $.Union .}};
+
+        public {{$struct}} Value { get; set; }
+
+        internal override object ValueObject
+        {
+            get => Value;
+            set => Value = ({{$struct}})value;
+        }
+
+        internal override global::System.Type ValueType => typeof({{$struct}});
+    }
+{{end}}
+{{- if .Json}}
+    /// <summary>
+    /// JsonValueConverter reads and writes the discriminator and the case struct.
+    /// </summary>
+    public sealed class JsonValueConverter : global::System.Text.Json.Serialization.JsonConverter<{{$name}}>
+    {
+        public override {{$name}} Read(ref global::System.Text.Json.Utf8JsonReader reader, global::System.Type typeToConvert, global::System.Text.Json.JsonSerializerOptions options)
+        {
+            using var document = global::System.Text.Json.JsonDocument.ParseValue(ref reader);
+            var root = document.RootElement;
+            var value = Create({{if .Native}}({{$caseType}})root.GetProperty({{quote .TypeName}}).GetInt32(){{else}}root.GetProperty({{quote .TypeName}}).GetString(){{end}});
+            value.ValueObject = global::System.Text.Json.JsonSerializer.Deserialize(root.GetProperty({{quote .ValueName}}), value.ValueType, options);
+            return value;
+        }
+
+        public override void Write(global::System.Text.Json.Utf8JsonWriter writer, {{$name}} value, global::System.Text.Json.JsonSerializerOptions options)
+        {
+            writer.WriteStartObject();
+            writer.WritePropertyName({{quote .TypeName}});
+            {{if .Native}}writer.WriteNumberValue((int)value.Case);{{else}}writer.WriteStringValue(value.Case);{{end}}
+            writer.WritePropertyName({{quote .ValueName}});
+            global::System.Text.Json.JsonSerializer.Serialize(writer, value.ValueObject, value.ValueType, options);
+            writer.WriteEndObject();
+        }
+    }
+{{- end}}
+{{- if .Bson}}
+    /// <summary>
+    /// BsonValueSerializer reads and writes the discriminator and the case struct.
+    /// </summary>
+    public sealed class BsonValueSerializer : global::MongoDB.Bson.Serialization.Serializers.SerializerBase<{{$name}}>
+    {
+        public override {{$name}} Deserialize(global::MongoDB.Bson.Serialization.BsonDeserializationContext context, global::MongoDB.Bson.Serialization.BsonDeserializationArgs args)
+        {
+            if (context.Reader.GetCurrentBsonType() == global::MongoDB.Bson.BsonType.Null)
+            {
+                context.Reader.ReadNull();
+                return null;
+            }
+            var document = global::MongoDB.Bson.Serialization.Serializers.BsonDocumentSerializer.Instance.Deserialize(context);
+            var value = Create({{if .Native}}({{$caseType}})document[{{quote .TypeName}}].AsInt32{{else}}document[{{quote .TypeName}}].AsString{{end}});
+            value.ValueObject = global::MongoDB.Bson.Serialization.BsonSerializer.Deserialize(document[{{quote .ValueName}}].AsBsonDocument, value.ValueType);
+            return value;
+        }
+
+        public override void Serialize(global::MongoDB.Bson.Serialization.BsonSerializationContext context, global::MongoDB.Bson.Serialization.BsonSerializationArgs args, {{$name}} value)
+        {
+            if (value == null)
+            {
+                context.Writer.WriteNull();
+                return;
+            }
+            context.Writer.WriteStartDocument();
+            context.Writer.WriteName({{quote .TypeName}});
+            {{if .Native}}context.Writer.WriteInt32((int)value.Case);{{else}}context.Writer.WriteString(value.Case);{{end}}
+            context.Writer.WriteName({{quote .ValueName}});
+            global::MongoDB.Bson.Serialization.BsonSerializer.Serialize(context.Writer, value.ValueType, value.ValueObject);
+            context.Writer.WriteEndDocument();
+        }
+    }
+{{- end}}
+}`))
+
+// templateCSharpUnionsFile C# 联合类型文件模版。
+var templateCSharpUnionsFile = template.Must(template.New("csharp_unions_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+namespace {{.Namespace}};
+
+{{range $index, $union := .Unions -}}
+{{if $index}}{{"\n\n"}}{{end -}}
+{{$.Exporter.GenUnion $union}}
+{{end}}`))
+
 // templateCSharpEntryClass C# 表项类模版。
 var templateCSharpEntryClass = template.Must(template.New("csharp_entry_class").
 	Funcs(csharpTemplateFuncMap).
@@ -143,9 +609,44 @@ var templateCSharpEntryClass = template.Must(template.New("csharp_entry_class").
 {{range $index, $field := .Table.Fields -}}
 {{if $index}}{{"\n"}}{{end -}}
 {{indent 4 ($.Exporter.GenEntryProperty $field)}}
-{{end}}
+{{- if $field.Optional}}{{"\n\n"}}{{indent 4 ($.Exporter.GenHasProperty ($.Exporter.GetEntryFieldName $field))}}{{end}}
+{{- if $.Exporter.IsTextField $field.Field}}{{"\n\n"}}{{indent 4 ($.Exporter.GenTextProperty ($.Exporter.GetEntryFieldName $field))}}{{end}}
+{{end -}}
+{{range $link := .Links}}
+{{"\n"}}{{indent 4 ($.Exporter.GenEntryLinkMethod $.Table $link)}}
+{{- end}}
 }`))
 
+// templateCSharpEntryLinkMethod C# 表项链接访问方法模版。
+var templateCSharpEntryLinkMethod = template.Must(template.New("csharp_entry_link_method").
+	Funcs(csharpTemplateFuncMap).
+	Parse(`{{$methodName := .Exporter.GenEntryLinkMethodName .Link.Field -}}
+{{$propName := .Exporter.GetEntryFieldName .Link.Field -}}
+{{xmlDocBlock (printf "%s returns the linked %s entry by %s, or null." $methodName .Link.DstTable.Name .Link.DstField.Name)}}
+public {{.Exporter.GetEntryClassName .Link.DstTable}} {{$methodName}}()
+{
+{{- if .Link.Field.Optional}}
+    if ({{$propName}} == null)
+    {
+        return null;
+    }
+{{- end}}
+{{- if .Link.Nullable}}
+    if ({{range $i, $v := .Link.NullLiterals}}{{if $i}} || {{end}}{{$propName}} == {{$v}}{{end}})
+    {
+        return null;
+    }
+{{- end}}
+    return {{.Exporter.GetTablesClassName}}.{{.Exporter.GetTablePropertyName .Link.DstTable}}.{{.Exporter.GenUniqueMethodName .Link.DstField}}({{.Exporter.GenOptionalValue .Link.Field.FieldTypeInfo $propName}});
+}`))
+
+// templateCSharpHasProperty C# 可选属性是否设置的属性模版。
+var templateCSharpHasProperty = template.Must(template.New("csharp_has_property").
+	Funcs(csharpTemplateFuncMap).
+	Parse(`{{$hasName := .Exporter.GenHasPropertyName .PropertyName -}}
+{{xmlDocBlock (printf "%s reports whether %s is set." $hasName .PropertyName)}}
+public bool {{$hasName}} => {{.PropertyName}} != null;`))
+
 // templateCSharpUniqueIndexField C# 唯一索引字段模版。
 var templateCSharpUniqueIndexField = template.Must(template.New("csharp_unique_index_field").
 	Funcs(csharpTemplateFuncMap).
@@ -459,6 +960,8 @@ var templateCSharpGlobalTableClass = template.Must(template.New("csharp_global_t
 {{- range .Table.Fields}}
 
 {{indent 4 ($.Exporter.GenGlobalTableProperty .)}}
+{{- if .Optional}}{{"\n\n"}}{{indent 4 ($.Exporter.GenHasProperty ($.Exporter.GetEntryFieldName .))}}{{end}}
+{{- if $.Exporter.IsTextField .Field}}{{"\n\n"}}{{indent 4 ($.Exporter.GenTextProperty ($.Exporter.GetEntryFieldName .))}}{{end}}
 {{- end}}
 
 {{indent 4 (.Exporter.GenGlobalTableLoadMethod .Table)}}
@@ -868,7 +1371,26 @@ internal static class LoadHelper
     /// <summary>
     /// jsonOptions centralizes serializer settings for all json table loads.
     /// </summary>
-    private static readonly global::System.Text.Json.JsonSerializerOptions jsonOptions = new();
+    private static readonly global::System.Text.Json.JsonSerializerOptions jsonOptions = new()
+    {
+        Converters = { new TimeSpanJsonConverter(){{range .Tuples}}, new {{$.Exporter.GenTupleName .}}JsonConverter(){{end}} },
+    };
+
+    /// <summary>
+    /// TimeSpanJsonConverter reads duration values exported as nanoseconds.
+    /// </summary>
+    private sealed class TimeSpanJsonConverter : global::System.Text.Json.Serialization.JsonConverter<global::System.TimeSpan>
+    {
+        public override global::System.TimeSpan Read(ref global::System.Text.Json.Utf8JsonReader reader, global::System.Type typeToConvert, global::System.Text.Json.JsonSerializerOptions options)
+        {
+            return global::System.TimeSpan.FromTicks(reader.GetInt64() / 100);
+        }
+
+        public override void Write(global::System.Text.Json.Utf8JsonWriter writer, global::System.TimeSpan value, global::System.Text.Json.JsonSerializerOptions options)
+        {
+            writer.WriteNumberValue(value.Ticks * 100);
+        }
+    }
 
     /// <summary>
     /// LoadTableAsync loads one table object from its json file.
@@ -908,7 +1430,18 @@ var templateCSharpBsonLoadHelperFile = template.Must(template.New("csharp_bson_l
 /// </summary>
 internal static class LoadHelper
 {
+{{- if .Tuples}}
     /// <summary>
+    /// LoadHelper registers the serializers of tuples, which are stored as arrays of elements.
+    /// </summary>
+    static LoadHelper()
+    {
+{{- range .Tuples}}
+        global::MongoDB.Bson.Serialization.BsonSerializer.TryRegisterSerializer(new {{$.Exporter.GenTupleName .}}BsonSerializer());
+{{- end}}
+    }
+
+{{end}}    /// <summary>
     /// LoadTableAsync loads one normal table collection from mongodb.
     /// </summary>
     public static async global::System.Threading.Tasks.Task<global::System.Collections.Generic.List<T>> LoadTableAsync<T>({{.Exporter.GenMongoDatabaseType}} db, string tableName)
@@ -1079,6 +1612,12 @@ internal static class LoadHelper
         }
         ordered.Sort((a, b) => a.MetadataToken.CompareTo(b.MetadataToken));
 
+        // Inherited properties of the base struct class come first.
+        if (type.BaseType != null && type.BaseType != typeof(object))
+        {
+            ordered.InsertRange(0, GetOrderedProperties(type.BaseType));
+        }
+
         var result = ordered.ToArray();
         lock (propertyCache)
         {
@@ -1105,6 +1644,11 @@ internal static class LoadHelper
     /// </summary>
     private static object DecodeValue(BytesReader reader, global::System.Type type)
     {
+        var underlyingType = global::System.Nullable.GetUnderlyingType(type);
+        if (underlyingType != null)
+        {
+            return DecodeValue(reader, underlyingType);
+        }
         if (type == typeof(int))
         {
             return reader.ReadVarint32();
@@ -1113,6 +1657,30 @@ internal static class LoadHelper
         {
             return reader.ReadVarint64();
         }
+        if (type == typeof(sbyte))
+        {
+            return checked((sbyte)reader.ReadVarint16());
+        }
+        if (type == typeof(short))
+        {
+            return reader.ReadVarint16();
+        }
+        if (type == typeof(byte))
+        {
+            return checked((byte)reader.ReadUvarint16());
+        }
+        if (type == typeof(ushort))
+        {
+            return reader.ReadUvarint16();
+        }
+        if (type == typeof(uint))
+        {
+            return reader.ReadUvarint32();
+        }
+        if (type == typeof(ulong))
+        {
+            return reader.ReadUvarint64();
+        }
         if (type == typeof(float))
         {
             return reader.ReadFloat32();
@@ -1129,6 +1697,34 @@ internal static class LoadHelper
         {
             return reader.ReadString();
         }
+        if (type == typeof(global::System.DateTime))
+        {
+            return global::System.DateTimeOffset.FromUnixTimeMilliseconds(reader.ReadVarint64()).UtcDateTime;
+        }
+        if (type == typeof(global::System.TimeSpan))
+        {
+            return global::System.TimeSpan.FromTicks(reader.ReadVarint64() / 100);
+        }
+{{- range .Decimals}}
+        if (type == typeof({{.}}))
+        {
+            return {{.}}.FromRaw(reader.ReadVarint64());
+        }
+{{- end}}
+{{- range .Unions}}{{$name := $.Exporter.GenUnionName .}}{{$caseType := $.Exporter.GenUnionCaseType .}}
+        if (type == typeof({{$name}}))
+        {
+            var value = {{$name}}.Create(({{$caseType}})DecodeValue(reader, typeof({{$caseType}})));
+            value.ValueObject = DecodeObject(reader, value.ValueType);
+            return value;
+        }
+{{- end}}
+{{- range .Tuples}}{{$type := $.Exporter.GenTupleType .}}
+        if (type == typeof({{$type}}))
+        {
+            return new {{$type}}({{range $i, $et := .GetTupleElements}}{{if $i}}, {{end}}({{$.Exporter.GenTypeInfo $et}})DecodeValue(reader, typeof({{$.Exporter.GenTypeInfo $et}})){{end}});
+        }
+{{- end}}
         if (type.IsEnum)
         {
             return global::System.Enum.ToObject(type, reader.ReadVarint32());
@@ -1312,6 +1908,26 @@ internal static class LoadHelper
             return result;
         }
 
+        public ushort ReadUvarint16()
+        {
+            var value = ReadUvarint64();
+            if (value > ushort.MaxValue)
+            {
+                throw new global::System.InvalidOperationException($"uvarint16[{value}] overflow");
+            }
+            return (ushort)value;
+        }
+
+        public uint ReadUvarint32()
+        {
+            var value = ReadUvarint64();
+            if (value > uint.MaxValue)
+            {
+                throw new global::System.InvalidOperationException($"uvarint32[{value}] overflow");
+            }
+            return (uint)value;
+        }
+
         public ulong ReadUvarint64()
         {
             ulong value = 0;
@@ -1502,20 +2118,50 @@ func (e *csharpExporter) GenProperty(typ, propName string, attrs []string, desc
 	})
 }
 
+// GenHasProperty 生成可选属性是否设置的属性文本。
+func (e *csharpExporter) GenHasProperty(propName string) string {
+	return executeCSharpTemplate("GenHasProperty", templateCSharpHasProperty, map[string]any{
+		"Exporter":     e,
+		"PropertyName": propName,
+	})
+}
+
+// GenTextProperty 生成文本属性查找文本的属性文本。
+func (e *csharpExporter) GenTextProperty(propName string) string {
+	return executeCSharpTemplate("GenTextProperty", templateCSharpTextProperty, map[string]any{
+		"Exporter":     e,
+		"PropertyName": propName,
+		"ClassName":    e.GetTextsClassName(),
+	})
+}
+
+// GenTextsFile 生成 C# 文本查找文件文本。
+func (e *csharpExporter) GenTextsFile() string {
+	return executeCSharpTemplate("GenTextsFile", templateCSharpTextsFile, map[string]any{
+		"Namespace":      e.kindOptions.Namespace,
+		"ClassName":      e.GetTextsClassName(),
+		"Json":           e.options.DataKind == export.DataJson,
+		"TextFilePrefix": strconv.Quote(export.TextFilePrefix + "."),
+	})
+}
+
 // GenStructProperty 生成结构体属性文本。
 func (e *csharpExporter) GenStructProperty(fd *gexcels.Field) string {
-	return e.GenProperty(e.GenTypeInfo(fd.FieldTypeInfo), e.GetFieldName(fd), e.GenPropertyAttributes(fd.Name, false), fd.Desc)
+	attributes := append(e.GenPropertyAttributes(fd.Name, false), e.GenTypeAttributes(fd.FieldTypeInfo)...)
+	return e.GenProperty(e.GenTypeInfo(fd.FieldTypeInfo), e.GetFieldName(fd), attributes, fieldComment(fd))
 }
 
 // GenEntryProperty 生成表项属性文本。
 func (e *csharpExporter) GenEntryProperty(fd *gexcels.TableField) string {
-	return e.GenProperty(e.GenTypeInfo(fd.FieldTypeInfo), e.GetEntryFieldName(fd), e.GenPropertyAttributes(e.GenNormalTableDataFieldName(fd), fd.Col == gexcels.TableColFieldID), fd.Desc)
+	attributes := append(e.GenPropertyAttributes(e.GenNormalTableDataFieldName(fd), fd.Col == gexcels.TableColFieldID), e.GenTypeAttributes(fd.FieldTypeInfo)...)
+	return e.GenProperty(e.GenTypeInfo(fd.FieldTypeInfo), e.GetEntryFieldName(fd), attributes, fieldComment(fd.Field))
 }
 
 // GenGlobalTableProperty 生成全局表属性文本。
 // 全局表字段不具备普通表 ID 语义，始终按字段原名生成序列化特性。
 func (e *csharpExporter) GenGlobalTableProperty(fd *gexcels.TableField) string {
-	return e.GenProperty(e.GenTypeInfo(fd.FieldTypeInfo), e.GetEntryFieldName(fd), e.GenPropertyAttributes(fd.Name, false), fd.Desc)
+	attributes := append(e.GenPropertyAttributes(fd.Name, false), e.GenTypeAttributes(fd.FieldTypeInfo)...)
+	return e.GenProperty(e.GenTypeInfo(fd.FieldTypeInfo), e.GetEntryFieldName(fd), attributes, fieldComment(fd.Field))
 }
 
 // renderCSharpFile 使用通用文件模版包装 using、namespace 和正文。
@@ -1576,6 +2222,56 @@ func (e *csharpExporter) GenEnumsFile() string {
 	})
 }
 
+// GenDecimal 生成小数位数为 scale 的 C# 定点小数类型文本。
+func (e *csharpExporter) GenDecimal(scale int) string {
+	return executeCSharpTemplate("GenDecimal", templateCSharpDecimal, map[string]any{
+		"Name":  e.GenDecimalName(scale),
+		"Scale": scale,
+		"One":   decimalOne(scale),
+		"Json":  e.options.DataKind == export.DataJson,
+		"Bson":  e.needsBsonAnnotations(),
+	})
+}
+
+// GenDecimalsFile 生成 C# 定点小数类型文件文本。
+func (e *csharpExporter) GenDecimalsFile(scales []int) string {
+	return executeCSharpTemplate("GenDecimalsFile", templateCSharpDecimalsFile, map[string]any{
+		"Exporter":  e,
+		"Namespace": e.kindOptions.Namespace,
+		"Scales":    scales,
+	})
+}
+
+// GenTuplesFile 生成 C# 元组类型文件文本, 包含类型定义与当前数据格式的转换器。
+func (e *csharpExporter) GenTuplesFile(tuples []*gexcels.FieldTypeInfo) string {
+	var blocks []string
+	for _, ti := range tuples {
+		if !e.isUnityTuple(ti) {
+			blocks = append(blocks, executeCSharpTemplate("GenTuplesFile", templateCSharpTuple, map[string]any{
+				"Exporter": e,
+				"Tuple":    ti,
+			}))
+		}
+	}
+	for _, ti := range tuples {
+		data := map[string]any{
+			"Exporter": e,
+			"Tuple":    ti,
+			"Name":     e.GenTupleName(ti),
+		}
+		switch e.options.DataKind {
+		case export.DataJson:
+			blocks = append(blocks, executeCSharpTemplate("GenTuplesFile", templateCSharpTupleJsonConverter, data))
+		case export.DataBson:
+			blocks = append(blocks, executeCSharpTemplate("GenTuplesFile", templateCSharpTupleBsonSerializer, data))
+		}
+	}
+	return executeCSharpTemplate("GenTuplesFile", templateCSharpTuplesFile, map[string]any{
+		"Namespace": e.kindOptions.Namespace,
+		"Blocks":    blocks,
+	})
+}
+
 // GenStruct 生成单个 C# 结构体文本。
 func (e *csharpExporter) GenStruct(sd *parse.Struct) string {
 	return executeCSharpTemplate("GenStruct", templateCSharpStruct, map[string]any{
@@ -1594,6 +2290,28 @@ func (e *csharpExporter) GenStructsFile() string {
 	})
 }
 
+// GenUnion 生成单个 C# 联合类型文本。
+func (e *csharpExporter) GenUnion(u *parse.Union) string {
+	return executeCSharpTemplate("GenUnion", templateCSharpUnion, map[string]any{
+		"Exporter":  e,
+		"Union":     u,
+		"Native":    e.canUseNativeEnum(e.parser.GetEnum(u.Enum)),
+		"TypeName":  export.UnionTypeName,
+		"ValueName": export.UnionValueName,
+		"Json":      e.options.DataKind == export.DataJson,
+		"Bson":      e.needsBsonAnnotations(),
+	})
+}
+
+// GenUnionsFile 生成 C# 联合类型文件文本。
+func (e *csharpExporter) GenUnionsFile() string {
+	return executeCSharpTemplate("GenUnionsFile", templateCSharpUnionsFile, map[string]any{
+		"Exporter":  e,
+		"Namespace": e.kindOptions.Namespace,
+		"Unions":    e.parser.Unions,
+	})
+}
+
 // GenTableFile 按表类型生成对应的 C# 表文件文本。
 func (e *csharpExporter) GenTableFile(td *parse.Table) string {
 	if td.IsGlobal {
@@ -1617,6 +2335,16 @@ func (e *csharpExporter) GenEntryClass(td *parse.Table) string {
 	return executeCSharpTemplate("GenEntryClass", templateCSharpEntryClass, map[string]any{
 		"Exporter": e,
 		"Table":    td,
+		"Links":    getLinkAccessors(e.parser, td),
+	})
+}
+
+// GenEntryLinkMethod 生成表项链接访问方法文本。
+func (e *csharpExporter) GenEntryLinkMethod(td *parse.Table, link *linkAccessor) string {
+	return executeCSharpTemplate("GenEntryLinkMethod", templateCSharpEntryLinkMethod, map[string]any{
+		"Exporter": e,
+		"Table":    td,
+		"Link":     link,
 	})
 }
 
@@ -1828,6 +2556,7 @@ func (e *csharpExporter) GenJsonLoadHelperFile() string {
 		"Exporter":    e,
 		"Namespace":   e.kindOptions.Namespace,
 		"UsingsBlock": "",
+		"Tuples":      tupleTypes(e.parser),
 	})
 }
 
@@ -1838,14 +2567,23 @@ func (e *csharpExporter) GenBsonLoadHelperFile() string {
 		"Namespace":            e.kindOptions.Namespace,
 		"UsingsBlock":          "",
 		"GlobalCollectionName": export.TableGlobalBsonCollName,
+		"Tuples":               tupleTypes(e.parser),
 	})
 }
 
 // GenBytesLoadHelperFile 生成 bytes LoadHelper 文件文本。
 func (e *csharpExporter) GenBytesLoadHelperFile() string {
+	scales := decimalScales(e.parser)
+	decimals := make([]string, len(scales))
+	for i, scale := range scales {
+		decimals[i] = e.GenDecimalName(scale)
+	}
 	return executeCSharpTemplate("GenBytesLoadHelperFile", templateCSharpBytesLoadHelperFile, map[string]any{
 		"Exporter":    e,
 		"Namespace":   e.kindOptions.Namespace,
 		"UsingsBlock": "",
+		"Decimals":    decimals,
+		"Unions":      e.parser.Unions,
+		"Tuples":      tupleTypes(e.parser),
 	})
 }
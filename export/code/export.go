@@ -4,8 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/godyy/gexcels"
 	"github.com/godyy/gexcels/export"
+	"github.com/godyy/gexcels/internal/utils"
 	"github.com/godyy/gexcels/parse"
 	pkg_errors "github.com/pkg/errors"
 )
@@ -56,6 +61,205 @@ func newExporterBase(parser *parse.Parser, path string, options *Options) export
 	}
 }
 
+// fieldComment 字段注释, 具备默认值时附加默认值
+func fieldComment(fd *gexcels.Field) string {
+	r := fd.GetFRDefault()
+	if r == nil {
+		return fd.Desc
+	}
+	if fd.Desc == "" {
+		return "(default: " + r.Value + ")"
+	}
+	return fd.Desc + " (default: " + r.Value + ")"
+}
+
+// decimalScales 获取结构体与配置表字段中用到的 decimal 小数位数, 升序排列.
+// 每个小数位数对应生成一个定点小数类型.
+func decimalScales(p *parse.Parser) []int {
+	used := make(map[int]bool)
+	var visit func(ti *gexcels.FieldTypeInfo)
+	visit = func(ti *gexcels.FieldTypeInfo) {
+		switch ti.Type {
+		case gexcels.FTDecimal:
+			used[ti.GetScale()] = true
+		case gexcels.FTTuple:
+			for _, et := range ti.GetTupleElements() {
+				visit(et)
+			}
+		case gexcels.FTArray:
+			visit(ti.GetElementType())
+		case gexcels.FTMap:
+			visit(ti.GetMapValueType())
+		}
+	}
+	for _, sd := range p.Structs {
+		for _, fd := range sd.Fields {
+			visit(fd.FieldTypeInfo)
+		}
+	}
+	for _, td := range p.Tables {
+		for _, fd := range td.Fields {
+			visit(fd.FieldTypeInfo)
+		}
+	}
+	scales := make([]int, 0, len(used))
+	for scale := range used {
+		scales = append(scales, scale)
+	}
+	slices.Sort(scales)
+	return scales
+}
+
+// decimalOne decimal 小数位数为 scale 时 1 的缩放值, e.g. 2 => 100
+func decimalOne(scale int) string {
+	return "1" + strings.Repeat("0", scale)
+}
+
+// tupleTypes 获取结构体与配置表字段中用到的元组类型, 按类型字符串排序.
+// 每个元组类型对应生成一个值类型.
+func tupleTypes(p *parse.Parser) []*gexcels.FieldTypeInfo {
+	used := make(map[string]*gexcels.FieldTypeInfo)
+	var visit func(ti *gexcels.FieldTypeInfo)
+	visit = func(ti *gexcels.FieldTypeInfo) {
+		switch ti.Type {
+		case gexcels.FTTuple:
+			used[ti.String()] = ti
+		case gexcels.FTArray:
+			visit(ti.GetElementType())
+		case gexcels.FTMap:
+			visit(ti.GetMapValueType())
+		}
+	}
+	for _, sd := range p.Structs {
+		for _, fd := range sd.Fields {
+			visit(fd.FieldTypeInfo)
+		}
+	}
+	for _, td := range p.Tables {
+		for _, fd := range td.Fields {
+			visit(fd.FieldTypeInfo)
+		}
+	}
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	types := make([]*gexcels.FieldTypeInfo, len(names))
+	for i, name := range names {
+		types[i] = used[name]
+	}
+	return types
+}
+
+// tupleName 元组类型名称, 内置元组为 Vec2, Vec3, Color; 通用元组为 Tuple + 元素类型名称, e.g. TupleInt32String.
+// enumName 返回枚举在代码中的名称.
+func tupleName(ti *gexcels.FieldTypeInfo, enumName func(name string) string) string {
+	if ti.GetName() != gexcels.TupleGeneric {
+		return utils.CamelCase(ti.GetName(), true)
+	}
+	var sb strings.Builder
+	sb.WriteString("Tuple")
+	for _, et := range ti.GetTupleElements() {
+		switch et.Type {
+		case gexcels.FTEnum:
+			sb.WriteString(enumName(et.GetName()))
+		case gexcels.FTDecimal:
+			sb.WriteString("Decimal" + strconv.Itoa(et.GetScale()))
+		default:
+			sb.WriteString(utils.CamelCase(et.Type.PrimitiveString(), true))
+		}
+	}
+	return sb.String()
+}
+
+// usesText 结构体与配置表字段中是否用到文本类型, 用到时生成文本查找代码
+func usesText(p *parse.Parser) bool {
+	var visit func(ti *gexcels.FieldTypeInfo) bool
+	visit = func(ti *gexcels.FieldTypeInfo) bool {
+		switch ti.Type {
+		case gexcels.FTText:
+			return true
+		case gexcels.FTArray:
+			return visit(ti.GetElementType())
+		case gexcels.FTMap:
+			return visit(ti.GetMapValueType())
+		default:
+			return false
+		}
+	}
+	for _, sd := range p.Structs {
+		for _, fd := range sd.Fields {
+			if visit(fd.FieldTypeInfo) {
+				return true
+			}
+		}
+	}
+	for _, td := range p.Tables {
+		for _, fd := range td.Fields {
+			if visit(fd.FieldTypeInfo) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// linkAccessor 条目链接访问方法, 通过字段值获取链接的目标条目
+type linkAccessor struct {
+	Field    *gexcels.TableField // 源字段
+	DstTable *parse.Table        // 目标配置表
+	DstField *gexcels.TableField // 目标字段, ID或者unique
+	Nullable *gexcels.FRNullable // 可空规则, 空值返回空
+}
+
+// NullLiterals 空值的字面量, 适用于 go 和 C#
+func (a *linkAccessor) NullLiterals() []string {
+	if a.Nullable == nil {
+		return nil
+	}
+	if len(a.Nullable.Values) == 0 {
+		if a.Field.Type == gexcels.FTString {
+			return []string{`""`}
+		}
+		return []string{"0"}
+	}
+	literals := make([]string, 0, len(a.Nullable.Values))
+	for _, v := range a.Nullable.Values {
+		if a.Field.Type == gexcels.FTString {
+			v = strconv.Quote(v)
+		}
+		literals = append(literals, v)
+	}
+	return literals
+}
+
+// getLinkAccessors 获取常规配置表 td 的链接访问方法, 仅支持链接值的 primitive 字段
+func getLinkAccessors(p *parse.Parser, td *parse.Table) []*linkAccessor {
+	var accessors []*linkAccessor
+	for _, fd := range td.Fields {
+		link := fd.GetFRLink()
+		if link == nil || link.Value == nil || !fd.Type.Primitive() {
+			continue
+		}
+		dstTable := p.GetTableByName(link.Value.TableName)
+		if dstTable == nil || dstTable.IsGlobal {
+			continue
+		}
+		dstField := dstTable.GetFieldByName(link.Value.FieldName)
+		if dstField == nil || !dstField.Unique() || dstField.Type != fd.Type {
+			continue
+		}
+		accessors = append(accessors, &linkAccessor{
+			Field:    fd,
+			DstTable: dstTable,
+			DstField: dstField,
+			Nullable: fd.GetFRNullable(),
+		})
+	}
+	return accessors
+}
+
 // creator 导出器构造函数
 type creator func(parser *parse.Parser, path string, options *Options, kindOptions kindOptions) (exporter, error)
 
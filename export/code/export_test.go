@@ -1,12 +1,16 @@
 package code
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/godyy/gexcels"
 	"github.com/godyy/gexcels/export"
+	"github.com/godyy/gexcels/export/data"
 	"github.com/godyy/gexcels/parse"
 )
 
@@ -24,6 +28,64 @@ func parseTestParser(t *testing.T) *parse.Parser {
 	return p
 }
 
+// parseTestSources 解析测试数据源
+func parseTestSources(t *testing.T, sources ...parse.Source) *parse.Parser {
+	t.Helper()
+
+	p, err := parse.ParseSources(sources, &parse.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// exportTestGo 导出go代码到临时目录, 返回目录路径
+func exportTestGo(t *testing.T, p *parse.Parser, dataKind export.DataKind) string {
+	t.Helper()
+
+	path := t.TempDir()
+	if err := ExportGo(p, path, &Options{DataKind: dataKind}, &GoOptions{PkgName: "test"}); err != nil {
+		t.Fatalf("export go to %s, %v", path, err)
+	}
+	return path
+}
+
+// testCSharpOptions 测试使用的csharp导出选项
+func testCSharpOptions() *CSharpOptions {
+	return &CSharpOptions{
+		Namespace:       "Test.Config",
+		TablesClassName: "ConfigTables",
+	}
+}
+
+// exportTestCSharp 导出csharp代码到临时目录, 返回目录路径
+func exportTestCSharp(t *testing.T, p *parse.Parser, dataKind export.DataKind, csharpOptions *CSharpOptions) string {
+	t.Helper()
+
+	path := t.TempDir()
+	if err := ExportCSharp(p, path, &Options{DataKind: dataKind}, csharpOptions); err != nil {
+		t.Fatalf("export csharp to %s, %v", path, err)
+	}
+	return path
+}
+
+// checkGeneratedFiles 检查生成的文件包含预期的代码片段, key 为文件路径
+func checkGeneratedFiles(t *testing.T, files map[string][]string) {
+	t.Helper()
+
+	for file, expected := range files {
+		code, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read generated file %s, %v", file, err)
+		}
+		for _, s := range expected {
+			if !strings.Contains(string(code), s) {
+				t.Fatalf("generated file %s missing %q:\n%s", file, s, code)
+			}
+		}
+	}
+}
+
 func TestExportGoJson(t *testing.T) {
 	exportGoPath := "../../internal/test/export/go_json"
 	p := parseTestParser(t)
@@ -242,3 +304,555 @@ func TestExportCSharpBytes(t *testing.T) {
 		t.Fatalf("generated csharp bytes tables file should use file-based load delegate")
 	}
 }
+
+func TestExportLinkAccessors(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "item|Item.csv", Reader: strings.NewReader("" +
+			"ID,Name\n" +
+			"id,name\n" +
+			"int32,string\n" +
+			",UNIQUE\n" +
+			",\n" +
+			"1,sword\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Weapon,ByName\n" +
+			"id,weapon,by name\n" +
+			"int32,int32,string\n" +
+			`,"LINK=Item.ID|NULLABLE=0,-1",LINK=Item.Name|NULLABLE` + "\n" +
+			",,\n" +
+			"1,-1,\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataJson)
+	csharpPath := exportTestCSharp(t, p, export.DataJson, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/Bag.go": {
+			"func (e *Bag) WeaponLink() *Item {\n\tif e.Weapon == 0 || e.Weapon == -1 {\n\t\treturn nil\n\t}",
+			"return t.ByID(e.Weapon)",
+			"if e.ByName == \"\" {",
+			"return t.ByName(e.ByName)",
+		},
+		csharpPath + "/bag.cs": {
+			"public Item WeaponLink()",
+			"if (Weapon == 0 || Weapon == -1)",
+			"return ConfigTables.Item.ById(Weapon);",
+			"if (ByName == \"\")",
+			"return ConfigTables.Item.ByName(ByName);",
+		},
+	})
+}
+
+func TestExportOptionalFields(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+			`,Drop,"Item:int32:""item"",Count:int32?:""count""",,drop` + "\n")},
+		parse.Source{Name: "item|Item.csv", Reader: strings.NewReader("" +
+			"ID,Name\n" +
+			"id,name\n" +
+			"int32,string\n" +
+			",\n" +
+			",\n" +
+			"1,sword\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Weapon,Name,Drop\n" +
+			"id,weapon,name,drop\n" +
+			"int32,int32?,string?,Drop\n" +
+			",LINK=Item.ID|NULLABLE,,\n" +
+			",,,\n" +
+			"1,,,\n" +
+			`2,1,x,"{""Item"":1}"` + "\n")},
+		parse.Source{Name: "全局|GlobalA.csv", Reader: strings.NewReader("" +
+			"\n" +
+			",MaxCount,int32?,,,max\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataBytes)
+	csharpPath := exportTestCSharp(t, p, export.DataBytes, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/Bag.go": {
+			"Weapon *int32",
+			"Name *string",
+			"func (e *Bag) HasWeapon() bool { return e.Weapon != nil }",
+			"func (e *Bag) HasName() bool { return e.Name != nil }",
+			"if e.Weapon == nil {\n\t\treturn nil\n\t}\n\tif *e.Weapon == 0 {",
+			"return t.ByID(*e.Weapon)",
+		},
+		goPath + "/GlobalA.go": {
+			"func (t *globalA) HasMaxCount() bool { return t.MaxCount != nil }",
+		},
+		goPath + "/test_structs.go": {
+			"func (s *Drop) HasCount() bool { return s.Count != nil }",
+		},
+		csharpPath + "/bag.cs": {
+			"public int? Weapon { get; set; }",
+			"public bool HasWeapon => Weapon != null;",
+			"public string Name { get; set; }",
+			"public bool HasName => Name != null;",
+			"if (Weapon == null)",
+			"return ConfigTables.Item.ById(Weapon.Value);",
+		},
+		csharpPath + "/global_a.cs": {
+			"public int? MaxCount { get; set; }",
+			"public bool HasMaxCount => MaxCount != null;",
+		},
+		csharpPath + "/test_config_structs.cs": {
+			"public int? Count { get; set; }",
+			"public bool HasCount => Count != null;",
+		},
+		csharpPath + "/test_config_load_helper.cs": {
+			"var underlyingType = global::System.Nullable.GetUnderlyingType(type);",
+		},
+	})
+}
+
+func TestExportFieldDefaults(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+			`,Drop,"Item:int32:""item"",Count:int32:""count""","DEFAULT=Count,5",drop` + "\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Count,Drop\n" +
+			"id,count,drop\n" +
+			"int32,int32,Drop\n" +
+			",DEFAULT=100,\n" +
+			",,\n" +
+			`1,,"{""Item"":1}"` + "\n")},
+		parse.Source{Name: "全局|GlobalA.csv", Reader: strings.NewReader("" +
+			"\n" +
+			",MaxCount,int32,,DEFAULT=10,\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataJson)
+	csharpPath := exportTestCSharp(t, p, export.DataJson, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/Bag.go":                     {"Count int32 `json:\"Count,omitempty\"` // count (default: 100)"},
+		goPath + "/GlobalA.go":                 {"MaxCount int32 `json:\"MaxCount,omitempty\"` // (default: 10)"},
+		goPath + "/test_structs.go":            {"Count int32 `json:\"Count,omitempty\"` // count (default: 5)"},
+		csharpPath + "/bag.cs":                 {"/// count (default: 100)"},
+		csharpPath + "/global_a.cs":            {"/// (default: 10)"},
+		csharpPath + "/test_config_structs.cs": {"/// count (default: 5)"},
+	})
+}
+
+func TestExportIntegerTypes(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,I8,I16,U8,U16,U32,U64,List,Map\n" +
+			"id,i8,i16,u8,u16,u32,u64,list,map\n" +
+			"uint16,int8,int16,uint8,uint16,uint32,uint64,[]uint8,map[uint32]int8\n" +
+			",,,,,,,,\n" +
+			",,,,,,,,\n" +
+			`1,-1,-1,1,1,1,1,"[1]","{""1"":1}"` + "\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataBytes)
+	csharpPath := exportTestCSharp(t, p, export.DataBytes, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/Bag.go": {
+			"ID uint16", "I8 int8", "I16 int16", "U8 uint8", "U16 uint16", "U32 uint32", "U64 uint64",
+			"List []uint8", "Map map[uint32]int8",
+		},
+		goPath + "/test_load_helper.go": {
+			"case reflect.Uint64: // FTUint64",
+		},
+		csharpPath + "/bag.cs": {
+			"public ushort Id { get; set; }", "public sbyte I8 { get; set; }", "public short I16 { get; set; }",
+			"public byte U8 { get; set; }", "public ushort U16 { get; set; }", "public uint U32 { get; set; }",
+			"public ulong U64 { get; set; }",
+		},
+		csharpPath + "/test_config_load_helper.cs": {
+			"if (type == typeof(ulong))",
+			"public uint ReadUvarint32()",
+		},
+	})
+}
+
+func TestExportTimeTypes(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Start,Cooldown,Optional,Durations\n" +
+			"id,start,cooldown,optional,durations\n" +
+			"int32,datetime,duration,datetime?,[]duration\n" +
+			",,,,\n" +
+			",,,,\n" +
+			`1,2024-01-02 08:00:00,1h30m,,"[""90s""]"` + "\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataBytes)
+	csharpPath := exportTestCSharp(t, p, export.DataBson, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/Bag.go": {
+			`import "time"`, "Start time.Time", "Cooldown time.Duration", "Optional *time.Time", "Durations []time.Duration",
+		},
+		goPath + "/test_load_helper.go": {
+			"time.UnixMilli(",
+		},
+		csharpPath + "/bag.cs": {
+			"public global::System.DateTime Start { get; set; }", "public global::System.TimeSpan Cooldown { get; set; }",
+			"public global::System.DateTime? Optional { get; set; }",
+			"[global::MongoDB.Bson.Serialization.Attributes.BsonTimeSpanOptionsAttribute(global::MongoDB.Bson.BsonType.Int64, global::MongoDB.Bson.Serialization.Options.TimeSpanUnits.Nanoseconds)]",
+		},
+	})
+}
+
+func TestExportDecimal(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Rate,Price,List\n" +
+			"id,rate,price,list\n" +
+			"int32,decimal(2),decimal(4)?,[]decimal(2)\n" +
+			",,,\n" +
+			",,,\n" +
+			`1,1.5,,"[""0.25""]"` + "\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataBytes)
+	csharpPath := exportTestCSharp(t, p, export.DataBytes, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/Bag.go": {
+			"Rate Decimal2", "Price *Decimal4", "List []Decimal2",
+		},
+		goPath + "/test_decimals.go": {
+			"type Decimal2 int64", "const Decimal2Scale = 2", "func Decimal2FromInt(i int64) Decimal2 { return Decimal2(i * 100) }",
+			"type Decimal4 int64", "func (d Decimal4) String() string",
+		},
+		csharpPath + "/bag.cs": {
+			"public Decimal2 Rate { get; set; }", "public Decimal4? Price { get; set; }",
+		},
+		csharpPath + "/test_config_decimals.cs": {
+			"public readonly struct Decimal2", "private const long One = 10000;",
+		},
+		csharpPath + "/test_config_load_helper.cs": {
+			"return Decimal4.FromRaw(reader.ReadVarint64());",
+		},
+	})
+}
+
+func TestExportFlagsEnum(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "枚举|EnumElement.tsv", Reader: strings.NewReader("" +
+			"\n" +
+			"\tElement_FLAGS\tint32\telement\n" +
+			"\tFire\t1\tfire\n" +
+			"\tPoison\t2\tpoison\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Element\n" +
+			"id,element\n" +
+			"int32,Element\n" +
+			",\n" +
+			",\n" +
+			"1,Fire|Poison\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataJson)
+	csharpPath := exportTestCSharp(t, p, export.DataJson, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/test_enums_other.go": {
+			"func (e Element) Has(flag Element) bool {", "range [...]Element{ElementFire, ElementPoison}",
+		},
+		csharpPath + "/test_config_enums.cs": {
+			"[global::System.Flags]\npublic enum Element : int",
+			"public static bool Has(this Element value, Element flag) => (value & flag) == flag;",
+		},
+	})
+}
+
+func TestExportUnion(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "枚举|EnumEffectType.tsv", Reader: strings.NewReader("" +
+			"\n" +
+			"\tEffectType_BEGIN\tint32\teffect type\n" +
+			"\tDamage\t1\tdamage\n" +
+			"\tHeal\t2\theal\n")},
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("" +
+			"\n" +
+			`,DamageEffect,"Amount:int32:""amount""",,damage` + "\n" +
+			`,Heal,"HP:int32:""hp""",,heal` + "\n" +
+			`,Effect,"union(EffectType):Damage=DamageEffect,Heal",,effect` + "\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Effect\n" +
+			"id,effect\n" +
+			"int32,Effect\n" +
+			",\n" +
+			",\n" +
+			`1,"Damage{Amount:10}"` + "\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataJson)
+	csharpPath := exportTestCSharp(t, p, export.DataBytes, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/Bag.go": {
+			"Effect *Effect",
+		},
+		goPath + "/test_unions.go": {
+			"type EffectVariant interface {", "func (*DamageEffect) EffectCase() EffectType { return EffectTypeDamage }",
+			"func (u *Effect) AsDamage() (*DamageEffect, bool) {", "func (u *Effect) UnmarshalJSON(data []byte) error {",
+		},
+		csharpPath + "/test_config_unions.cs": {
+			"public abstract class Effect", "public sealed class Damage : Effect",
+			"public global::Test.Config.DamageEffect Value { get; set; }",
+		},
+		csharpPath + "/test_config_load_helper.cs": {
+			"var value = Effect.Create((EffectType)DecodeValue(reader, typeof(EffectType)));",
+		},
+	})
+}
+
+func TestExportStructInherit(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("" +
+			"\n" +
+			`,Reward,"Count:int32:""count"",Note:string?",,reward` + "\n" +
+			`,ItemReward:Reward,"ItemID:int32:""item""",,item reward` + "\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Reward\n" +
+			"id,reward\n" +
+			"int32,ItemReward\n" +
+			",\n" +
+			",\n" +
+			`1,"{Count:1,ItemID:2}"` + "\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataBson)
+	csharpPath := exportTestCSharp(t, p, export.DataBytes, testCSharpOptions())
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/test_structs.go": {
+			"type ItemReward struct {\n\tReward `bson:\",inline\"` // reward\n\tItemID int32",
+			"func (s *Reward) HasNote() bool",
+		},
+		csharpPath + "/test_config_structs.cs": {
+			"public class Reward\n", "public sealed class ItemReward : Reward\n",
+		},
+		csharpPath + "/test_config_load_helper.cs": {
+			"ordered.InsertRange(0, GetOrderedProperties(type.BaseType));",
+		},
+	})
+	if code, _ := os.ReadFile(goPath + "/test_structs.go"); strings.Contains(string(code), "func (s *ItemReward) HasNote") {
+		t.Fatalf("inherited Has method generated:\n%s", code)
+	}
+}
+
+func TestExportTuple(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("" +
+			"\n" +
+			`,Spawn,"Pos:vec3:""pos"",Tint:color:""tint""",,spawn` + "\n")},
+		parse.Source{Name: "a|A.csv", Reader: strings.NewReader("" +
+			"ID,Path,Pair,Spawn\n" +
+			"id,path,pair,spawn\n" +
+			`int32,[]vec2,"tuple[int32,string]",Spawn` + "\n" +
+			",,,\n" +
+			",,,\n" +
+			`1,"1,2;3,4","7,seven","{Pos:[1,2,3]}"` + "\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataBytes)
+	csharpPath := exportTestCSharp(t, p, export.DataJson, &CSharpOptions{Namespace: "Test.Config"})
+	unityPath := exportTestCSharp(t, p, export.DataBytes, &CSharpOptions{Namespace: "Test.Config", UnityTypes: true})
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/test_tuples.go": {
+			"type Vec3 struct {\n\tX float32\n\tY float32\n\tZ float32\n}",
+			"type TupleInt32String struct {\n\tItem1 int32\n\tItem2 string\n}",
+			"func (*Color) tuple() {}",
+		},
+		goPath + "/test_structs.go": {"Pos Vec3", "Tint Color"},
+		goPath + "/A.go":            {"Path []Vec2", "Pair TupleInt32String"},
+		csharpPath + "/test_config_tuples.cs": {
+			"public readonly struct Vec3\n",
+			"public TupleInt32String(int item1, string item2)",
+			"internal sealed class Vec2JsonConverter : global::System.Text.Json.Serialization.JsonConverter<Vec2>",
+		},
+		csharpPath + "/test_config_load_helper.cs": {"new Vec2JsonConverter()", "new Vec3JsonConverter()"},
+		unityPath + "/test_config_structs.cs":      {"public global::UnityEngine.Vector3 Pos { get; set; }", "public global::UnityEngine.Color Tint { get; set; }"},
+		unityPath + "/test_config_load_helper.cs": {
+			"return new global::UnityEngine.Vector2((float)DecodeValue(reader, typeof(float)), (float)DecodeValue(reader, typeof(float)));",
+			"return new TupleInt32String((int)DecodeValue(reader, typeof(int)), (string)DecodeValue(reader, typeof(string)));",
+		},
+	})
+	if code, _ := os.ReadFile(unityPath + "/test_config_tuples.cs"); strings.Contains(string(code), "struct Vec3") {
+		t.Fatalf("unity mapped tuple generated:\n%s", code)
+	}
+}
+
+func TestExportText(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("" +
+			"\n" +
+			`,Reward,"Count:int32:""count"",Tips:text:""tips""",,reward` + "\n")},
+		parse.Source{Name: "全局|GlobalG.csv", Reader: strings.NewReader("" +
+			",Name,type,value,rule,desc\n" +
+			",Title,i18n,Hello,,title\n")},
+		parse.Source{Name: "a|A.csv", Reader: strings.NewReader("" +
+			"ID,Name,Tags,Reward\n" +
+			"id,name,tags,reward\n" +
+			"int32,text,[]text,Reward\n" +
+			",,,\n" +
+			",,,\n" +
+			`1,Sword,"[""a""]","{Count:1,Tips:""take it""}"` + "\n")},
+	)
+
+	goPath := exportTestGo(t, p, export.DataJson)
+	csharpPath := exportTestCSharp(t, p, export.DataJson, &CSharpOptions{Namespace: "Test.Config"})
+	checkGeneratedFiles(t, map[string][]string{
+		goPath + "/test_texts.go": {
+			"func SetTextLookup(lookup func(key string) string)",
+			"func Text(key string) string",
+			`path := filepath.Join(basePath, "texts."+lang+".json")`,
+		},
+		goPath + "/test_structs.go": {"Tips string", "func (s *Reward) TipsText() string { return Text(s.Tips) }"},
+		goPath + "/A.go":            {"Name string", "Tags []string", "func (e *A) NameText() string { return Text(e.Name) }"},
+		goPath + "/GlobalG.go":      {"func (t *globalG) TitleText() string { return Text(t.Title) }"},
+		csharpPath + "/test_config_texts.cs": {
+			"public static class Texts",
+			"public static string Get(string key)",
+			`var filePath = global::System.IO.Path.Combine(basePath, "texts." + lang + ".json");`,
+		},
+		csharpPath + "/test_config_structs.cs": {"public string TipsText => Texts.Get(Tips);"},
+		csharpPath + "/a.cs":                   {"public string NameText => Texts.Get(Name);"},
+	})
+	if code, _ := os.ReadFile(goPath + "/A.go"); strings.Contains(string(code), "TagsText") {
+		t.Fatalf("text method generated for array field:\n%s", code)
+	}
+}
+
+// goLoadMain 加载导出数据并执行测试代码的程序, 参数为生成代码所在目录名和测试代码
+const goLoadMain = `package main
+
+import (
+	"fmt"
+	"os"
+
+	cfg "github.com/godyy/gexcels/internal/test/export/%s/cfg"
+)
+
+func main() {
+	if err := cfg.Load(os.Args[1]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+%s
+}
+`
+
+// runGoLoad 导出go代码与数据, 编译运行加载数据并执行测试代码 body 的程序, 返回程序输出
+func runGoLoad(t *testing.T, p *parse.Parser, dataKind export.DataKind, body string) string {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skip building generated code in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	// 生成的代码需位于模块内, 以使用模块的依赖
+	if err := os.MkdirAll("../../internal/test/export", 0755); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.MkdirTemp("../../internal/test/export", "go_load_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	if err := ExportGo(p, filepath.Join(dir, "cfg"), &Options{DataKind: dataKind}, &GoOptions{PkgName: "cfg"}); err != nil {
+		t.Fatalf("export go to %s, %v", dir, err)
+	}
+	dataPath := filepath.Join(dir, "data")
+	exportData := data.ExportJson
+	if dataKind == export.DataBytes {
+		exportData = data.ExportBytes
+	}
+	if err := exportData(p, dataPath); err != nil {
+		t.Fatalf("export %s data to %s, %v", dataKind, dataPath, err)
+	}
+	main := fmt.Sprintf(goLoadMain, filepath.Base(dir), body)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	absDataPath, err := filepath.Abs(dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(goBin, "run", "./internal/test/export/"+filepath.Base(dir), absDataPath)
+	cmd.Dir = "../.."
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("run generated code, %v:\n%s", err, out)
+	}
+	return string(out)
+}
+
+func TestExportGoLoad(t *testing.T) {
+	p := parseTestSources(t,
+		parse.Source{Name: "枚举|EnumElement.tsv", Reader: strings.NewReader("" +
+			"\n" +
+			"\tElement_FLAGS\tint32\telement\n" +
+			"\tFire\t1\tfire\n" +
+			"\tPoison\t2\tpoison\n")},
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+			`,Drop,"Item:int32:""item"",Count:int32?:""count""",,drop` + "\n")},
+		parse.Source{Name: "item|Item.csv", Reader: strings.NewReader("" +
+			"ID,Name\n" +
+			"id,name\n" +
+			"int32,string\n" +
+			",\n" +
+			",\n" +
+			"1,sword\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Weapon,Rate,Element,Cooldown,Drop,Path\n" +
+			"id,weapon,rate,element,cooldown,drop,path\n" +
+			"int32,int32?,decimal(2),Element,duration,Drop,[]vec2\n" +
+			",LINK=Item.ID|NULLABLE,,,,,\n" +
+			",,,,,,\n" +
+			`1,,-0.05,,90s,"{""Item"":3}",` + "\n" +
+			`2,1,1.5,Fire|Poison,1h30m,"{""Item"":1,""Count"":2}","1,2;3.5,4"` + "\n")},
+		parse.Source{Name: "全局|GlobalA.csv", Reader: strings.NewReader("" +
+			"\n" +
+			",MaxCount,int32,7,,max\n" +
+			",Rate,decimal(3),0.125,,rate\n")},
+	)
+
+	const body = `	for _, e := range cfg.TblBag().All() {
+		fmt.Println(e.ID, e.HasWeapon(), e.WeaponLink() != nil, e.Rate, e.Element, e.Cooldown, e.Drop.Item, e.Drop.HasCount(), e.Path)
+	}
+	fmt.Println(*cfg.TblBag().ByID(2).Weapon, cfg.TblBag().ByID(2).WeaponLink().Name, *cfg.TblBag().ByID(2).Drop.Count)
+	fmt.Println(cfg.GlobalA().MaxCount, cfg.GlobalA().Rate)`
+	const expected = "" +
+		"1 false false -0.05  1m30s 3 false []\n" +
+		"2 true true 1.50 Fire|Poison 1h30m0s 1 true [{1 2} {3.5 4}]\n" +
+		"1 sword 2\n" +
+		"7 0.125\n"
+	for _, dataKind := range []export.DataKind{export.DataJson, export.DataBytes} {
+		t.Run(dataKind.String(), func(t *testing.T) {
+			if out := runGoLoad(t, p, dataKind, body); out != expected {
+				t.Fatalf("generated code output:\n%s\nexpected:\n%s", out, expected)
+			}
+		})
+	}
+}
+
+func TestExportGoLoadEmptyStruct(t *testing.T) {
+	// 未设置任何字段的结构体值需写入结束标记, 否则后续字段被当作结构体字段读取
+	p := parseTestSources(t,
+		parse.Source{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+			`,Tag,"Name:string?:""name""",,tag` + "\n")},
+		parse.Source{Name: "bag|Bag.csv", Reader: strings.NewReader("" +
+			"ID,Tag,Level\n" +
+			"id,tag,level\n" +
+			"int32,Tag,int32\n" +
+			",,\n" +
+			",,\n" +
+			"1,{},5\n" +
+			`2,"{""Name"":""x""}",6` + "\n")},
+	)
+
+	const body = `	for _, e := range cfg.TblBag().All() {
+		fmt.Println(e.ID, e.Tag.HasName(), e.Level)
+	}`
+	const expected = "1 false 5\n2 true 6\n"
+	if out := runGoLoad(t, p, export.DataBytes, body); out != expected {
+		t.Fatalf("generated code output:\n%s\nexpected:\n%s", out, expected)
+	}
+}
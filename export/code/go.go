@@ -77,10 +77,26 @@ func (e *goExporter) export() error {
 		return err
 	}
 
+	if err := e.exportDecimalsFile(); err != nil {
+		return err
+	}
+
+	if err := e.exportTuplesFile(); err != nil {
+		return err
+	}
+
+	if err := e.exportTextsFile(); err != nil {
+		return err
+	}
+
 	if err := e.exportStructsFile(); err != nil {
 		return err
 	}
 
+	if err := e.exportUnionsFile(); err != nil {
+		return err
+	}
+
 	if err := e.exportTableFiles(); err != nil {
 		return err
 	}
@@ -122,6 +138,56 @@ func (e *goExporter) exportEnumsFile() error {
 	return nil
 }
 
+// exportDecimalsFile 导出定点小数类型文件
+func (e *goExporter) exportDecimalsFile() error {
+	scales := decimalScales(e.parser)
+	if len(scales) == 0 {
+		return nil
+	}
+
+	content := e.GenDecimalsFile(scales)
+	filePath := filepath.Join(e.path, e.kindOptions.PkgName+"_decimals.go")
+	if err := os.WriteFile(filePath, ([]byte)(content), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: go: decimals to [%s]", filePath)
+	}
+
+	log.PrintfGreen("export code: go: decimals to [%s]", filePath)
+	return nil
+}
+
+// exportTuplesFile 导出元组类型文件
+func (e *goExporter) exportTuplesFile() error {
+	tuples := tupleTypes(e.parser)
+	if len(tuples) == 0 {
+		return nil
+	}
+
+	content := e.GenTuplesFile(tuples)
+	filePath := filepath.Join(e.path, e.kindOptions.PkgName+"_tuples.go")
+	if err := os.WriteFile(filePath, ([]byte)(content), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: go: tuples to [%s]", filePath)
+	}
+
+	log.PrintfGreen("export code: go: tuples to [%s]", filePath)
+	return nil
+}
+
+// exportTextsFile 导出文本查找文件
+func (e *goExporter) exportTextsFile() error {
+	if !usesText(e.parser) {
+		return nil
+	}
+
+	content := e.GenTextsFile()
+	filePath := filepath.Join(e.path, e.kindOptions.PkgName+"_texts.go")
+	if err := os.WriteFile(filePath, ([]byte)(content), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: go: texts to [%s]", filePath)
+	}
+
+	log.PrintfGreen("export code: go: texts to [%s]", filePath)
+	return nil
+}
+
 // exportStructsFile 将结构体定义导出为go代码文件
 func (e *goExporter) exportStructsFile() error {
 	structs := e.parser.Structs
@@ -139,6 +205,22 @@ func (e *goExporter) exportStructsFile() error {
 	return nil
 }
 
+// exportUnionsFile 将联合类型定义导出为go代码文件
+func (e *goExporter) exportUnionsFile() error {
+	if len(e.parser.Unions) == 0 {
+		return nil
+	}
+
+	content := e.GenUnionsFile()
+	filePath := filepath.Join(e.path, e.kindOptions.PkgName+"_unions.go")
+	if err := os.WriteFile(filePath, ([]byte)(content), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "export code: go: unions to [%s]", filePath)
+	}
+
+	log.PrintfGreen("export code: go: unions to [%s]", filePath)
+	return nil
+}
+
 // exportTableFiles 将所有配置表定义导出为go代码文件
 func (e *goExporter) exportTableFiles() error {
 	for _, table := range e.parser.Tables {
@@ -449,11 +531,59 @@ func (e *goExporter) GenEnumStringsVarName(enum *parse.Enum) string {
 	return utils.CamelCase(enum.Name, false) + "Strings"
 }
 
+// GenDecimalName 生成定点小数类型名称
+func (e *goExporter) GenDecimalName(scale int) string {
+	return fmt.Sprintf("Decimal%d", scale)
+}
+
+// GenDecimalOne 生成定点小数类型中 1 的缩放值
+func (e *goExporter) GenDecimalOne(scale int) string {
+	return decimalOne(scale)
+}
+
+// GenTupleName 生成元组类型名称
+func (e *goExporter) GenTupleName(ti *gexcels.FieldTypeInfo) string {
+	return tupleName(ti, func(name string) string {
+		return e.GetEnumName(e.parser.GetEnum(name))
+	})
+}
+
 // GenStructName 生成结构体名称
 func (e *goExporter) GenStructName(sd *parse.Struct) string {
 	return utils.CamelCase(sd.Name, true)
 }
 
+// GenUnionName 生成联合类型名称
+func (e *goExporter) GenUnionName(u *parse.Union) string {
+	return utils.CamelCase(u.Name, true)
+}
+
+// GenUnionVariantName 生成联合类型分支接口名称
+func (e *goExporter) GenUnionVariantName(u *parse.Union) string {
+	return e.GenUnionName(u) + "Variant"
+}
+
+// GenUnionCaseMethodName 生成分支结构体返回所属分支的方法名称
+func (e *goExporter) GenUnionCaseMethodName(u *parse.Union) string {
+	return e.GenUnionName(u) + "Case"
+}
+
+// GenUnionEnumName 生成联合类型判别枚举名称
+func (e *goExporter) GenUnionEnumName(u *parse.Union) string {
+	return e.GetEnumName(e.parser.GetEnum(u.Enum))
+}
+
+// GenUnionCaseItemName 生成联合类型分支对应的枚举项名称
+func (e *goExporter) GenUnionCaseItemName(u *parse.Union, c *gexcels.UnionCase) string {
+	enum := e.parser.GetEnum(u.Enum)
+	return e.GenEnumItemName(enum, enum.GetItemByName(c.Name).Index)
+}
+
+// GenUnionCaseStructName 生成联合类型分支结构体名称
+func (e *goExporter) GenUnionCaseStructName(c *gexcels.UnionCase) string {
+	return e.GetStructName(e.parser.GetStructByName(c.Struct))
+}
+
 // GenTableName 生成表名称
 func (e *goExporter) GenTableName(td *parse.Table) string {
 	return td.Name
@@ -505,14 +635,30 @@ func (e *goExporter) GenPrimitiveFieldType(t gexcels.FieldType) string {
 		return "int32"
 	case gexcels.FTInt64:
 		return "int64"
+	case gexcels.FTInt8:
+		return "int8"
+	case gexcels.FTInt16:
+		return "int16"
+	case gexcels.FTUint8:
+		return "uint8"
+	case gexcels.FTUint16:
+		return "uint16"
+	case gexcels.FTUint32:
+		return "uint32"
+	case gexcels.FTUint64:
+		return "uint64"
 	case gexcels.FTFloat32:
 		return "float32"
 	case gexcels.FTFloat64:
 		return "float64"
 	case gexcels.FTBool:
 		return "bool"
-	case gexcels.FTString:
+	case gexcels.FTString, gexcels.FTText:
 		return "string"
+	case gexcels.FTDatetime:
+		return "time.Time"
+	case gexcels.FTDuration:
+		return "time.Duration"
 	default:
 		return ""
 	}
@@ -526,13 +672,20 @@ func (e *goExporter) genTypeInfo(ti *gexcels.FieldTypeInfo) string {
 	}
 
 	switch ti.Type {
-	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString:
-		return e.GenPrimitiveFieldType(ti.Type)
+	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64,
+		gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString, gexcels.FTText, gexcels.FTDatetime, gexcels.FTDuration:
+		return e.genOptionalType(ti, e.GenPrimitiveFieldType(ti.Type))
+	case gexcels.FTDecimal:
+		return e.genOptionalType(ti, e.GenDecimalName(ti.GetScale()))
 	case gexcels.FTEnum:
-		return e.GetEnumName(e.parser.GetEnum(ti.GetName()))
+		return e.genOptionalType(ti, e.GetEnumName(e.parser.GetEnum(ti.GetName())))
 	case gexcels.FTStruct:
 		sd := e.parser.GetStructByName(ti.GetName())
 		return "*" + e.GetStructName(sd)
+	case gexcels.FTUnion:
+		return "*" + e.GenUnionName(e.parser.GetUnionByName(ti.GetName()))
+	case gexcels.FTTuple:
+		return e.GenTupleName(ti)
 	case gexcels.FTArray:
 		return "[]" + e.genTypeInfo(ti.GetElementType())
 	case gexcels.FTMap:
@@ -542,6 +695,14 @@ func (e *goExporter) genTypeInfo(ti *gexcels.FieldTypeInfo) string {
 	}
 }
 
+// genOptionalType 可选类型用指针表示, nil 表示未设置
+func (e *goExporter) genOptionalType(ti *gexcels.FieldTypeInfo, typ string) string {
+	if ti.Optional {
+		return "*" + typ
+	}
+	return typ
+}
+
 // GenFieldType 生成字段类型
 func (e *goExporter) GenFieldType(fd *gexcels.Field) string {
 	return e.genTypeInfo(fd.FieldTypeInfo)
@@ -579,6 +740,96 @@ func (e *goExporter) GenEntryFieldTag(fd *gexcels.TableField) string {
 	}
 }
 
+// typeUsesTime 返回类型是否包含 datetime 或 duration
+func typeUsesTime(ti *gexcels.FieldTypeInfo) bool {
+	switch ti.Type {
+	case gexcels.FTDatetime, gexcels.FTDuration:
+		return true
+	case gexcels.FTArray:
+		return typeUsesTime(ti.GetElementType())
+	case gexcels.FTMap:
+		return typeUsesTime(ti.GetMapValueType())
+	default:
+		return false
+	}
+}
+
+// genTimeImport 字段包含 datetime 或 duration 时生成 time 包的 import 文本
+func genTimeImport(types []*gexcels.FieldTypeInfo) string {
+	for _, ti := range types {
+		if typeUsesTime(ti) {
+			return "import \"time\"\n\n"
+		}
+	}
+	return ""
+}
+
+// GenStructsImports 生成结构体文件的 import 文本
+func (e *goExporter) GenStructsImports() string {
+	var types []*gexcels.FieldTypeInfo
+	for _, sd := range e.parser.Structs {
+		for _, fd := range sd.Fields {
+			types = append(types, fd.FieldTypeInfo)
+		}
+	}
+	return genTimeImport(types)
+}
+
+// GenTupleElementType 生成元组元素类型
+func (e *goExporter) GenTupleElementType(et *gexcels.FieldTypeInfo) string {
+	return e.genTypeInfo(et)
+}
+
+// GenTuplesImports 生成元组类型文件的 import 文本
+func (e *goExporter) GenTuplesImports(tuples []*gexcels.FieldTypeInfo) string {
+	var imports []string
+	switch e.options.DataKind {
+	case export.DataJson:
+		imports = append(imports, `"encoding/json"`, `"fmt"`)
+	case export.DataBson:
+		imports = append(imports, `"fmt"`)
+	}
+	for _, ti := range tuples {
+		if genTimeImport(ti.GetTupleElements()) != "" {
+			imports = append(imports, `"time"`)
+			break
+		}
+	}
+	if e.options.DataKind == export.DataBson {
+		imports = append(imports, "", `"go.mongodb.org/mongo-driver/v2/bson"`)
+	}
+	switch len(imports) {
+	case 0:
+		return ""
+	case 1:
+		return "import " + imports[0] + "\n\n"
+	}
+	var sb strings.Builder
+	sb.WriteString("import (\n")
+	for _, imp := range imports {
+		if imp != "" {
+			sb.WriteString("\t" + imp)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(")\n\n")
+	return sb.String()
+}
+
+// GenTableImports 生成配置表文件的 import 文本
+func (e *goExporter) GenTableImports(td *parse.Table) string {
+	types := make([]*gexcels.FieldTypeInfo, len(td.Fields))
+	for i, fd := range td.Fields {
+		types[i] = fd.FieldTypeInfo
+	}
+	return genTimeImport(types)
+}
+
+// GenFieldComment 生成字段注释
+func (e *goExporter) GenFieldComment(fd *gexcels.Field) string {
+	return fieldComment(fd)
+}
+
 // GenStructField 生成结构体字段
 func (e *goExporter) GenStructField(fd *gexcels.Field) string {
 	var sb strings.Builder
@@ -589,7 +840,18 @@ func (e *goExporter) GenStructField(fd *gexcels.Field) string {
 	if fieldTag := e.GenStructFieldTag(fd); fieldTag != "" {
 		sb.WriteString(fieldTag)
 	}
-	sb.WriteString(" // " + fd.Desc)
+	sb.WriteString(" // " + e.GenFieldComment(fd))
+	return sb.String()
+}
+
+// GenStructParentField 生成嵌入的父结构体字段
+func (e *goExporter) GenStructParentField(sd *parse.Struct) string {
+	var sb strings.Builder
+	sb.WriteString(e.GetStructName(e.parser.GetStructByName(sd.Parent.Name)))
+	if e.options.DataKind == export.DataBson {
+		sb.WriteString(" `bson:\",inline\"`")
+	}
+	sb.WriteString(" // " + sd.Parent.Desc)
 	return sb.String()
 }
 
@@ -598,6 +860,16 @@ func (e *goExporter) GenTableStructField(fd *gexcels.TableField) string {
 	return e.GenStructField(fd.Field)
 }
 
+// GenHasMethodName 生成可选字段是否设置的方法名称
+func (e *goExporter) GenHasMethodName(fieldName string) string {
+	return "Has" + fieldName
+}
+
+// GenTextMethodName 生成文本字段查找文本的方法名称
+func (e *goExporter) GenTextMethodName(fieldName string) string {
+	return fieldName + "Text"
+}
+
 // GenTableUniqueKeyFieldName 生成表唯一键字段名称
 func (e *goExporter) GenTableUniqueKeyFieldName(fd *gexcels.TableField) string {
 	return "by" + e.GetEntryFieldName(fd)
@@ -608,6 +880,11 @@ func (e *goExporter) GenTableUniqueKeyMethodName(fd *gexcels.TableField) string
 	return "By" + e.GenEntryFieldName(fd)
 }
 
+// GenEntryLinkMethodName 生成条目链接访问方法名称
+func (e *goExporter) GenEntryLinkMethodName(fd *gexcels.TableField) string {
+	return e.GetEntryFieldName(fd) + "Link"
+}
+
 // GenTableCompositeKeyFieldName 生成表组合键字段名称
 func (e *goExporter) GenTableCompositeKeyFieldName(ck *parse.TableCompositeKey) string {
 	var sb strings.Builder
@@ -44,9 +44,32 @@ var templateGoEnumOther = template.Must(template.New("go_enum_other").
 {{- end}}
 }
 
+{{if .Enum.Flags -}}
+// Has reports whether all bits of flag are set in e
+func (e {{.Exporter.GetEnumName .Enum}}) Has(flag {{.Exporter.GetEnumName .Enum}}) bool {
+	return e&flag == flag
+}
+
+func (e {{.Exporter.GetEnumName .Enum}}) String() string {
+	if s, ok := {{.Exporter.GenEnumStringsVarName .Enum}}[e]; ok {
+		return s
+	}
+	s := ""
+	for _, flag := range [...]{{.Exporter.GetEnumName .Enum}}{ {{- range $index, $item := .Enum.Items}}{{if $index}}, {{end}}{{$.Exporter.GenEnumItemName $.Enum $index}}{{end -}} } {
+		if flag != 0 && e.Has(flag) {
+			if s != "" {
+				s += "|"
+			}
+			s += {{.Exporter.GenEnumStringsVarName .Enum}}[flag]
+		}
+	}
+	return s
+}
+{{else -}}
 func (e {{.Exporter.GetEnumName .Enum}}) String() string {
 	return {{.Exporter.GenEnumStringsVarName .Enum}}[e]
 }
+{{end -}}
 `))
 
 // GenEnumOther 生成枚举其他文本
@@ -113,10 +136,266 @@ func (e *goExporter) GenEnumsOtherFile() string {
 	return sb.String()
 }
 
+// templateGoDecimal go定点小数类型模版
+var templateGoDecimal = template.Must(template.New("go_decimal").
+	Parse(`{{$name := .Exporter.GenDecimalName .Scale -}}
+// {{$name}} fixed-point decimal with {{.Scale}} decimal places, stored as value * {{.One}}
+type {{$name}} int64
+
+// {{$name}}Scale number of decimal places of {{$name}}
+const {{$name}}Scale = {{.Scale}}
+
+// {{$name}}FromRaw returns the {{$name}} of scaled integer raw
+func {{$name}}FromRaw(raw int64) {{$name}} { return {{$name}}(raw) }
+
+// {{$name}}FromInt returns the {{$name}} of integer i
+func {{$name}}FromInt(i int64) {{$name}} { return {{$name}}(i * {{.One}}) }
+
+// Raw returns the scaled integer
+func (d {{$name}}) Raw() int64 { return int64(d) }
+
+// Int returns the integer part, truncated toward zero
+func (d {{$name}}) Int() int64 { return int64(d) / {{.One}} }
+
+// Float64 returns the float64 value, which is not deterministic across platforms
+func (d {{$name}}) Float64() float64 { return float64(d) / {{.One}} }
+
+// String returns the decimal string with {{.Scale}} decimal places
+func (d {{$name}}) String() string { return formatDecimal(int64(d), {{$name}}Scale) }
+`))
+
+// GenDecimal 生成定点小数类型文本
+func (e *goExporter) GenDecimal(scale int) string {
+	var sb strings.Builder
+	if err := templateGoDecimal.Execute(&sb, map[string]any{
+		"Exporter": e,
+		"Scale":    scale,
+		"One":      e.GenDecimalOne(scale),
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: GenDecimal"))
+	}
+	return sb.String()
+}
+
+// templateGoDecimalsFile go定点小数类型文件模版
+var templateGoDecimalsFile = template.Must(template.New("go_decimals_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+package {{.PkgName}}
+
+import "strconv"
+
+{{range .Scales -}}
+{{$.Exporter.GenDecimal .}}
+{{end -}}
+// formatDecimal formats scaled integer raw as a decimal string with scale decimal places
+func formatDecimal(raw int64, scale int) string {
+	u := uint64(raw)
+	if raw < 0 {
+		u = -u
+	}
+	s := strconv.FormatUint(u, 10)
+	if scale > 0 {
+		for len(s) <= scale {
+			s = "0" + s
+		}
+		s = s[:len(s)-scale] + "." + s[len(s)-scale:]
+	}
+	if raw < 0 {
+		s = "-" + s
+	}
+	return s
+}
+`))
+
+// GenDecimalsFile 生成定点小数类型文件文本
+func (e *goExporter) GenDecimalsFile(scales []int) string {
+	var sb strings.Builder
+	if err := templateGoDecimalsFile.Execute(&sb, map[string]any{
+		"Exporter": e,
+		"Scales":   scales,
+		"PkgName":  e.kindOptions.PkgName,
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: GenDecimalsFile"))
+	}
+	return sb.String()
+}
+
+// templateGoTextsFile go文本查找文件模版
+var templateGoTextsFile = template.Must(template.New("go_texts_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+package {{.PkgName}}
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// textLookup function used to look up localized text by key
+var textLookup atomic.Pointer[func(key string) string]
+
+// SetTextLookup sets the function used to look up localized text by key, nil to reset
+func SetTextLookup(lookup func(key string) string) {
+	if lookup == nil {
+		textLookup.Store(nil)
+		return
+	}
+	textLookup.Store(&lookup)
+}
+
+// Text returns the localized text of key, returns key itself when key is empty or no lookup function set
+func Text(key string) string {
+	lookup := textLookup.Load()
+	if key == "" || lookup == nil {
+		return key
+	}
+	return (*lookup)(key)
+}
+
+// LoadTexts loads the string table of lang in basePath and uses it to look up localized text,
+// returns key itself when the text of key is missing or empty
+func LoadTexts(basePath string, lang string) error {
+	path := filepath.Join(basePath, "{{.TextFilePrefix}}."+lang+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pkgerrors.WithMessagef(err, "load texts %s", lang)
+	}
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return pkgerrors.WithMessagef(err, "load texts %s", lang)
+	}
+	SetTextLookup(func(key string) string {
+		if s := texts[key]; s != "" {
+			return s
+		}
+		return key
+	})
+	return nil
+}
+`))
+
+// GenTextsFile 生成文本查找文件文本
+func (e *goExporter) GenTextsFile() string {
+	var sb strings.Builder
+	if err := templateGoTextsFile.Execute(&sb, map[string]any{
+		"TextFilePrefix": export.TextFilePrefix,
+		"PkgName":        e.kindOptions.PkgName,
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: GenTextsFile"))
+	}
+	return sb.String()
+}
+
+// templateGoTuple go元组类型模版
+var templateGoTuple = template.Must(template.New("go_tuple").
+	Parse(`{{$name := .Exporter.GenTupleName .Tuple -}}
+{{$names := .Tuple.GetTupleElementNames -}}
+{{$elems := .Tuple.GetTupleElements -}}
+// {{$name}} value of {{.Tuple}}, the elements are encoded in order
+type {{$name}} struct {
+{{- range $i, $et := $elems}}
+	{{index $names $i}} {{$.Exporter.GenTupleElementType $et}}
+{{- end}}
+}
+{{if eq .DataKind "json"}}
+// UnmarshalJSON implements json.Unmarshaler, the data is an array of elements
+func (t *{{$name}}) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+	if len(raw) != {{len $elems}} {
+		return fmt.Errorf("{{$name}}: element number %d invalid", len(raw))
+	}
+{{- range $i, $et := $elems}}
+	if err := json.Unmarshal(raw[{{$i}}], &t.{{index $names $i}}); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+{{else if eq .DataKind "bson"}}
+// UnmarshalBSONValue implements bson.ValueUnmarshaler, the value is an array of elements
+func (t *{{$name}}) UnmarshalBSONValue(typ byte, data []byte) error {
+	if bson.Type(typ) == bson.TypeNull {
+		return nil
+	}
+	arr, ok := bson.RawValue{Type: bson.Type(typ), Value: data}.ArrayOK()
+	if !ok {
+		return fmt.Errorf("{{$name}}: bson type %v invalid", bson.Type(typ))
+	}
+	values, err := arr.Values()
+	if err != nil {
+		return err
+	}
+	if len(values) != {{len $elems}} {
+		return fmt.Errorf("{{$name}}: element number %d invalid", len(values))
+	}
+{{- range $i, $et := $elems}}
+	if err := values[{{$i}}].Unmarshal(&t.{{index $names $i}}); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+{{else}}
+// tuple marks {{$name}} as a tuple
+func (*{{$name}}) tuple() {}
+{{end}}`))
+
+// GenTuple 生成元组类型文本
+func (e *goExporter) GenTuple(ti *gexcels.FieldTypeInfo) string {
+	var sb strings.Builder
+	if err := templateGoTuple.Execute(&sb, map[string]any{
+		"Exporter": e,
+		"Tuple":    ti,
+		"DataKind": e.options.DataKind.String(),
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: GenTuple"))
+	}
+	return sb.String()
+}
+
+// templateGoTuplesFile go元组类型文件模版
+var templateGoTuplesFile = template.Must(template.New("go_tuples_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+package {{.PkgName}}
+
+{{.Exporter.GenTuplesImports .Tuples}}{{range $index, $tuple := .Tuples -}}
+{{if $index}}{{"\n"}}{{end -}}
+{{$.Exporter.GenTuple $tuple}}{{end}}`))
+
+// GenTuplesFile 生成元组类型文件文本
+func (e *goExporter) GenTuplesFile(tuples []*gexcels.FieldTypeInfo) string {
+	var sb strings.Builder
+	if err := templateGoTuplesFile.Execute(&sb, map[string]any{
+		"Exporter": e,
+		"Tuples":   tuples,
+		"PkgName":  e.kindOptions.PkgName,
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: GenTuplesFile"))
+	}
+	return sb.String()
+}
+
 // templateGoStruct go结构体模版
 var templateGoStruct = template.Must(template.New("go_struct").
 	Parse(`type {{.Exporter.GetStructName .Struct}} struct {
-{{range $index,$field := .Struct.Fields -}}
+{{if .Struct.Parent}}{{"\t"}}{{.Exporter.GenStructParentField .Struct}}
+{{end -}}
+{{range $index,$field := .Struct.OwnFields -}}
 {{"\t"}}{{$.Exporter.GenStructField $field}}
 {{end}}}`))
 
@@ -139,11 +418,11 @@ var templateGoStructsFile = template.Must(template.New("go_structs_file").
 
 package {{.PkgName}}
 
-{{range $index, $struct := .Structs -}}
+{{.Exporter.GenStructsImports}}{{range $index, $struct := .Structs -}}
 {{$structName := $.Exporter.GetStructName $struct -}}
 {{if $index}}{{"\n"}}{{end -}}
 // {{$structName}} {{$struct.Desc}}
-{{$.Exporter.GenStruct $struct}}
+{{$.Exporter.GenStruct $struct}}{{$.Exporter.GenStructHasMethods $struct}}{{$.Exporter.GenStructTextMethods $struct}}
 {{end}}`))
 
 // GenStructsFile 生成go结构体文件文本
@@ -159,11 +438,159 @@ func (e *goExporter) GenStructsFile() string {
 	return sb.String()
 }
 
+// templateGoUnion go联合类型模版
+var templateGoUnion = template.Must(template.New("go_union").
+	Parse(`{{$name := .Exporter.GenUnionName .Union -}}
+{{$variant := .Exporter.GenUnionVariantName .Union -}}
+{{$caseMethod := .Exporter.GenUnionCaseMethodName .Union -}}
+{{$enum := .Exporter.GenUnionEnumName .Union -}}
+// {{$variant}} variant of {{$name}}, implemented by the case structs
+type {{$variant}} interface {
+	{{$caseMethod}}() {{$enum}}
+}
+
+// {{$name}} {{.Union.Desc}}
+type {{$name}} struct {
+	Variant {{$variant}} // variant of the selected case
+}
+{{range .Union.Cases}}
+// {{$caseMethod}} returns the case of {{$name}} which {{$.Exporter.GenUnionCaseStructName .}} belongs to
+func (*{{$.Exporter.GenUnionCaseStructName .}}) {{$caseMethod}}() {{$enum}} { return {{$.Exporter.GenUnionCaseItemName $.Union .}} }
+{{end}}
+// Case returns the case of the selected variant
+func (u *{{$name}}) Case() {{$enum}} {
+	if u == nil || u.Variant == nil {
+		var c {{$enum}}
+		return c
+	}
+	return u.Variant.{{$caseMethod}}()
+}
+{{range .Union.Cases}}{{$struct := $.Exporter.GenUnionCaseStructName .}}
+// As{{.Name}} returns the variant if the case is {{.Name}}
+func (u *{{$name}}) As{{.Name}}() (*{{$struct}}, bool) {
+	if u == nil {
+		return nil, false
+	}
+	v, ok := u.Variant.(*{{$struct}})
+	return v, ok
+}
+{{end}}
+// newVariant creates the variant of case c
+func (u *{{$name}}) newVariant(c {{$enum}}) ({{$variant}}, error) {
+	switch c {
+{{- range .Union.Cases}}
+	case {{$.Exporter.GenUnionCaseItemName $.Union .}}:
+		return new({{$.Exporter.GenUnionCaseStructName .}}), nil
+{{- end}}
+	default:
+		return nil, fmt.Errorf("{{$name}}: case %v invalid", c)
+	}
+}
+{{if eq .DataKind "json"}}
+// UnmarshalJSON implements json.Unmarshaler
+func (u *{{$name}}) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type  {{$enum}} ` + "`json:\"{{.TypeName}}\"`" + `
+		Value json.RawMessage ` + "`json:\"{{.ValueName}}\"`" + `
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	v, err := u.newVariant(raw.Type)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw.Value, v); err != nil {
+		return err
+	}
+	u.Variant = v
+	return nil
+}
+{{else if eq .DataKind "bson"}}
+// UnmarshalBSON implements bson.Unmarshaler
+func (u *{{$name}}) UnmarshalBSON(data []byte) error {
+	var raw struct {
+		Type  {{$enum}} ` + "`bson:\"{{.TypeName}}\"`" + `
+		Value bson.Raw ` + "`bson:\"{{.ValueName}}\"`" + `
+	}
+	if err := bson.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	v, err := u.newVariant(raw.Type)
+	if err != nil {
+		return err
+	}
+	if err := bson.Unmarshal(raw.Value, v); err != nil {
+		return err
+	}
+	u.Variant = v
+	return nil
+}
+{{else}}
+// newCase returns the pointer to the case to decode
+func (u *{{$name}}) newCase() any { return new({{$enum}}) }
+
+// setCase sets the variant of case c, and returns it to decode
+func (u *{{$name}}) setCase(c any) (any, error) {
+	v, err := u.newVariant(c.({{$enum}}))
+	if err != nil {
+		return nil, err
+	}
+	u.Variant = v
+	return v, nil
+}
+{{end}}`))
+
+// GenUnion 生成联合类型文本
+func (e *goExporter) GenUnion(u *parse.Union) string {
+	var sb strings.Builder
+	if err := templateGoUnion.Execute(&sb, map[string]any{
+		"Exporter":  e,
+		"Union":     u,
+		"DataKind":  e.options.DataKind.String(),
+		"TypeName":  export.UnionTypeName,
+		"ValueName": export.UnionValueName,
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: GenUnion"))
+	}
+	return sb.String()
+}
+
+// templateGoUnionsFile go联合类型文件模版
+var templateGoUnionsFile = template.Must(template.New("go_unions_file").
+	Parse(`// Code generated by gexcels; DO NOT EDIT.
+// This file was automatically generated and may be overwritten.
+
+package {{.PkgName}}
+
+import (
+	{{if eq .DataKind "json"}}"encoding/json"
+	{{end}}"fmt"{{if eq .DataKind "bson"}}
+
+	"go.mongodb.org/mongo-driver/v2/bson"{{end}}
+)
+{{range .Unions}}
+{{$.Exporter.GenUnion .}}{{end}}`))
+
+// GenUnionsFile 生成go联合类型文件文本
+func (e *goExporter) GenUnionsFile() string {
+	var sb strings.Builder
+	if err := templateGoUnionsFile.Execute(&sb, map[string]any{
+		"Exporter": e,
+		"Unions":   e.parser.Unions,
+		"DataKind": e.options.DataKind.String(),
+		"PkgName":  e.kindOptions.PkgName,
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: GenUnionsFile"))
+	}
+	return sb.String()
+}
+
 // templateGoEntryStruct go table结构体模版
 var templateGoEntryStruct = template.Must(template.New("go_struct").
 	Parse(`type {{.Exporter.GetEntryStructName .Table}} struct {
 {{range $index,$field := .Table.Fields -}}
-{{"\t"}}{{$.Exporter.GetEntryFieldName $field}} {{$.Exporter.GetEntryFieldType $field}} {{$.Exporter.GenEntryFieldTag $field}} // {{$field.Desc}}
+{{"\t"}}{{$.Exporter.GetEntryFieldName $field}} {{$.Exporter.GetEntryFieldType $field}} {{$.Exporter.GenEntryFieldTag $field}} // {{$.Exporter.GenFieldComment $field.Field}}
 {{end}}}`))
 
 // GenEntryStruct 生成go配置表结构体文本
@@ -178,6 +605,159 @@ func (e *goExporter) GenEntryStruct(td *parse.Table) string {
 	return sb.String()
 }
 
+// templateGoHasMethods go可选字段是否设置的方法模版
+var templateGoHasMethods = template.Must(template.New("go_has_methods").
+	Parse(`{{range $fieldName := .FieldNames}}
+{{$methodName := $.Exporter.GenHasMethodName $fieldName}}
+// {{$methodName}} reports whether {{$fieldName}} is set
+func ({{$.Recv}} *{{$.TypeName}}) {{$methodName}}() bool { return {{$.Recv}}.{{$fieldName}} != nil }
+{{- end}}`))
+
+// genHasMethods 生成类型 typeName 中可选字段的 Has 方法, 每个方法前附加空行
+func (e *goExporter) genHasMethods(recv, typeName string, fieldNames []string) string {
+	var sb strings.Builder
+	if err := templateGoHasMethods.Execute(&sb, map[string]any{
+		"Exporter":   e,
+		"Recv":       recv,
+		"TypeName":   typeName,
+		"FieldNames": fieldNames,
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: genHasMethods"))
+	}
+	return sb.String()
+}
+
+// GenStructHasMethods 生成go结构体可选字段的 Has 方法
+func (e *goExporter) GenStructHasMethods(sd *parse.Struct) string {
+	// 继承字段的 Has 方法由嵌入的父结构体提供
+	var fieldNames []string
+	for _, fd := range sd.OwnFields() {
+		if fd.Optional {
+			fieldNames = append(fieldNames, e.GetFieldName(fd))
+		}
+	}
+	return e.genHasMethods("s", e.GetStructName(sd), fieldNames)
+}
+
+// GenEntryHasMethods 生成go配置表条目可选字段的 Has 方法
+func (e *goExporter) GenEntryHasMethods(td *parse.Table) string {
+	var fieldNames []string
+	for _, fd := range td.Fields {
+		if fd.Optional {
+			fieldNames = append(fieldNames, e.GetEntryFieldName(fd))
+		}
+	}
+	return e.genHasMethods("e", e.GetEntryStructName(td), fieldNames)
+}
+
+// GenGlobalTableHasMethods 生成go全局配置表可选字段的 Has 方法
+func (e *goExporter) GenGlobalTableHasMethods(td *parse.Table) string {
+	var fieldNames []string
+	for _, fd := range td.Fields {
+		if fd.Optional {
+			fieldNames = append(fieldNames, e.GetFieldName(fd.Field))
+		}
+	}
+	return e.genHasMethods("t", e.GetTableStructName(td), fieldNames)
+}
+
+// templateGoTextMethods go文本字段查找文本的方法模版
+var templateGoTextMethods = template.Must(template.New("go_text_methods").
+	Parse(`{{range $fieldName := .FieldNames}}
+{{$methodName := $.Exporter.GenTextMethodName $fieldName}}
+// {{$methodName}} returns the localized text of {{$fieldName}}
+func ({{$.Recv}} *{{$.TypeName}}) {{$methodName}}() string { return Text({{$.Recv}}.{{$fieldName}}) }
+{{- end}}`))
+
+// genTextMethods 生成类型 typeName 中文本字段的查找方法, 每个方法前附加空行
+func (e *goExporter) genTextMethods(recv, typeName string, fieldNames []string) string {
+	var sb strings.Builder
+	if err := templateGoTextMethods.Execute(&sb, map[string]any{
+		"Exporter":   e,
+		"Recv":       recv,
+		"TypeName":   typeName,
+		"FieldNames": fieldNames,
+	}); err != nil {
+		panic(pkg_errors.WithMessage(err, "export code: go: genTextMethods"))
+	}
+	return sb.String()
+}
+
+// GenStructTextMethods 生成go结构体文本字段的查找方法
+func (e *goExporter) GenStructTextMethods(sd *parse.Struct) string {
+	// 继承字段的查找方法由嵌入的父结构体提供
+	var fieldNames []string
+	for _, fd := range sd.OwnFields() {
+		if fd.Type == gexcels.FTText {
+			fieldNames = append(fieldNames, e.GetFieldName(fd))
+		}
+	}
+	return e.genTextMethods("s", e.GetStructName(sd), fieldNames)
+}
+
+// GenEntryTextMethods 生成go配置表条目文本字段的查找方法
+func (e *goExporter) GenEntryTextMethods(td *parse.Table) string {
+	var fieldNames []string
+	for _, fd := range td.Fields {
+		if fd.Type == gexcels.FTText {
+			fieldNames = append(fieldNames, e.GetEntryFieldName(fd))
+		}
+	}
+	return e.genTextMethods("e", e.GetEntryStructName(td), fieldNames)
+}
+
+// GenGlobalTableTextMethods 生成go全局配置表文本字段的查找方法
+func (e *goExporter) GenGlobalTableTextMethods(td *parse.Table) string {
+	var fieldNames []string
+	for _, fd := range td.Fields {
+		if fd.Type == gexcels.FTText {
+			fieldNames = append(fieldNames, e.GetFieldName(fd.Field))
+		}
+	}
+	return e.genTextMethods("t", e.GetTableStructName(td), fieldNames)
+}
+
+// templateGoEntryLinkMethod go条目链接访问方法模版
+var templateGoEntryLinkMethod = template.Must(template.New("go_entry_link_method").
+	Parse(`{{$methodName := .Exporter.GenEntryLinkMethodName .Link.Field -}}
+{{$fieldName := .Exporter.GetEntryFieldName .Link.Field -}}
+{{$value := printf "e.%s" $fieldName -}}
+{{if .Link.Field.Optional}}{{$value = printf "*e.%s" $fieldName}}{{end -}}
+// {{$methodName}} link to {{.Link.DstTable.Name}}.{{.Link.DstField.Name}}
+func (e *{{.Exporter.GetEntryStructName .Table}}) {{$methodName}}() *{{.Exporter.GetEntryStructName .Link.DstTable}} {
+{{- if .Link.Field.Optional}}
+	if e.{{$fieldName}} == nil {
+		return nil
+	}
+{{- end}}
+{{- if .Link.Nullable}}
+	if {{range $i, $v := .Link.NullLiterals}}{{if $i}} || {{end}}{{$value}} == {{$v}}{{end}} {
+		return nil
+	}
+{{- end}}
+	t := {{.Exporter.GetTableStructExportName .Link.DstTable}}()
+	if t == nil {
+		return nil
+	}
+	return t.{{.Exporter.GenTableUniqueKeyMethodName .Link.DstField}}({{$value}})
+}`))
+
+// GenEntryLinkMethods 生成go条目链接访问方法
+func (e *goExporter) GenEntryLinkMethods(td *parse.Table) string {
+	var sb strings.Builder
+	for _, link := range getLinkAccessors(e.parser, td) {
+		sb.WriteString("\n\n")
+		if err := templateGoEntryLinkMethod.Execute(&sb, map[string]any{
+			"Exporter": e,
+			"Table":    td,
+			"Link":     link,
+		}); err != nil {
+			panic(pkg_errors.WithMessage(err, "export code: go: GenEntryLinkMethods"))
+		}
+	}
+	return sb.String()
+}
+
 // templateGoTableUniqueKeyMethod go配置表唯一键方法模版
 var templateGoTableUniqueKeyMethod = template.Must(template.New("go_table_unique_key_method").
 	Parse(`{{$methodName := .Exporter.GenTableUniqueKeyMethodName .Field -}}
@@ -376,9 +956,9 @@ var templateGoNormalTableFile = template.Must(template.New("go_table_file").
 
 package {{.PkgName}}
 
-const {{.Exporter.GetTableNameConstName .Table}} = "{{.Table.Name}}"
+{{.Exporter.GenTableImports .Table}}const {{.Exporter.GetTableNameConstName .Table}} = "{{.Table.Name}}"
 
-{{.Exporter.GenEntryStruct .Table}}
+{{.Exporter.GenEntryStruct .Table}}{{.Exporter.GenEntryHasMethods .Table}}{{.Exporter.GenEntryTextMethods .Table}}{{.Exporter.GenEntryLinkMethods .Table}}
 {{$entryStructName := .Exporter.GetEntryStructName .Table -}}
 {{- $tableStructName := .Exporter.GetTableStructName .Table}}
 // {{$tableStructName}} {{.Table.Desc}}
@@ -504,7 +1084,7 @@ var templateGoGlobalTableFile = template.Must(template.New("go_global_table_file
 
 package {{.PkgName}}
 
-const {{.Exporter.GetTableNameConstName .Table}} = "{{.Table.Name}}"
+{{.Exporter.GenTableImports .Table}}const {{.Exporter.GetTableNameConstName .Table}} = "{{.Table.Name}}"
 
 {{$tableStructName := .Exporter.GetTableStructName .Table -}}
 // {{$tableStructName}} {{.Table.Desc}}
@@ -513,7 +1093,7 @@ type {{$tableStructName}} struct {
 {{if $index}}{{"\n"}}{{end -}}
 	{{"\t"}}{{$.Exporter.GenTableStructField $field}}
 {{- end}}
-}
+}{{.Exporter.GenGlobalTableHasMethods .Table}}{{.Exporter.GenGlobalTableTextMethods .Table}}
 
 {{.Exporter.GenTableLoadDataMethod .Table}}
 
@@ -1146,6 +1726,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/godyy/gutils/buffer/bytes"
 	pkg_errors "github.com/pkg/errors"
@@ -1153,6 +1734,26 @@ import (
 
 var loadHelper = &bytesLoadHelper{}
 
+// timeType datetime 字段类型, 以 Unix 毫秒数编码
+var timeType = reflect.TypeOf(time.Time{})
+
+// union 联合类型, 以判别枚举值 + 分支结构体编码
+type union interface {
+	newCase() any
+	setCase(c any) (any, error)
+}
+
+// unionType 联合类型接口类型
+var unionType = reflect.TypeOf((*union)(nil)).Elem()
+
+// tuple 元组类型, 按顺序编码各元素
+type tuple interface {
+	tuple()
+}
+
+// tupleType 元组类型接口类型
+var tupleType = reflect.TypeOf((*tuple)(nil)).Elem()
+
 type bytesLoadHelper struct{}
 
 func (h *bytesLoadHelper) load(basePath string, tableName string, v any) error {
@@ -1289,12 +1890,36 @@ func (h *bytesLoadHelper) decodeValue(buf *bytes.Buffer, v reflect.Value) (err e
 		if err == nil {
 			v.SetInt(int64(i32))
 		}
-	case reflect.Int64: // FTInt64
+	case reflect.Int64: // FTInt64, FTDuration, FTDecimal
 		var i64 int64
 		i64, err = buf.ReadVarint64()
 		if err == nil {
 			v.SetInt(i64)
 		}
+	case reflect.Int8, reflect.Int16: // FTInt8, FTInt16
+		var i16 int16
+		i16, err = buf.ReadVarint16()
+		if err == nil {
+			v.SetInt(int64(i16))
+		}
+	case reflect.Uint8, reflect.Uint16: // FTUint8, FTUint16
+		var u16 uint16
+		u16, err = buf.ReadUvarint16()
+		if err == nil {
+			v.SetUint(uint64(u16))
+		}
+	case reflect.Uint32: // FTUint32
+		var u32 uint32
+		u32, err = buf.ReadUvarint32()
+		if err == nil {
+			v.SetUint(uint64(u32))
+		}
+	case reflect.Uint64: // FTUint64
+		var u64 uint64
+		u64, err = buf.ReadUvarint64()
+		if err == nil {
+			v.SetUint(u64)
+		}
 	case reflect.Float32: // FTFloat32
 		var f32 float32
 		f32, err = buf.ReadFloat32()
@@ -1319,8 +1944,29 @@ func (h *bytesLoadHelper) decodeValue(buf *bytes.Buffer, v reflect.Value) (err e
 		if err == nil {
 			v.SetString(s)
 		}
-	case reflect.Ptr, reflect.Struct: // FTStruct
-		err = h.decodeStruct(buf, v)
+	case reflect.Ptr:
+		if v.Type().Implements(unionType) { // FTUnion
+			err = h.decodeUnion(buf, v)
+		} else if elemType := v.Type().Elem(); elemType.Kind() != reflect.Struct || elemType == timeType { // optional
+			ptr := reflect.New(elemType)
+			if err = h.decodeValue(buf, ptr.Elem()); err == nil {
+				v.Set(ptr)
+			}
+		} else { // FTStruct
+			err = h.decodeStruct(buf, v)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType { // FTDatetime
+			var ms int64
+			ms, err = buf.ReadVarint64()
+			if err == nil {
+				v.Set(reflect.ValueOf(time.UnixMilli(ms).UTC()))
+			}
+		} else if reflect.PointerTo(v.Type()).Implements(tupleType) { // FTTuple
+			err = h.decodeTuple(buf, v)
+		} else { // FTStruct
+			err = h.decodeStruct(buf, v)
+		}
 	case reflect.Slice: // FTArray
 		err = h.decodeArray(buf, v)
 	case reflect.Map: // FTMap
@@ -1346,7 +1992,8 @@ func (h *bytesLoadHelper) decodeStruct(buf *bytes.Buffer, s reflect.Value) error
 		v = s
 	}
 
-	n := v.NumField()
+	fields := structFields(v)
+	n := len(fields)
 	for {
 		index, err = h.decodeFieldIndex(buf)
 		if err != nil {
@@ -1358,7 +2005,7 @@ func (h *bytesLoadHelper) decodeStruct(buf *bytes.Buffer, s reflect.Value) error
 		if index == 0 {
 			break
 		}
-		field := v.Field(int(index - 1))
+		field := fields[index-1]
 		if err = h.decodeValue(buf, field); err != nil {
 			return pkg_errors.WithMessagef(err, "load field[%d]", index)
 		}
@@ -1370,6 +2017,46 @@ func (h *bytesLoadHelper) decodeStruct(buf *bytes.Buffer, s reflect.Value) error
 	return nil
 }
 
+// structFields 获取结构体的所有字段, 嵌入的父结构体字段展开并排在前面
+func structFields(v reflect.Value) []reflect.Value {
+	fields := make([]reflect.Value, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Anonymous {
+			fields = append(fields, structFields(v.Field(i))...)
+		} else {
+			fields = append(fields, v.Field(i))
+		}
+	}
+	return fields
+}
+
+func (h *bytesLoadHelper) decodeTuple(buf *bytes.Buffer, v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		if err := h.decodeValue(buf, v.Field(i)); err != nil {
+			return pkg_errors.WithMessagef(err, "load element[%d]", i)
+		}
+	}
+	return nil
+}
+
+func (h *bytesLoadHelper) decodeUnion(buf *bytes.Buffer, v reflect.Value) error {
+	ptr := reflect.New(v.Type().Elem())
+	u := ptr.Interface().(union)
+	c := reflect.ValueOf(u.newCase()).Elem()
+	if err := h.decodeValue(buf, c); err != nil {
+		return pkg_errors.WithMessage(err, "load case")
+	}
+	variant, err := u.setCase(c.Interface())
+	if err != nil {
+		return err
+	}
+	if err := h.decodeStruct(buf, reflect.ValueOf(variant).Elem()); err != nil {
+		return pkg_errors.WithMessagef(err, "load case %v", c.Interface())
+	}
+	v.Set(ptr)
+	return nil
+}
+
 func (h *bytesLoadHelper) decodeArrayLength(buf *bytes.Buffer) (int, error) {
 	arrayLen, err := buf.ReadVarint16()
 	if err != nil {
@@ -1415,7 +2102,9 @@ func (h *bytesLoadHelper) decodeMap(buf *bytes.Buffer, m reflect.Value) error {
 
 	keyType := m.Type().Key()
 	switch keyType.Kind() {
-	case reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
 	default:
 		return fmt.Errorf("load map: unsupported key type: %v", keyType)
 	}
@@ -3,6 +3,8 @@ package data
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/godyy/gexcels"
@@ -79,10 +81,18 @@ func (e *bsonExporter) exportNormalTable(table *parse.Table) error {
 	for i, entry := range table.Entries {
 		object := make(bson.D, 0, len(table.Fields))
 		for _, fd := range table.Fields {
+			// 可选字段未设置时不写入
+			if fd.Optional && entry[fd.Name] == nil {
+				continue
+			}
+			value, err := e.convertValue(fd.FieldTypeInfo, entry[fd.Name])
+			if err != nil {
+				return pkg_errors.WithMessagef(err, "table[%s] entry[%d] field[%s]", table.Name, i, fd.Name)
+			}
 			if fd.Col == gexcels.TableColFieldID {
-				object = append(object, bson.E{Key: export.TableFieldIDBsonName, Value: entry[fd.Name]})
+				object = append(object, bson.E{Key: export.TableFieldIDBsonName, Value: value})
 			} else {
-				object = append(object, bson.E{Key: fd.Name, Value: entry[fd.Name]})
+				object = append(object, bson.E{Key: fd.Name, Value: value})
 			}
 		}
 
@@ -111,7 +121,15 @@ func (e *bsonExporter) exportGlobalTable(td *parse.Table) error {
 
 	object = append(object, bson.E{Key: export.TableFieldIDBsonName, Value: td.Name})
 	for _, fd := range td.Fields {
-		object = append(object, bson.E{Key: fd.Name, Value: td.GetEntryByName(fd.Name)})
+		value := td.GetEntryByName(fd.Name)
+		if fd.Optional && value == nil {
+			continue
+		}
+		value, err := e.convertValue(fd.FieldTypeInfo, value)
+		if err != nil {
+			return pkg_errors.WithMessagef(err, "table[%s] field[%s]", td.Name, fd.Name)
+		}
+		object = append(object, bson.E{Key: fd.Name, Value: value})
 	}
 
 	ctx, cancel := createBsonSaveContext()
@@ -123,3 +141,92 @@ func (e *bsonExporter) exportGlobalTable(td *parse.Table) error {
 
 	return nil
 }
+
+// convertValue 转换值中的联合类型值为 {Type:判别枚举值, Value:分支结构体} 文档, 其余值原样写入
+func (e *bsonExporter) convertValue(ti *gexcels.FieldTypeInfo, value any) (any, error) {
+	if value == nil || !e.containsUnion(ti) {
+		return value, nil
+	}
+
+	switch ti.Type {
+	case gexcels.FTUnion:
+		uv, ok := value.(*parse.UnionValue)
+		if !ok {
+			return nil, fmt.Errorf("value %v not union", value)
+		}
+		disc, st, err := e.parser.ResolveUnionValue(ti, uv)
+		if err != nil {
+			return nil, err
+		}
+		v, err := e.convertValue(st, uv.Value)
+		if err != nil {
+			return nil, pkg_errors.WithMessagef(err, "case[%s]", uv.Case)
+		}
+		return bson.D{{Key: export.UnionTypeName, Value: disc}, {Key: export.UnionValueName, Value: v}}, nil
+	case gexcels.FTStruct:
+		sd := e.parser.GetStructByName(ti.GetName())
+		m := value.(map[string]any)
+		out := make(map[string]any, len(m))
+		for _, fd := range sd.Fields {
+			fv, ok := m[fd.Name]
+			if !ok {
+				continue
+			}
+			v, err := e.convertValue(fd.FieldTypeInfo, fv)
+			if err != nil {
+				return nil, pkg_errors.WithMessagef(err, "field[%s]", fd.Name)
+			}
+			out[fd.Name] = v
+		}
+		return out, nil
+	case gexcels.FTArray:
+		rv := reflect.ValueOf(value)
+		out := make([]any, rv.Len())
+		for i := range out {
+			v, err := e.convertValue(ti.GetElementType(), rv.Index(i).Interface())
+			if err != nil {
+				return nil, pkg_errors.WithMessagef(err, "[%d]", i)
+			}
+			out[i] = v
+		}
+		return out, nil
+	case gexcels.FTMap:
+		rv := reflect.ValueOf(value)
+		out := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for _, k := range rv.MapKeys() {
+			v, err := e.convertValue(ti.GetMapValueType(), rv.MapIndex(k).Interface())
+			if err != nil {
+				return nil, pkg_errors.WithMessagef(err, "[%v]", k.Interface())
+			}
+			out.SetMapIndex(k, reflect.ValueOf(&v).Elem())
+		}
+		return out.Interface(), nil
+	default:
+		return value, nil
+	}
+}
+
+// containsUnion 类型中是否包含联合类型
+func (e *bsonExporter) containsUnion(ti *gexcels.FieldTypeInfo) bool {
+	switch ti.Type {
+	case gexcels.FTUnion:
+		return true
+	case gexcels.FTStruct:
+		sd := e.parser.GetStructByName(ti.GetName())
+		if sd == nil {
+			return false
+		}
+		for _, fd := range sd.Fields {
+			if e.containsUnion(fd.FieldTypeInfo) {
+				return true
+			}
+		}
+		return false
+	case gexcels.FTArray:
+		return e.containsUnion(ti.GetElementType())
+	case gexcels.FTMap:
+		return e.containsUnion(ti.GetMapValueType())
+	default:
+		return false
+	}
+}
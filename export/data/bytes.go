@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/godyy/gexcels"
 	internal_define "github.com/godyy/gexcels/export"
@@ -149,23 +150,48 @@ func (e *bytesExporter) encodeFieldValue(fd *gexcels.Field, index int, value any
 	return e.encodeValue(fd.FieldTypeInfo, value, "field["+fd.Name+"]")
 }
 
-// encodePrimitiveValue 编码primitive值
+// encodePrimitiveValue 编码primitive值.
+// datetime 编码为 Unix 毫秒数, duration 编码为纳秒数, decimal 编码为缩放后的整数.
 func (e *bytesExporter) encodePrimitiveValue(ft gexcels.FieldType, value any) error {
 	switch ft {
 	case gexcels.FTInt32:
 		_, err := e.buf.WriteVarint32(value.(int32))
 		return err
-	case gexcels.FTInt64:
+	case gexcels.FTInt64, gexcels.FTDecimal:
 		_, err := e.buf.WriteVarint64(value.(int64))
 		return err
+	case gexcels.FTInt8:
+		_, err := e.buf.WriteVarint16(int16(value.(int8)))
+		return err
+	case gexcels.FTInt16:
+		_, err := e.buf.WriteVarint16(value.(int16))
+		return err
+	case gexcels.FTUint8:
+		_, err := e.buf.WriteUvarint16(uint16(value.(uint8)))
+		return err
+	case gexcels.FTUint16:
+		_, err := e.buf.WriteUvarint16(value.(uint16))
+		return err
+	case gexcels.FTUint32:
+		_, err := e.buf.WriteUvarint32(value.(uint32))
+		return err
+	case gexcels.FTUint64:
+		_, err := e.buf.WriteUvarint64(value.(uint64))
+		return err
 	case gexcels.FTFloat32:
 		return e.buf.WriteFloat32(value.(float32))
 	case gexcels.FTFloat64:
 		return e.buf.WriteFloat64(value.(float64))
 	case gexcels.FTBool:
 		return e.buf.WriteBool(value.(bool))
-	case gexcels.FTString:
+	case gexcels.FTString, gexcels.FTText:
 		return e.buf.WriteString(value.(string))
+	case gexcels.FTDatetime:
+		_, err := e.buf.WriteVarint64(value.(time.Time).UnixMilli())
+		return err
+	case gexcels.FTDuration:
+		_, err := e.buf.WriteVarint64(int64(value.(time.Duration)))
+		return err
 	default:
 		panic(fmt.Sprintf("export data: bytes: encodePrimitiveValue: field type %d invalid", ft))
 	}
@@ -182,7 +208,7 @@ func (e *bytesExporter) encodeStructField(ft *gexcels.FieldTypeInfo, value any)
 	var (
 		sd        = e.parser.GetStructByName(ft.GetName())
 		v         = reflect.ValueOf(value)
-		lastIndex int
+		lastIndex = -1 // 未写入任何字段时同样需要结束标记
 	)
 	for i, fd := range sd.Fields {
 		fv := v.MapIndex(reflect.ValueOf(fd.Name))
@@ -210,12 +236,18 @@ func (e *bytesExporter) encodeValue(ti *gexcels.FieldTypeInfo, value any, path s
 	}
 
 	switch ti.Type {
-	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString:
+	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64,
+		gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString, gexcels.FTText, gexcels.FTDatetime, gexcels.FTDuration,
+		gexcels.FTDecimal:
 		return e.encodePrimitiveValue(ti.Type, value)
 	case gexcels.FTEnum:
 		return e.encodeEnumField(ti, value)
 	case gexcels.FTStruct:
 		return e.encodeStructField(ti, value)
+	case gexcels.FTUnion:
+		return e.encodeUnionValue(ti, value, path)
+	case gexcels.FTTuple:
+		return e.encodeTupleValue(ti, value, path)
 	case gexcels.FTArray:
 		return e.encodeArrayValue(ti, value, path)
 	case gexcels.FTMap:
@@ -225,6 +257,40 @@ func (e *bytesExporter) encodeValue(ti *gexcels.FieldTypeInfo, value any, path s
 	}
 }
 
+// encodeUnionValue 编码联合类型：判别枚举值 + 分支结构体。
+func (e *bytesExporter) encodeUnionValue(ft *gexcels.FieldTypeInfo, value any, path string) error {
+	uv, ok := value.(*parse.UnionValue)
+	if !ok || uv == nil {
+		return fmt.Errorf("%s must be union", path)
+	}
+	disc, st, err := e.parser.ResolveUnionValue(ft, uv)
+	if err != nil {
+		return pkg_errors.WithMessage(err, path)
+	}
+	enum := e.parser.GetEnum(e.parser.GetUnionByName(ft.GetName()).Enum)
+	if err := e.encodePrimitiveValue(enum.Type, disc); err != nil {
+		return pkg_errors.WithMessagef(err, "%s type", path)
+	}
+	if err := e.encodeStructField(st, uv.Value); err != nil {
+		return pkg_errors.WithMessagef(err, "%s case[%s]", path, uv.Case)
+	}
+	return nil
+}
+
+// encodeTupleValue 编码元组：按顺序编码各元素, 元素数量固定不写长度。
+func (e *bytesExporter) encodeTupleValue(ft *gexcels.FieldTypeInfo, value any, path string) error {
+	t, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("%s must be tuple", path)
+	}
+	for i, et := range ft.GetTupleElements() {
+		if err := e.encodeValue(et, t[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // encodeArrayValue 编码数组：长度 + N 个元素（元素类型递归编码）。
 func (e *bytesExporter) encodeArrayValue(ft *gexcels.FieldTypeInfo, value any, path string) error {
 	elemType := ft.GetElementType()
@@ -64,8 +64,10 @@ func (e *baseExporter) sortMapKeys(kt *gexcels.FieldTypeInfo, keys []reflect.Val
 		switch ft {
 		case gexcels.FTInt32:
 			return keys[i].Int() < keys[j].Int()
-		case gexcels.FTInt64:
+		case gexcels.FTInt64, gexcels.FTInt8, gexcels.FTInt16:
 			return keys[i].Int() < keys[j].Int()
+		case gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+			return keys[i].Uint() < keys[j].Uint()
 		case gexcels.FTString:
 			return keys[i].String() < keys[j].String()
 		default:
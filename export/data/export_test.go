@@ -1,7 +1,13 @@
 package data
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/godyy/gexcels"
@@ -39,6 +45,42 @@ func TestExportBytes(t *testing.T) {
 	}
 }
 
+func TestExportTexts(t *testing.T) {
+	p, err := parse.ParseSources([]parse.Source{
+		{Name: "a|A.csv", Reader: strings.NewReader("ID,Name\nid,name\nint32,text\n,\n,\n1,<b>Sword</b>\n2,Shield\n")},
+	}, &parse.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := t.TempDir()
+	if err := os.WriteFile(filepath.Join(path, "texts.en.json"), []byte(`{"A.1.Name":"Sword EN","A.3.Name":"Axe EN"}`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportTexts(p, path, []string{"zh", "en"}); err != nil {
+		t.Fatalf("export texts to %s, %v", path, err)
+	}
+	for lang, expected := range map[string]map[string]string{
+		"zh": {"A.1.Name": "<b>Sword</b>", "A.2.Name": "Shield"},
+		"en": {"A.1.Name": "Sword EN", "A.2.Name": ""},
+	} {
+		b, err := os.ReadFile(filepath.Join(path, "texts."+lang+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var texts map[string]string
+		if err := json.Unmarshal(b, &texts); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(texts, expected) {
+			t.Fatalf("texts %s %v invalid", lang, texts)
+		}
+		if lang == "zh" && !bytes.Contains(b, []byte("<b>Sword</b>")) {
+			t.Fatalf("texts %s html escaped:\n%s", lang, b)
+		}
+	}
+}
+
 func TestExportBson(t *testing.T) {
 	excelsPath := "../../internal/test/excels"
 	mongoURI := "mongodb://localhost:27017"
@@ -116,6 +116,10 @@ func (e *jsonExporter) marshalJsonFieldValue(ft *gexcels.FieldTypeInfo, val any)
 		return json.Marshal(val)
 	} else if ft.Type == gexcels.FTStruct {
 		return e.marshalJsonStructValue(ft, val)
+	} else if ft.Type == gexcels.FTUnion {
+		return e.marshalJsonUnionValue(ft, val)
+	} else if ft.Type == gexcels.FTTuple {
+		return e.marshalJsonTupleValue(ft, val)
 	} else if ft.Type == gexcels.FTArray {
 		return e.marshalJsonArrayValue(ft, val)
 	} else if ft.Type == gexcels.FTMap {
@@ -158,13 +162,68 @@ func (e *jsonExporter) marshalJsonStructValue(ft *gexcels.FieldTypeInfo, val any
 	return buf.Bytes(), nil
 }
 
-// checkArrayCouldMarshal 检查数组类型是否直接编码
+// marshalJsonUnionValue 编码联合类型值, 格式为 {"Type":判别枚举值,"Value":分支结构体}
+func (e *jsonExporter) marshalJsonUnionValue(ft *gexcels.FieldTypeInfo, val any) ([]byte, error) {
+	uv, _ := val.(*parse.UnionValue)
+	if uv == nil {
+		return []byte("null"), nil
+	}
+
+	disc, st, err := e.parser.ResolveUnionValue(ft, uv)
+	if err != nil {
+		return nil, err
+	}
+	discJson, err := json.Marshal(disc)
+	if err != nil {
+		return nil, err
+	}
+	valueJson, err := e.marshalJsonStructValue(st, uv.Value)
+	if err != nil {
+		return nil, pkg_errors.WithMessagef(err, "case[%s]", uv.Case)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("{")
+	buf.WriteString(e.jsonFieldNamePrefix(export.UnionTypeName))
+	buf.Write(discJson)
+	buf.WriteString(",")
+	buf.WriteString(e.jsonFieldNamePrefix(export.UnionValueName))
+	buf.Write(valueJson)
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// marshalJsonTupleValue 编码元组值, 格式为元素数组
+func (e *jsonExporter) marshalJsonTupleValue(ft *gexcels.FieldTypeInfo, val any) ([]byte, error) {
+	t, _ := val.([]any)
+	if t == nil {
+		return []byte("null"), nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("[")
+	for i, et := range ft.GetTupleElements() {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		elemValJson, err := e.marshalJsonFieldValue(et, t[i])
+		if err != nil {
+			return nil, pkg_errors.WithMessagef(err, "[%d]", i)
+		}
+		buf.Write(elemValJson)
+	}
+	buf.WriteString("]")
+	return buf.Bytes(), nil
+}
+
+// checkArrayCouldMarshal 检查数组类型是否直接编码.
+// []uint8 会被 encoding/json 编码为 base64 字符串, 需逐个元素编码.
 func (e *jsonExporter) checkArrayCouldMarshal(ft *gexcels.FieldTypeInfo) bool {
 	if ft.Type != gexcels.FTArray {
 		return false
 	}
 	elementType := ft.GetElementType()
-	return elementType.Type.Primitive() || elementType.Type == gexcels.FTEnum ||
+	return (elementType.Type.Primitive() && elementType.Type != gexcels.FTUint8) || elementType.Type == gexcels.FTEnum ||
 		(elementType.Type == gexcels.FTArray && e.checkArrayCouldMarshal(elementType))
 }
 
@@ -296,14 +355,16 @@ func (e *tableJsonMarshaler) marshalNormal() ([]byte, error) {
 func (e *tableJsonMarshaler) marshalGlobal() ([]byte, error) {
 	buf := bytes.NewBuffer(nil)
 	buf.WriteString("{")
-	for i, fd := range e.table.Fields {
+	n := 0
+	for _, fd := range e.table.Fields {
 		fieldVal := e.table.GetEntryByName(fd.Name)
 		if fieldVal == nil {
 			continue
 		}
-		if i > 0 {
+		if n > 0 {
 			buf.WriteString(",")
 		}
+		n++
 		buf.WriteString(e.exporter.jsonFieldNamePrefix(fd.Name))
 		entryJson, err := e.exporter.marshalJsonFieldValue(fd.Field.FieldTypeInfo, fieldVal)
 		if err != nil {
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/godyy/gexcels/export"
+	"github.com/godyy/gexcels/internal/log"
+	"github.com/godyy/gexcels/parse"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// ErrNoLangSpecified 未指定文本语言
+var ErrNoLangSpecified = errors.New("export data: no text language specified")
+
+// ExportTexts 导出文本字符串表, 每种语言一个文件, 内容为文本key到文本的映射.
+// langs[0] 为源语言, 文本为配置表中填写的值;
+// 其它语言保留已有文件中仍存在的key的翻译, 新增key的翻译为空字符串.
+func ExportTexts(p *parse.Parser, path string, langs []string) error {
+	if p == nil {
+		return ErrNoParserSpecified
+	}
+
+	if path == "" {
+		return ErrNoPathSpecified
+	}
+
+	if len(langs) == 0 {
+		return ErrNoLangSpecified
+	}
+
+	log.Printf("export data texts to [%s]", path)
+
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "mkdir")
+	}
+
+	for i, lang := range langs {
+		if err := exportTextFile(p, path, lang, i == 0); err != nil {
+			return pkg_errors.WithMessage(err, "export data: texts")
+		}
+	}
+	return nil
+}
+
+// exportTextFile 导出语言 lang 的文本字符串表文件
+func exportTextFile(p *parse.Parser, path, lang string, source bool) error {
+	filePath := filepath.Join(path, export.TextFileName(lang))
+
+	// 非源语言保留已有的翻译
+	var translated map[string]string
+	if !source {
+		b, err := os.ReadFile(filePath)
+		if err != nil && !os.IsNotExist(err) {
+			return pkg_errors.WithMessagef(err, "lang[%s] read [%s]", lang, filePath)
+		}
+		if err == nil {
+			if err := json.Unmarshal(b, &translated); err != nil {
+				return pkg_errors.WithMessagef(err, "lang[%s] unmarshal [%s]", lang, filePath)
+			}
+		}
+	}
+
+	texts := make(map[string]string, len(p.Texts))
+	for _, t := range p.Texts {
+		if source {
+			texts[t.Key] = t.Value
+		} else {
+			texts[t.Key] = translated[t.Key]
+		}
+	}
+
+	// 文本常含富文本标签, 不转义html字符便于翻译编辑
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(texts); err != nil {
+		return pkg_errors.WithMessagef(err, "lang[%s] marshal", lang)
+	}
+	if err := os.WriteFile(filePath, buf.Bytes(), os.ModePerm); err != nil {
+		return pkg_errors.WithMessagef(err, "lang[%s] to [%s]", lang, filePath)
+	}
+	log.PrintfGreen("export data: texts: lang[%s] to [%s]", lang, filePath)
+	return nil
+}
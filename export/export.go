@@ -8,3 +8,17 @@ const TableGlobalBsonCollName = "Global"
 
 // TableFieldIDBsonName 配置表ID字段bson字段名
 const TableFieldIDBsonName = "_id"
+
+// UnionTypeName 联合类型值中判别枚举值的字段名
+const UnionTypeName = "Type"
+
+// UnionValueName 联合类型值中分支结构体值的字段名
+const UnionValueName = "Value"
+
+// TextFilePrefix 文本字符串表文件名前缀, 文件名为 {TextFilePrefix}.{lang}.json
+const TextFilePrefix = "texts"
+
+// TextFileName 生成语言 lang 的文本字符串表文件名
+func TextFileName(lang string) string {
+	return TextFilePrefix + "." + lang + ".json"
+}
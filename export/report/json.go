@@ -0,0 +1,49 @@
+package report
+
+import (
+	"io"
+
+	"github.com/godyy/gexcels/parse"
+)
+
+// jsonReport json格式报告
+type jsonReport struct {
+	Errors      int               `json:"errors"`      // 错误数量
+	Warnings    int               `json:"warnings"`    // 警告数量
+	Diagnostics []*jsonDiagnostic `json:"diagnostics"` // 诊断信息
+}
+
+// jsonDiagnostic json格式诊断信息
+type jsonDiagnostic struct {
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Sheet    string `json:"sheet,omitempty"`
+	Cell     string `json:"cell,omitempty"`
+	Row      int    `json:"row,omitempty"`
+	Col      int    `json:"col,omitempty"`
+	Table    string `json:"table,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message"`
+}
+
+// WriteJson 将诊断信息以json格式写入w
+func WriteJson(w io.Writer, diags []*parse.Diagnostic) error {
+	report := &jsonReport{Diagnostics: make([]*jsonDiagnostic, 0, len(diags))}
+	report.Errors, report.Warnings = countSeverity(diags)
+	for _, diag := range diags {
+		report.Diagnostics = append(report.Diagnostics, &jsonDiagnostic{
+			Severity: diag.Severity.String(),
+			Path:     diag.Path,
+			Sheet:    diag.Sheet,
+			Cell:     diag.Cell(),
+			Row:      diag.Row,
+			Col:      diag.Col,
+			Table:    diag.Table,
+			Field:    diag.Field,
+			Rule:     diag.Rule,
+			Message:  diag.Message,
+		})
+	}
+	return writeJson(w, report)
+}
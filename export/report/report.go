@@ -0,0 +1,68 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/godyy/gexcels/parse"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// ErrNoPathSpecified 未指定导出路径
+var ErrNoPathSpecified = errors.New("export report: no path specified")
+
+// ExportJson 导出json格式的诊断报告
+func ExportJson(diags []*parse.Diagnostic, path string) error {
+	return exportFile(path, func(w io.Writer) error { return WriteJson(w, diags) })
+}
+
+// ExportSarif 导出 SARIF 2.1.0 格式的诊断报告
+func ExportSarif(diags []*parse.Diagnostic, path string) error {
+	return exportFile(path, func(w io.Writer) error { return WriteSarif(w, diags) })
+}
+
+// exportFile 创建path指定的文件, 并通过write写入报告内容
+func exportFile(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return ErrNoPathSpecified
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return pkg_errors.WithMessage(err, "export report: mkdir")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return pkg_errors.WithMessage(err, "export report: create file")
+	}
+
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return pkg_errors.WithMessage(err, "export report: write")
+	}
+
+	return file.Close()
+}
+
+// writeJson 以缩进格式写入v
+func writeJson(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// countSeverity 统计各级别诊断数量
+func countSeverity(diags []*parse.Diagnostic) (errs, warnings int) {
+	for _, diag := range diags {
+		switch diag.Severity {
+		case parse.SeverityError:
+			errs++
+		case parse.SeverityWarning:
+			warnings++
+		}
+	}
+	return
+}
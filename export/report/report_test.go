@@ -0,0 +1,111 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/godyy/gexcels/parse"
+)
+
+var testDiagnostics = []*parse.Diagnostic{
+	{
+		Severity: parse.SeverityError,
+		Path:     "excels/道具|Item.csv",
+		Sheet:    "道具|Item",
+		Row:      7,
+		Col:      2,
+		Table:    "Item",
+		Field:    "Ref",
+		Rule:     "LINK",
+		Message:  "link [Item.Ref -> A.ID] dst.ID=99 not found",
+	},
+	{
+		Severity: parse.SeverityWarning,
+		Message:  "no location",
+	},
+}
+
+func TestWriteJson(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJson(&buf, testDiagnostics); err != nil {
+		t.Fatal(err)
+	}
+
+	var report jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Errors != 1 || report.Warnings != 1 || len(report.Diagnostics) != 2 {
+		t.Fatalf("report invalid: %s", buf.String())
+	}
+	if d := report.Diagnostics[0]; d.Severity != "error" || d.Cell != "B7" || d.Rule != "LINK" || d.Sheet != "道具|Item" {
+		t.Fatalf("diagnostic invalid: %+v", d)
+	}
+	if d := report.Diagnostics[1]; d.Severity != "warning" || d.Cell != "" {
+		t.Fatalf("diagnostic invalid: %+v", d)
+	}
+}
+
+func TestWriteSarif(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSarif(&buf, testDiagnostics); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("sarif invalid: %s", buf.String())
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("sarif run invalid: %s", buf.String())
+	}
+
+	r := run.Results[0]
+	if r.RuleID != "LINK" || r.Level != "error" || len(r.Locations) != 1 {
+		t.Fatalf("sarif result invalid: %+v", r)
+	}
+	pl := r.Locations[0].PhysicalLocation
+	if pl == nil || pl.Region == nil || pl.Region.StartLine != 7 || pl.Region.StartColumn != 2 {
+		t.Fatalf("sarif physical location invalid: %s", buf.String())
+	}
+	if pl.ArtifactLocation.URI != "excels/%E9%81%93%E5%85%B7%7CItem.csv" {
+		t.Fatalf("sarif uri invalid: %s", pl.ArtifactLocation.URI)
+	}
+	if r.Properties["cell"] != "B7" {
+		t.Fatalf("sarif cell invalid: %v", r.Properties)
+	}
+
+	r = run.Results[1]
+	if r.RuleID != sarifRuleParse || r.Level != "warning" || r.Locations != nil {
+		t.Fatalf("sarif result invalid: %+v", r)
+	}
+}
+
+func TestExportReport(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "report", "report.json")
+	sarifPath := filepath.Join(dir, "report", "report.sarif")
+
+	if err := ExportJson(testDiagnostics, jsonPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportSarif(testDiagnostics, sarifPath); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{jsonPath, sarifPath} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ExportJson(nil, ""); err != ErrNoPathSpecified {
+		t.Fatalf("export without path: %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package report
+
+import (
+	"io"
+	"net/url"
+	"path/filepath"
+
+	"github.com/godyy/gexcels/parse"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// sarifToolName 工具名
+	sarifToolName = "gexcels"
+
+	// sarifRuleParse 未关联规则的诊断使用的规则ID
+	sarifRuleParse = "PARSE"
+)
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri,omitempty"`
+	Rules          []*sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID     string           `json:"ruleId"`
+	RuleIndex  int              `json:"ruleIndex"`
+	Level      string           `json:"level"`
+	Message    sarifMessage     `json:"message"`
+	Locations  []*sarifLocation `json:"locations,omitempty"`
+	Properties map[string]any   `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation  `json:"physicalLocation,omitempty"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion 单元格位置, 行号对应表格行, 列号对应表格列
+type sarifRegion struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSarif 将诊断信息以 SARIF 2.1.0 格式写入w
+func WriteSarif(w io.Writer, diags []*parse.Diagnostic) error {
+	run := &sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           sarifToolName,
+			InformationURI: "https://github.com/godyy/gexcels",
+		}},
+		Results: make([]*sarifResult, 0, len(diags)),
+	}
+
+	ruleIndex := map[string]int{}
+	for _, diag := range diags {
+		ruleID := diag.Rule
+		if ruleID == "" {
+			ruleID = sarifRuleParse
+		}
+		index, ok := ruleIndex[ruleID]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[ruleID] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{ID: ruleID})
+		}
+
+		result := &sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: index,
+			Level:     sarifLevel(diag.Severity),
+			Message:   sarifMessage{Text: diag.Message},
+		}
+		if location := sarifLocationOf(diag); location != nil {
+			result.Locations = []*sarifLocation{location}
+		}
+		if diag.Sheet != "" {
+			result.Properties = map[string]any{"sheet": diag.Sheet}
+			if cell := diag.Cell(); cell != "" {
+				result.Properties["cell"] = cell
+			}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return writeJson(w, &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []*sarifRun{run},
+	})
+}
+
+// sarifLevel 诊断级别对应的 SARIF level
+func sarifLevel(severity parse.Severity) string {
+	switch severity {
+	case parse.SeverityWarning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// sarifLocationOf 诊断位置对应的 SARIF location, 无位置信息时返回 nil
+func sarifLocationOf(diag *parse.Diagnostic) *sarifLocation {
+	var location sarifLocation
+
+	if diag.Path != "" {
+		location.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: sarifURI(diag.Path)},
+		}
+		if diag.Row > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: diag.Row, StartColumn: diag.Col}
+		}
+	}
+
+	if diag.Table != "" {
+		name, kind := diag.Table, "type"
+		if diag.Field != "" {
+			name, kind = name+"."+diag.Field, "member"
+		}
+		location.LogicalLocations = []*sarifLogicalLocation{{FullyQualifiedName: name, Kind: kind}}
+	}
+
+	if location.PhysicalLocation == nil && location.LogicalLocations == nil {
+		return nil
+	}
+	return &location
+}
+
+// sarifURI 将文件路径转换为 URI 引用
+func sarifURI(path string) string {
+	u := url.URL{Path: filepath.ToSlash(path)}
+	return u.String()
+}
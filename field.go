@@ -62,6 +62,24 @@ func (f *Field) GetFRLink() *FRLink {
 	return fr.(*FRLink)
 }
 
+// GetFRNullable 获得 FRNullable
+func (f *Field) GetFRNullable() *FRNullable {
+	fr := f.GetRule(FRNNullable)
+	if fr == nil {
+		return nil
+	}
+	return fr.(*FRNullable)
+}
+
+// GetFRDefault 获得 FRDefault
+func (f *Field) GetFRDefault() *FRDefault {
+	fr := f.GetRule(FRNDefault)
+	if fr == nil {
+		return nil
+	}
+	return fr.(*FRDefault)
+}
+
 // HasFRUnique 是否具备 FRUnique
 func (f *Field) HasFRUnique() bool {
 	return f.GetRule(FRNUnique) != nil
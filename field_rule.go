@@ -2,10 +2,14 @@ package gexcels
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 )
@@ -18,10 +22,17 @@ const FRValueSep = ","
 
 // 字段规则名称
 const (
-	FRNUnique       = "UNIQUE" // 唯一键
-	FRNLink         = "LINK"   // 外链，链接其它表的字段，该字段必须是ID，或者Unique
-	FRNCompositeKey = "CKEY"   // 组合键
-	FRNGroup        = "GROUP"  // 分组 将具备相同属性的数据聚合在一起
+	FRNUnique       = "UNIQUE"   // 唯一键
+	FRNLink         = "LINK"     // 外链，链接其它表的字段，该字段必须是ID，或者Unique
+	FRNCompositeKey = "CKEY"     // 组合键
+	FRNGroup        = "GROUP"    // 分组 将具备相同属性的数据聚合在一起
+	FRNRange        = "RANGE"    // 数值范围
+	FRNRegex        = "REGEX"    // 字符串正则匹配
+	FRNLen          = "LEN"      // 字符串长度范围
+	FRNNotEmpty     = "NOTEMPTY" // 字符串非空
+	FRNCheck        = "CHECK"    // 条目检查表达式
+	FRNNullable     = "NULLABLE" // 可空链接
+	FRNDefault      = "DEFAULT"  // 默认值
 )
 
 // FieldRule 规则接口
@@ -39,16 +50,106 @@ type FieldRule interface {
 	String() string
 }
 
+// FRFieldValidator 字段定义校验, 规则添加到字段时调用.
+// 可用于检查规则能否作用于字段类型.
+type FRFieldValidator interface {
+	ValidateField(field *Field) error
+}
+
+// FRValueValidator 字段值校验, 解析配置表条目时调用.
+// 数组元素以及 map 的值分别校验; 未填写的 primitive 值以零值校验.
+type FRValueValidator interface {
+	ValidateValue(value any) error
+}
+
+// FREntryValidator 条目校验, 配置表条目解析完成后调用, 可用于检查字段之间的关系.
+// 全局配置表的所有字段值视为一个条目.
+type FREntryValidator interface {
+	ValidateEntry(field *Field, entry TableEntry) error
+}
+
+// FRTableValidator 配置表校验, 配置表解析完成后调用.
+type FRTableValidator interface {
+	ValidateTable(table *Table, field *Field, entries []TableEntry) error
+}
+
+// frNameRegexp 字段规则名称正则表达式
+var frNameRegexp = regexp.MustCompile(`^` + NamePattern + `$`)
+
+// frCreatorsMu 保护 frCreators
+var frCreatorsMu sync.RWMutex
+
 // frCreators 字段规则构造器
 var frCreators = map[string]func() FieldRule{
 	FRNUnique:       func() FieldRule { return &FRUnique{} },
 	FRNLink:         func() FieldRule { return &FRLink{} },
 	FRNCompositeKey: func() FieldRule { return &FRCompositeKey{} },
 	FRNGroup:        func() FieldRule { return &FRGroup{} },
+	FRNRange:        func() FieldRule { return &FRRange{} },
+	FRNCheck:        func() FieldRule { return &FRCheck{} },
+	FRNRegex:        func() FieldRule { return &FRRegex{} },
+	FRNLen:          func() FieldRule { return &FRLen{} },
+	FRNNotEmpty:     func() FieldRule { return &FRNotEmpty{} },
+	FRNNullable:     func() FieldRule { return &FRNullable{} },
+	FRNDefault:      func() FieldRule { return &FRDefault{} },
 }
 
-// FRRegexp 字段规则正则表达式
-var FRRegexp = regexp.MustCompile(`^(` + NamePattern + `)(?:` + FRNameValueSep + `([^\s]+))?$`)
+// builtinFieldRules 内置字段规则名称
+var builtinFieldRules = func() map[string]bool {
+	m := make(map[string]bool, len(frCreators))
+	for name := range frCreators {
+		m[name] = true
+	}
+	return m
+}()
+
+// IsBuiltinFieldRule 是否内置字段规则
+func IsBuiltinFieldRule(name string) bool {
+	return builtinFieldRules[strings.ToUpper(name)]
+}
+
+// FieldRuleNames 获取所有已注册的字段规则名称, 升序排列
+func FieldRuleNames() []string {
+	frCreatorsMu.RLock()
+	defer frCreatorsMu.RUnlock()
+	names := make([]string, 0, len(frCreators))
+	for name := range frCreators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterFieldRule 注册自定义字段规则.
+// name 为规则名称, 不区分大小写, 不能与已注册的规则重复.
+// 规则可实现 FRFieldValidator/FRValueValidator/FREntryValidator/FRTableValidator 参与校验.
+// 开启增量解析缓存时, 内容未变化的配置表仍会调用自定义规则的 FREntryValidator/FRTableValidator,
+// 可用于依赖配置内容以外状态的校验; FRValueValidator 的结果随解析结果缓存, 应仅取决于值本身.
+func RegisterFieldRule(name string, creator func() FieldRule) {
+	if !frNameRegexp.MatchString(name) {
+		panic("gexcels: RegisterFieldRule: name " + name + " invalid")
+	}
+	if creator == nil {
+		panic("gexcels: RegisterFieldRule: creator is nil")
+	}
+	name = strings.ToUpper(name)
+	frCreatorsMu.Lock()
+	defer frCreatorsMu.Unlock()
+	if _, ok := frCreators[name]; ok {
+		panic("gexcels: RegisterFieldRule: rule " + name + " duplicate")
+	}
+	frCreators[name] = creator
+}
+
+// getFRCreator 获取字段规则构造器
+func getFRCreator(name string) func() FieldRule {
+	frCreatorsMu.RLock()
+	defer frCreatorsMu.RUnlock()
+	return frCreators[name]
+}
+
+// FRRegexp 字段规则正则表达式, 规则值可包含空白字符, e.g. CHECK 表达式
+var FRRegexp = regexp.MustCompile(`^(` + NamePattern + `)(?:` + FRNameValueSep + `(\S.*))?$`)
 
 // ParseFieldRule 解析字段规则
 func ParseFieldRule(s string) (FieldRule, error) {
@@ -60,7 +161,7 @@ func ParseFieldRule(s string) (FieldRule, error) {
 	frName := strings.ToUpper(matches[1])
 	frValue := matches[2]
 
-	creator := frCreators[frName]
+	creator := getFRCreator(frName)
 	if creator == nil {
 		return nil, fmt.Errorf("gexcels: field-rule %s not exist", frName)
 	}
@@ -73,6 +174,11 @@ func ParseFieldRule(s string) (FieldRule, error) {
 	return fr, nil
 }
 
+// errFROnFieldType 生成表示字段规则不能作用于字段类型的错误
+func errFROnFieldType(ruleName string, field *Field, expected string) error {
+	return fmt.Errorf("gexcels: field-rule %s on non-%s field %s", ruleName, expected, field.Name)
+}
+
 // errFRValueInvalid 生成表示字段规则值无效的错误
 func errFRValueInvalid(ruleName string, value string, f string, args ...any) error {
 	return fmt.Errorf("gexcels: field-rule %s value %s invalid, %s", ruleName, value, fmt.Sprintf(f, args...))
@@ -108,6 +214,10 @@ func (r *FRUnique) String() string { return FRNUnique }
 //
 //	Table.Field 表示字段的叶子值需要链接的表名和字段名.
 //	kx:Table.Field 表示字段中的第x层级的map键需要映射到的表名和字段名，其中x>=1.
+//	Table.Field[Filter] 链接的目标条目还需满足过滤条件, 条件语法与 CHECK 一致, e.g. Item.ID[Type == Weapon].
+//	Table.(K1,K2=Src) 表示链接目标表的组合键, K1,K2 为组合键的字段, 需与组合键字段及顺序一致.
+//	  组合键各字段的值取自同名的源字段, 也可以通过 K2=Src 指定源字段名.
+//	  叶子值为结构体时, 源字段为结构体字段; 否则规则需定义在配置表字段上, 源字段为配置表字段.
 type FRLink struct {
 	Value *FRLinkTarget         // 叶子值链接目标
 	Keys  map[int]*FRLinkTarget // 各层级map键链接目标，key为map层级
@@ -115,12 +225,43 @@ type FRLink struct {
 
 // FRLinkTarget 链接规则目标
 type FRLinkTarget struct {
-	TableName string // 目标配置表名
-	FieldName string // 目标字段名
+	TableName string   // 目标配置表名
+	FieldName string   // 目标字段名, 链接组合键时为空
+	KeyFields []string // 目标组合键字段, 链接组合键时有效
+	SrcFields []string // 组合键字段对应的源字段名, 与 KeyFields 一一对应
+	Filter    string   // 目标条目过滤条件, 为空时不过滤
+}
+
+// IsCompositeKey 是否链接组合键
+func (t *FRLinkTarget) IsCompositeKey() bool {
+	return len(t.KeyFields) > 0
+}
+
+func (t *FRLinkTarget) String() string {
+	s := t.TableName + "."
+	if t.IsCompositeKey() {
+		keys := make([]string, len(t.KeyFields))
+		for i, key := range t.KeyFields {
+			keys[i] = key
+			if t.SrcFields[i] != key {
+				keys[i] += FRNameValueSep + t.SrcFields[i]
+			}
+		}
+		s += "(" + strings.Join(keys, FRValueSep) + ")"
+	} else {
+		s += t.FieldName
+	}
+	if t.Filter != "" {
+		s += "[" + t.Filter + "]"
+	}
+	return s
 }
 
 // frLinkPairRegexp 字段链接规则值分段正则表达式
-var frLinkPairRegexp = regexp.MustCompile(`^(?:(` + NamePattern + `):)?(` + NamePattern + `)\.(` + NamePattern + `)$`)
+var frLinkPairRegexp = regexp.MustCompile(`^(?:(` + NamePattern + `):)?(` + NamePattern + `)\.(?:(` + NamePattern + `)|\(([^()]+)\))(?:\[(.+)\])?$`)
+
+// frLinkKeyFieldRegexp 组合键链接目标字段正则表达式
+var frLinkKeyFieldRegexp = regexp.MustCompile(`^(` + NamePattern + `)(?:\s*` + FRNameValueSep + `\s*(` + NamePattern + `))?$`)
 
 // NewFRLink 创建链接规则
 func NewFRLink(tableName, fieldName string) *FRLink {
@@ -138,6 +279,28 @@ func NewFRLink(tableName, fieldName string) *FRLink {
 	}
 }
 
+// NewFRLinkCompositeKey 创建链接组合键的链接规则, 源字段与组合键字段同名
+func NewFRLinkCompositeKey(tableName string, keyFields ...string) *FRLink {
+	if !MatchName(tableName) {
+		panic("gexcels: NewFRLinkCompositeKey: tableName " + tableName + " invalid")
+	}
+	if len(keyFields) == 0 {
+		panic("gexcels: NewFRLinkCompositeKey: keyFields empty")
+	}
+	for _, key := range keyFields {
+		if !MatchName(key) {
+			panic("gexcels: NewFRLinkCompositeKey: keyField " + key + " invalid")
+		}
+	}
+	return &FRLink{
+		Value: &FRLinkTarget{
+			TableName: tableName,
+			KeyFields: keyFields,
+			SrcFields: keyFields,
+		},
+	}
+}
+
 func (r *FRLink) FRName() string { return FRNLink }
 
 func (r *FRLink) FRKey() string {
@@ -160,14 +323,54 @@ func (r *FRLink) addKeyTarget(level int, target *FRLinkTarget) bool {
 func parseLinkValue(s string) (string, *FRLinkTarget, bool) {
 	s = strings.TrimSpace(s)
 	matches := frLinkPairRegexp.FindStringSubmatch(s)
-	if len(matches) < 3 {
+	if matches == nil {
 		return "", nil, false
 	}
-	if len(matches) > 3 {
-		return strings.ToLower(matches[1]), &FRLinkTarget{TableName: matches[2], FieldName: matches[3]}, true
-	} else {
-		return "", &FRLinkTarget{TableName: matches[1], FieldName: matches[2]}, true
+	target := &FRLinkTarget{
+		TableName: matches[2],
+		FieldName: matches[3],
+		Filter:    strings.TrimSpace(matches[5]),
+	}
+	if matches[4] != "" {
+		fields := make(map[string]bool)
+		for _, key := range strings.Split(matches[4], FRValueSep) {
+			keyMatches := frLinkKeyFieldRegexp.FindStringSubmatch(strings.TrimSpace(key))
+			if keyMatches == nil || fields[keyMatches[1]] {
+				return "", nil, false
+			}
+			fields[keyMatches[1]] = true
+			src := keyMatches[2]
+			if src == "" {
+				src = keyMatches[1]
+			}
+			target.KeyFields = append(target.KeyFields, keyMatches[1])
+			target.SrcFields = append(target.SrcFields, src)
+		}
+	}
+	return strings.ToLower(matches[1]), target, true
+}
+
+// splitLinkValue 按 FRValueSep 拆分链接规则值, 忽略过滤条件以及组合键字段中的分隔符
+func splitLinkValue(value string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case FRValueSep[0]:
+			if depth == 0 {
+				parts = append(parts, value[start:i])
+				start = i + 1
+			}
+		}
 	}
+	return append(parts, value[start:])
 }
 
 func (r *FRLink) ParseValue(value string) error {
@@ -178,7 +381,7 @@ func (r *FRLink) ParseValue(value string) error {
 
 	r.Value = nil
 	r.Keys = nil
-	parts := strings.Split(value, FRValueSep)
+	parts := splitLinkValue(value)
 	for _, part := range parts {
 		kindPrefix, target, ok := parseLinkValue(part)
 		if !ok {
@@ -197,6 +400,9 @@ func (r *FRLink) ParseValue(value string) error {
 			if err != nil {
 				return errFRValueInvalid(FRNLink, value, "kx:Table.Field")
 			}
+			if target.IsCompositeKey() {
+				return errFRValueInvalid(FRNLink, value, "composite-key target on k%d", level)
+			}
 			if !r.addKeyTarget(level, target) {
 				return errFRValueInvalid(FRNLink, value, "duplicate k%d:Table.Field", level)
 			}
@@ -215,9 +421,7 @@ func (r *FRLink) String() string {
 
 	first := true
 	if r.Value != nil {
-		sb.WriteString(r.Value.TableName)
-		sb.WriteString(".")
-		sb.WriteString(r.Value.FieldName)
+		sb.WriteString(r.Value.String())
 		first = false
 	}
 
@@ -234,10 +438,7 @@ func (r *FRLink) String() string {
 			sb.WriteString("k")
 			sb.WriteString(strconv.Itoa(level))
 			sb.WriteString("=")
-			t := r.Keys[level]
-			sb.WriteString(t.TableName)
-			sb.WriteString(".")
-			sb.WriteString(t.FieldName)
+			sb.WriteString(r.Keys[level].String())
 			first = false
 		}
 	}
@@ -336,3 +537,682 @@ func (r *FRGroup) ParseValue(value string) error {
 func (r *FRGroup) String() string {
 	return FRNGroup + FRNameValueSep + r.GroupName + FRValueSep + strconv.Itoa(r.Index)
 }
+
+// FRRange 数值范围规则, 限制数值字段的取值范围.
+// 作用于数值字段, 以及数值数组的元素和数值 map 的值.
+// 格式: RANGE=min,max 表示闭区间; 可使用'['/']'表示闭区间, '('/')'表示开区间; 省略边界表示无限制.
+// .e.g: RANGE=0,1  RANGE=[0,1)  RANGE=(0,]  RANGE=1,
+type FRRange struct {
+	Min     float64 // 下界
+	Max     float64 // 上界
+	HasMin  bool    // 是否具备下界
+	HasMax  bool    // 是否具备上界
+	MinOpen bool    // 下界是否为开区间
+	MaxOpen bool    // 上界是否为开区间
+
+	minText string // 下界原文, 整数字段按其精确比较
+	maxText string // 上界原文
+
+	// 整数及 FTDecimal 字段按整数精确比较, 边界转换为闭区间; FTDecimal 边界按 10^scale 放大
+	integer    bool
+	unsigned   bool
+	iMin, iMax int64
+	uMin, uMax uint64
+}
+
+// NewFRRange 创建闭区间 [min,max] 数值范围规则
+func NewFRRange(min, max float64) *FRRange {
+	if min > max {
+		panic("gexcels: NewFRRange: min greater than max")
+	}
+	return &FRRange{
+		Min:     min,
+		Max:     max,
+		HasMin:  true,
+		HasMax:  true,
+		minText: strconv.FormatFloat(min, 'f', -1, 64),
+		maxText: strconv.FormatFloat(max, 'f', -1, 64),
+	}
+}
+
+func (r *FRRange) FRName() string { return FRNRange }
+
+func (r *FRRange) FRKey() string { return FRNRange }
+
+func (r *FRRange) ParseValue(value string) error {
+	const example = "e.g. min,max [min,max) (min,"
+
+	s := strings.TrimSpace(value)
+	*r = FRRange{}
+	if strings.HasPrefix(s, "[") {
+		s = s[1:]
+	} else if strings.HasPrefix(s, "(") {
+		r.MinOpen = true
+		s = s[1:]
+	}
+	if strings.HasSuffix(s, "]") {
+		s = s[:len(s)-1]
+	} else if strings.HasSuffix(s, ")") {
+		r.MaxOpen = true
+		s = s[:len(s)-1]
+	}
+
+	bounds := strings.Split(s, FRValueSep)
+	if len(bounds) != 2 {
+		return errFRValueInvalid(FRNRange, value, example)
+	}
+	if bound := strings.TrimSpace(bounds[0]); bound != "" {
+		f, err := strconv.ParseFloat(bound, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return errFRValueInvalid(FRNRange, value, "min %s invalid", bound)
+		}
+		r.Min, r.HasMin, r.minText = f, true, bound
+	}
+	if bound := strings.TrimSpace(bounds[1]); bound != "" {
+		f, err := strconv.ParseFloat(bound, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return errFRValueInvalid(FRNRange, value, "max %s invalid", bound)
+		}
+		r.Max, r.HasMax, r.maxText = f, true, bound
+	}
+	if !r.HasMin && !r.HasMax {
+		return errFRValueInvalid(FRNRange, value, example)
+	}
+	if r.HasMin && r.HasMax && boundRat(r.minText, r.Min).Cmp(boundRat(r.maxText, r.Max)) > 0 {
+		return errFRValueInvalid(FRNRange, value, "min greater than max")
+	}
+
+	return nil
+}
+
+// Contains 数值 v 是否在范围内
+func (r *FRRange) Contains(v float64) bool {
+	if r.HasMin && (v < r.Min || (r.MinOpen && v == r.Min)) {
+		return false
+	}
+	if r.HasMax && (v > r.Max || (r.MaxOpen && v == r.Max)) {
+		return false
+	}
+	return true
+}
+
+// containsFloat32 float32 值 v 是否在范围内.
+// 边界按 float32 精度比较, 否则如 float32(0.1) 扩展为 float64 后大于边界 0.1.
+func (r *FRRange) containsFloat32(v float32) bool {
+	if r.HasMin {
+		min := float32(r.Min)
+		if v < min || (r.MinOpen && v == min) {
+			return false
+		}
+	}
+	if r.HasMax {
+		max := float32(r.Max)
+		if v > max || (r.MaxOpen && v == max) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *FRRange) ValidateField(field *Field) error {
+	leaf := field.LeafType()
+	if !leaf.Type.Numeric() {
+		return errFROnFieldType(FRNRange, field, "numeric")
+	}
+
+	var scale int
+	switch {
+	case leaf.Type == FTDecimal:
+		scale = leaf.GetScale()
+	case leaf.Type.Integer():
+	default:
+		r.integer = false
+		return nil
+	}
+	r.integer, r.unsigned = true, leaf.Type.Unsigned()
+	lo, hi := big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64)
+	if r.unsigned {
+		lo, hi = new(big.Int), new(big.Int).SetUint64(math.MaxUint64)
+	}
+	min, max := r.integerBounds(scale, lo, hi)
+	if min.Cmp(max) > 0 {
+		return errFRValueInvalid(FRNRange, r.RangeString(), "no %s value in range", leaf.Type)
+	}
+	if r.unsigned {
+		r.uMin, r.uMax = min.Uint64(), max.Uint64()
+	} else {
+		r.iMin, r.iMax = min.Int64(), max.Int64()
+	}
+	return nil
+}
+
+// integerBounds 计算整数字段的闭区间边界, 边界按 10^scale 放大后取整, 并限制在 [lo,hi] 内
+func (r *FRRange) integerBounds(scale int, lo, hi *big.Int) (min, max *big.Int) {
+	pow := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+	min, max = lo, hi
+	if r.HasMin {
+		b := boundRat(r.minText, r.Min)
+		b.Mul(b, pow)
+		// 向下取整, 开区间或非整数时取下一个整数
+		n := new(big.Int).Div(b.Num(), b.Denom())
+		if r.MinOpen || !b.IsInt() {
+			n.Add(n, big.NewInt(1))
+		}
+		if n.Cmp(min) > 0 {
+			min = n
+		}
+	}
+	if r.HasMax {
+		b := boundRat(r.maxText, r.Max)
+		b.Mul(b, pow)
+		n := new(big.Int).Div(b.Num(), b.Denom())
+		if r.MaxOpen && b.IsInt() {
+			n.Sub(n, big.NewInt(1))
+		}
+		if n.Cmp(max) < 0 {
+			max = n
+		}
+	}
+	return min, max
+}
+
+// boundRat 将边界原文精确转换为有理数, 原文缺失或无法精确解析时使用 f
+func boundRat(text string, f float64) *big.Rat {
+	if text != "" {
+		if b, ok := new(big.Rat).SetString(text); ok {
+			return b
+		}
+	}
+	return new(big.Rat).SetFloat64(f)
+}
+
+// numericFloat64 将数值 value 转换为 float64, value 非数值时 ok 为 false
+func numericFloat64(value any) (f float64, ok bool) {
+	switch v := value.(type) {
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// ValidateValue 整数及 FTDecimal 字段按整数精确比较, float32 值按 float32 精度比较, 其余按 float64 比较
+func (r *FRRange) ValidateValue(value any) error {
+	var ok bool
+	if r.integer {
+		switch v := value.(type) {
+		case int8:
+			ok = r.containsInt(int64(v))
+		case int16:
+			ok = r.containsInt(int64(v))
+		case int32:
+			ok = r.containsInt(int64(v))
+		case int64:
+			ok = r.containsInt(v)
+		case uint8:
+			ok = r.containsUint(uint64(v))
+		case uint16:
+			ok = r.containsUint(uint64(v))
+		case uint32:
+			ok = r.containsUint(uint64(v))
+		case uint64:
+			ok = r.containsUint(v)
+		default:
+			return nil
+		}
+	} else if v, isFloat32 := value.(float32); isFloat32 {
+		ok = r.containsFloat32(v)
+	} else {
+		f, numeric := numericFloat64(value)
+		if !numeric {
+			return nil
+		}
+		ok = r.Contains(f)
+	}
+	if !ok {
+		return fmt.Errorf("out of range %s", r.RangeString())
+	}
+	return nil
+}
+
+// containsInt 有符号整数字段的值 v 是否在范围内
+func (r *FRRange) containsInt(v int64) bool {
+	if r.unsigned {
+		return v >= 0 && r.containsUint(uint64(v))
+	}
+	return v >= r.iMin && v <= r.iMax
+}
+
+// containsUint 无符号整数字段的值 v 是否在范围内
+func (r *FRRange) containsUint(v uint64) bool {
+	if !r.unsigned {
+		return v <= math.MaxInt64 && r.containsInt(int64(v))
+	}
+	return v >= r.uMin && v <= r.uMax
+}
+
+// RangeString 输出区间形式, e.g. [0,1)
+func (r *FRRange) RangeString() string {
+	var sb strings.Builder
+	if r.MinOpen {
+		sb.WriteString("(")
+	} else {
+		sb.WriteString("[")
+	}
+	if r.HasMin {
+		sb.WriteString(boundText(r.minText, r.Min))
+	}
+	sb.WriteString(FRValueSep)
+	if r.HasMax {
+		sb.WriteString(boundText(r.maxText, r.Max))
+	}
+	if r.MaxOpen {
+		sb.WriteString(")")
+	} else {
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
+
+// boundText 边界的输出形式, 优先使用原文
+func boundText(text string, f float64) string {
+	if text != "" {
+		return text
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func (r *FRRange) String() string {
+	return FRNRange + FRNameValueSep + r.RangeString()
+}
+
+// FRRegex 正则规则, 字符串值必须匹配正则表达式.
+// 作用于字符串字段, 以及字符串数组的元素和字符串 map 的值.
+// 正则表达式可包含空白字符, 但首尾空白会被忽略(可使用\s代替); 包含字段规则分隔符时需修改分隔符.
+// .e.g: REGEX=^[a-z_]+$  REGEX=^[A-Z][a-z]+ [A-Z][a-z]+$
+type FRRegex struct {
+	Pattern string // 正则表达式
+
+	regexp *regexp.Regexp
+}
+
+// NewFRRegex 创建正则规则
+func NewFRRegex(pattern string) *FRRegex {
+	return &FRRegex{
+		Pattern: pattern,
+		regexp:  regexp.MustCompile(pattern),
+	}
+}
+
+func (r *FRRegex) FRName() string { return FRNRegex }
+
+func (r *FRRegex) FRKey() string { return FRNRegex }
+
+func (r *FRRegex) ParseValue(value string) error {
+	if value == "" {
+		return errFRValueInvalid(FRNRegex, value, "e.g. ^[a-z_]+$")
+	}
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return errFRValueInvalid(FRNRegex, value, "%v", err)
+	}
+	r.Pattern = value
+	r.regexp = re
+	return nil
+}
+
+// Match 字符串 s 是否匹配
+func (r *FRRegex) Match(s string) bool {
+	return r.regexp.MatchString(s)
+}
+
+func (r *FRRegex) ValidateField(field *Field) error {
+	return validateStringRuleField(FRNRegex, field)
+}
+
+func (r *FRRegex) ValidateValue(value any) error {
+	if s, ok := value.(string); ok && !r.Match(s) {
+		return fmt.Errorf("not match %s", r.Pattern)
+	}
+	return nil
+}
+
+func (r *FRRegex) String() string {
+	return FRNRegex + FRNameValueSep + r.Pattern
+}
+
+// FRLen 长度规则, 限制字符串的字符数量, 闭区间.
+// 作用于字符串字段, 以及字符串数组的元素和字符串 map 的值.
+// .e.g: LEN=1,16  LEN=,32  LEN=2,
+type FRLen struct {
+	Min    int  // 最小长度
+	Max    int  // 最大长度
+	HasMax bool // 是否具备最大长度
+}
+
+// NewFRLen 创建长度规则
+func NewFRLen(min, max int) *FRLen {
+	if min < 0 || min > max {
+		panic("gexcels: NewFRLen: min or max invalid")
+	}
+	return &FRLen{
+		Min:    min,
+		Max:    max,
+		HasMax: true,
+	}
+}
+
+func (r *FRLen) FRName() string { return FRNLen }
+
+func (r *FRLen) FRKey() string { return FRNLen }
+
+func (r *FRLen) ParseValue(value string) error {
+	const example = "e.g. min,max ,max min,"
+
+	*r = FRLen{}
+	bounds := strings.Split(strings.TrimSpace(value), FRValueSep)
+	if len(bounds) != 2 {
+		return errFRValueInvalid(FRNLen, value, example)
+	}
+	if bound := strings.TrimSpace(bounds[0]); bound != "" {
+		n, err := strconv.Atoi(bound)
+		if err != nil || n < 0 {
+			return errFRValueInvalid(FRNLen, value, "min %s invalid", bound)
+		}
+		r.Min = n
+	}
+	if bound := strings.TrimSpace(bounds[1]); bound != "" {
+		n, err := strconv.Atoi(bound)
+		if err != nil || n < 0 {
+			return errFRValueInvalid(FRNLen, value, "max %s invalid", bound)
+		}
+		r.Max, r.HasMax = n, true
+	}
+	if r.Min == 0 && !r.HasMax {
+		return errFRValueInvalid(FRNLen, value, example)
+	}
+	if r.HasMax && r.Min > r.Max {
+		return errFRValueInvalid(FRNLen, value, "min greater than max")
+	}
+	return nil
+}
+
+// Contains 长度 n 是否在范围内
+func (r *FRLen) Contains(n int) bool {
+	return n >= r.Min && (!r.HasMax || n <= r.Max)
+}
+
+func (r *FRLen) ValidateField(field *Field) error {
+	return validateStringRuleField(FRNLen, field)
+}
+
+func (r *FRLen) ValidateValue(value any) error {
+	if s, ok := value.(string); ok {
+		if n := utf8.RuneCountInString(s); !r.Contains(n) {
+			return fmt.Errorf("length %d out of range %s", n, r.RangeString())
+		}
+	}
+	return nil
+}
+
+// RangeString 输出区间形式, e.g. [1,16]
+func (r *FRLen) RangeString() string {
+	s := "[" + strconv.Itoa(r.Min) + FRValueSep
+	if r.HasMax {
+		s += strconv.Itoa(r.Max)
+	}
+	return s + "]"
+}
+
+func (r *FRLen) String() string {
+	s := FRNLen + FRNameValueSep + strconv.Itoa(r.Min) + FRValueSep
+	if r.HasMax {
+		s += strconv.Itoa(r.Max)
+	}
+	return s
+}
+
+// FRNotEmpty 非空规则, 字符串值不能为空.
+// 作用于字符串字段, 以及字符串数组的元素和字符串 map 的值.
+// .e.g: NOTEMPTY
+type FRNotEmpty struct{}
+
+// NewFRNotEmpty 创建非空规则
+func NewFRNotEmpty() *FRNotEmpty {
+	return &FRNotEmpty{}
+}
+
+func (r *FRNotEmpty) FRName() string { return FRNNotEmpty }
+
+func (r *FRNotEmpty) FRKey() string { return FRNNotEmpty }
+
+// ErrFRNotEmptyMustNoValue 指定 FRNotEmpty 规则不能具备value的错误
+var ErrFRNotEmptyMustNoValue = errors.New("gexcels: field-rule notempty must no value")
+
+func (r *FRNotEmpty) ParseValue(value string) error {
+	if value != "" {
+		return ErrFRNotEmptyMustNoValue
+	}
+	return nil
+}
+
+func (r *FRNotEmpty) ValidateField(field *Field) error {
+	return validateStringRuleField(FRNNotEmpty, field)
+}
+
+// ErrFRNotEmptyValueEmpty 字段值为空
+var ErrFRNotEmptyValueEmpty = errors.New("gexcels: field-rule notempty value empty")
+
+func (r *FRNotEmpty) ValidateValue(value any) error {
+	if s, ok := value.(string); ok && s == "" {
+		return ErrFRNotEmptyValueEmpty
+	}
+	return nil
+}
+
+func (r *FRNotEmpty) String() string { return FRNNotEmpty }
+
+// validateStringRuleField 检查字符串规则能否作用于字段
+func validateStringRuleField(ruleName string, field *Field) error {
+	if leaf := field.LeafType().Type; leaf != FTString && leaf != FTText {
+		return errFROnFieldType(ruleName, field, "string")
+	}
+	return nil
+}
+
+// FRCheck 条目检查规则, 配置表的每个条目需满足表达式.
+// 表达式可引用配置表的所有字段, 与规则所在字段无关.
+// 由于表达式可能包含字段规则分隔符, CHECK 需位于规则单元格的末尾.
+// .e.g: CHECK=MinLevel <= MaxLevel
+type FRCheck struct {
+	Expr string // 表达式
+}
+
+// NewFRCheck 创建条目检查规则
+func NewFRCheck(expr string) *FRCheck {
+	return &FRCheck{Expr: expr}
+}
+
+func (r *FRCheck) FRName() string { return FRNCheck }
+
+func (r *FRCheck) FRKey() string { return FRNCheck + FRNameValueSep + r.Expr }
+
+func (r *FRCheck) ParseValue(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return errFRValueInvalid(FRNCheck, value, "e.g. MinLevel <= MaxLevel")
+	}
+	r.Expr = value
+	return nil
+}
+
+func (r *FRCheck) String() string { return FRNCheck + FRNameValueSep + r.Expr }
+
+// FRNullable 可空规则, 配合 LINK 使用, 值为空值时不检查链接, 生成的链接访问方法返回空.
+// 未指定空值时以零值作为空值, 否则仅指定的值为空值.
+// 作用于整数和字符串字段, 以及对应数组的元素和 map 的值.
+// .e.g: NULLABLE  NULLABLE=-1  NULLABLE=0,-1
+type FRNullable struct {
+	Values []string // 空值, 未指定时为零值
+}
+
+// NewFRNullable 创建可空规则
+func NewFRNullable(values ...string) *FRNullable {
+	return &FRNullable{Values: values}
+}
+
+func (r *FRNullable) FRName() string { return FRNNullable }
+
+func (r *FRNullable) FRKey() string { return FRNNullable }
+
+func (r *FRNullable) ParseValue(value string) error {
+	r.Values = nil
+	if value == "" {
+		return nil
+	}
+	for _, v := range strings.Split(value, FRValueSep) {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return errFRValueInvalid(FRNNullable, value, "e.g. 0,-1")
+		}
+		r.Values = append(r.Values, v)
+	}
+	return nil
+}
+
+func (r *FRNullable) ValidateField(field *Field) error {
+	switch leaf := field.LeafType(); leaf.Type {
+	case FTInt32, FTInt64, FTInt8, FTInt16, FTUint8, FTUint16, FTUint32, FTUint64:
+		for _, v := range r.Values {
+			var err error
+			if leaf.Type.Unsigned() {
+				_, err = strconv.ParseUint(v, 10, leaf.Type.BitSize())
+			} else {
+				_, err = strconv.ParseInt(v, 10, leaf.Type.BitSize())
+			}
+			if err != nil {
+				return errFRValueInvalid(FRNNullable, v, "not %s", leaf.Type)
+			}
+		}
+	case FTString:
+	default:
+		return errFROnFieldType(FRNNullable, field, "integer or string")
+	}
+	return nil
+}
+
+// IsNull 值 value 是否为空值
+func (r *FRNullable) IsNull(value any) bool {
+	switch v := value.(type) {
+	case int8:
+		return r.isNullInt(int64(v))
+	case int16:
+		return r.isNullInt(int64(v))
+	case int32:
+		return r.isNullInt(int64(v))
+	case int64:
+		return r.isNullInt(v)
+	case uint8:
+		return r.isNullUint(uint64(v))
+	case uint16:
+		return r.isNullUint(uint64(v))
+	case uint32:
+		return r.isNullUint(uint64(v))
+	case uint64:
+		return r.isNullUint(v)
+	case string:
+		if len(r.Values) == 0 {
+			return v == ""
+		}
+		for _, s := range r.Values {
+			if s == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *FRNullable) isNullInt(v int64) bool {
+	if len(r.Values) == 0 {
+		return v == 0
+	}
+	for _, s := range r.Values {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil && n == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *FRNullable) isNullUint(v uint64) bool {
+	if len(r.Values) == 0 {
+		return v == 0
+	}
+	for _, s := range r.Values {
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil && n == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *FRNullable) String() string {
+	if len(r.Values) == 0 {
+		return FRNNullable
+	}
+	return FRNNullable + FRNameValueSep + strings.Join(r.Values, FRValueSep)
+}
+
+// FRDefault 默认值规则, 单元格未填写时使用默认值代替零值.
+// 默认值按字段类型解析, 格式与单元格一致, 且需满足字段的其它规则.
+// 作用于配置表字段、全局配置表字段以及结构体字段, 不能作用于可选字段.
+// .e.g: DEFAULT=100  DEFAULT=Normal  DEFAULT=[1,2]
+type FRDefault struct {
+	Value string // 默认值
+}
+
+// NewFRDefault 创建默认值规则
+func NewFRDefault(value string) *FRDefault {
+	return &FRDefault{Value: value}
+}
+
+func (r *FRDefault) FRName() string { return FRNDefault }
+
+func (r *FRDefault) FRKey() string { return FRNDefault }
+
+func (r *FRDefault) ParseValue(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return errFRValueInvalid(FRNDefault, value, "e.g. 100")
+	}
+	r.Value = value
+	return nil
+}
+
+func (r *FRDefault) ValidateField(field *Field) error {
+	if field.Optional {
+		return fmt.Errorf("gexcels: field-rule %s on optional field %s", FRNDefault, field.Name)
+	}
+	return nil
+}
+
+func (r *FRDefault) String() string { return FRNDefault + FRNameValueSep + r.Value }
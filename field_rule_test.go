@@ -1,6 +1,11 @@
 package gexcels
 
-import "testing"
+import (
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+)
 
 func TestFieldRule(t *testing.T) {
 	frUnique := NewFRUnique()
@@ -43,3 +48,325 @@ func TestFieldRule(t *testing.T) {
 	}
 	t.Log(fr)
 }
+
+func TestFRRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		valid   bool
+		in, out []float64
+	}{
+		{value: "0,1", valid: true, in: []float64{0, 0.5, 1}, out: []float64{-0.1, 1.1}},
+		{value: "[0,1)", valid: true, in: []float64{0, 0.99}, out: []float64{1}},
+		{value: "(0,]", valid: true, in: []float64{0.1, 1e10}, out: []float64{0, -1}},
+		{value: ",200", valid: true, in: []float64{-1e10, 200}, out: []float64{201}},
+		{value: "1,0"},
+		{value: ","},
+		{value: "a,1"},
+		{value: "1"},
+	}
+	for _, test := range tests {
+		fr, err := ParseFieldRule(FRNRange + FRNameValueSep + test.value)
+		if (err == nil) != test.valid {
+			t.Fatalf("parse %s, valid %v, error %v", test.value, test.valid, err)
+		}
+		if err != nil {
+			continue
+		}
+		r := fr.(*FRRange)
+		for _, v := range test.in {
+			if !r.Contains(v) {
+				t.Fatalf("%s not contains %v", r, v)
+			}
+		}
+		for _, v := range test.out {
+			if r.Contains(v) {
+				t.Fatalf("%s contains %v", r, v)
+			}
+		}
+		if fr2, err := ParseFieldRule(r.String()); err != nil || *fr2.(*FRRange) != *r {
+			t.Fatalf("reparse %s failed: %v", r, err)
+		}
+	}
+}
+
+func TestFRRangeInteger(t *testing.T) {
+	tests := []struct {
+		value   string
+		ti      *FieldTypeInfo
+		in, out []any
+	}{
+		{"0,9007199254740992", NewPrimitiveFieldTypeInfo(FTInt64), []any{int64(0), int64(9007199254740992)}, []any{int64(9007199254740993), int64(-1)}},
+		{"0,18446744073709551614", NewPrimitiveFieldTypeInfo(FTUint64), []any{uint64(18446744073709551614)}, []any{uint64(18446744073709551615)}},
+		{"(-9223372036854775808,", NewPrimitiveFieldTypeInfo(FTInt64), []any{int64(math.MinInt64 + 1)}, []any{int64(math.MinInt64)}},
+		{"(0.5,2.5)", NewPrimitiveFieldTypeInfo(FTInt32), []any{int32(1), int32(2)}, []any{int32(0), int32(3)}},
+		{"[0,1.5)", NewDecimalFieldTypeInfo(2), []any{int64(0), int64(149)}, []any{int64(-1), int64(150)}},
+	}
+	for _, test := range tests {
+		fr, err := ParseFieldRule(FRNRange + FRNameValueSep + test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := fr.(*FRRange)
+		if err := r.ValidateField(NewField("Value", "", test.ti)); err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range test.in {
+			if err := r.ValidateValue(v); err != nil {
+				t.Fatalf("%s %s: %v", r, test.ti, err)
+			}
+		}
+		for _, v := range test.out {
+			if r.ValidateValue(v) == nil {
+				t.Fatalf("%s %s contains %v", r, test.ti, v)
+			}
+		}
+	}
+
+	fr, err := ParseFieldRule("RANGE=,-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fr.(*FRRange).ValidateField(NewField("Value", "", NewPrimitiveFieldTypeInfo(FTUint32))); err == nil {
+		t.Fatal("empty range on uint32 accepted")
+	}
+}
+
+func TestFRStringRules(t *testing.T) {
+	fr, err := ParseFieldRule("REGEX=^[a-z_]+$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := fr.(*FRRegex); !r.Match("item_name") || r.Match("Item") {
+		t.Fatalf("%s match invalid", r)
+	}
+	if fr, err := ParseFieldRule("REGEX=^[A-Z][a-z]+ [A-Z][a-z]+$"); err != nil || !fr.(*FRRegex).Match("John Smith") {
+		t.Fatalf("regex with whitespace invalid: %v", err)
+	}
+	if _, err := ParseFieldRule("REGEX=[a-"); err == nil {
+		t.Fatalf("invalid regex accepted")
+	}
+
+	for value, valid := range map[string]bool{"1,16": true, ",32": true, "2,": true, ",": false, "3,1": false, "-1,2": false, "x": false} {
+		fr, err := ParseFieldRule(FRNLen + FRNameValueSep + value)
+		if (err == nil) != valid {
+			t.Fatalf("parse LEN=%s, valid %v, error %v", value, valid, err)
+		}
+		if err != nil {
+			continue
+		}
+		if fr2, err := ParseFieldRule(fr.String()); err != nil || *fr2.(*FRLen) != *fr.(*FRLen) {
+			t.Fatalf("reparse %s failed: %v", fr, err)
+		}
+	}
+	if r := NewFRLen(1, 2); r.Contains(0) || !r.Contains(2) || r.Contains(3) {
+		t.Fatalf("%s contains invalid", r)
+	}
+
+	if _, err := ParseFieldRule(FRNNotEmpty); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseFieldRule(FRNNotEmpty + "=1"); err != ErrFRNotEmptyMustNoValue {
+		t.Fatalf("NOTEMPTY with value: %v", err)
+	}
+}
+
+type testFRCustom struct{ value string }
+
+func (r *testFRCustom) FRName() string { return "CUSTOM" }
+
+func (r *testFRCustom) FRKey() string { return "CUSTOM" }
+
+func (r *testFRCustom) ParseValue(value string) error {
+	r.value = value
+	return nil
+}
+
+func (r *testFRCustom) String() string { return "CUSTOM=" + r.value }
+
+var registerTestFRCustom sync.Once
+
+func TestRegisterFieldRule(t *testing.T) {
+	registerTestFRCustom.Do(func() {
+		RegisterFieldRule("custom", func() FieldRule { return &testFRCustom{} })
+	})
+
+	fr, err := ParseFieldRule("CUSTOM=abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := fr.(*testFRCustom); !ok || r.value != "abc" {
+		t.Fatalf("parse custom rule %v", fr)
+	}
+
+	for _, name := range []string{"CUSTOM", FRNRange, "a-b"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("register %s not panic", name)
+				}
+			}()
+			RegisterFieldRule(name, func() FieldRule { return &testFRCustom{} })
+		}()
+	}
+}
+
+func TestFRLinkFilter(t *testing.T) {
+	fr, err := ParseFieldRule("LINK=Item.ID[Type == Weapon and len(Tags) > 1],k1:Skill.ID[Level >= 2]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := fr.(*FRLink)
+	if r.Value.TableName != "Item" || r.Value.FieldName != "ID" || r.Value.Filter != "Type == Weapon and len(Tags) > 1" {
+		t.Fatalf("value target %+v invalid", r.Value)
+	}
+	if k1 := r.Keys[1]; k1 == nil || k1.TableName != "Skill" || k1.Filter != "Level >= 2" {
+		t.Fatalf("k1 target %+v invalid", k1)
+	}
+
+	fr, err = ParseFieldRule("LINK=Item.ID[len(Tags, 1) > 0]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter := fr.(*FRLink).Value.Filter; filter != "len(Tags, 1) > 0" {
+		t.Fatalf("filter %s with separator invalid", filter)
+	}
+
+	if _, err := ParseFieldRule("LINK=Item.ID[]"); err == nil {
+		t.Fatalf("empty filter accepted")
+	}
+}
+
+func TestFRNullable(t *testing.T) {
+	fr, err := ParseFieldRule(FRNNullable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := fr.(*FRNullable)
+	if !r.IsNull(int32(0)) || !r.IsNull("") || r.IsNull(int64(-1)) || r.IsNull("none") {
+		t.Fatalf("%s null values invalid", r)
+	}
+
+	fr, err = ParseFieldRule("NULLABLE=-1, none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = fr.(*FRNullable)
+	if !r.IsNull(int64(-1)) || !r.IsNull("none") || r.IsNull(int32(0)) || r.IsNull("") {
+		t.Fatalf("%s null values invalid", r)
+	}
+	if r.String() != "NULLABLE=-1,none" {
+		t.Fatalf("%s string invalid", r)
+	}
+
+	if _, err := ParseFieldRule("NULLABLE=1,,2"); err == nil {
+		t.Fatalf("empty null value accepted")
+	}
+}
+
+func TestFRLinkCompositeKey(t *testing.T) {
+	fr, err := ParseFieldRule("LINK=Stage.(Chapter, Stage=Next)[Chapter > 1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := fr.(*FRLink)
+	if !r.Value.IsCompositeKey() || r.Value.TableName != "Stage" || r.Value.FieldName != "" ||
+		!reflect.DeepEqual(r.Value.KeyFields, []string{"Chapter", "Stage"}) ||
+		!reflect.DeepEqual(r.Value.SrcFields, []string{"Chapter", "Next"}) ||
+		r.Value.Filter != "Chapter > 1" {
+		t.Fatalf("value target %+v invalid", r.Value)
+	}
+	if s := r.String(); s != "LINK=Stage.(Chapter,Stage=Next)[Chapter > 1]" {
+		t.Fatalf("string %s invalid", s)
+	}
+	if s := NewFRLinkCompositeKey("Stage", "Chapter", "Stage").String(); s != "LINK=Stage.(Chapter,Stage)" {
+		t.Fatalf("string %s invalid", s)
+	}
+
+	for _, value := range []string{"Stage.()", "Stage.(A,A)", "Stage.(A=)", "k1:Stage.(A,B)"} {
+		if _, err := ParseFieldRule(FRNLink + FRNameValueSep + value); err == nil {
+			t.Fatalf("LINK=%s accepted", value)
+		}
+	}
+}
+
+func TestFRDefault(t *testing.T) {
+	fr, err := ParseFieldRule("DEFAULT=[1,2]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := fr.(*FRDefault)
+	if r.Value != "[1,2]" || r.String() != "DEFAULT=[1,2]" {
+		t.Fatalf("%s value invalid", r)
+	}
+	if _, err := ParseFieldRule(FRNDefault + FRNameValueSep); err == nil {
+		t.Fatalf("empty default value accepted")
+	}
+	if err := r.ValidateField(NewField("Count", "", NewOptionalFieldTypeInfo(NewPrimitiveFieldTypeInfo(FTInt32)))); err == nil {
+		t.Fatalf("DEFAULT on optional field accepted")
+	}
+}
+
+func TestFRIntegerTypes(t *testing.T) {
+	for s, bitSize := range map[string]int{"int8": 8, "int16": 16, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64} {
+		ft := ParsePrimitiveFieldType(s)
+		if !ft.Integer() || !ft.Numeric() || !ft.CanMapKey() || ft.BitSize() != bitSize || ft.Unsigned() != (s[0] == 'u') || ft.String() != s {
+			t.Fatalf("field type %s invalid", s)
+		}
+	}
+
+	// 新增类型追加在原有类型之后, 原有类型的值保持不变
+	for ft, value := range map[FieldType]int{FTInt32: 1, FTInt64: 2, FTFloat32: 3, FTFloat64: 4, FTBool: 5, FTString: 6, FTEnum: 7, FTStruct: 8, FTArray: 9, FTMap: 10} {
+		if int(ft) != value {
+			t.Fatalf("field type %s value %d, expected %d", ft, ft, value)
+		}
+	}
+
+	r := NewFRRange(0, 200)
+	if r.ValidateValue(uint8(200)) != nil || r.ValidateValue(uint8(201)) == nil || r.ValidateValue(int8(-1)) == nil {
+		t.Fatalf("%s validate small integers invalid", r)
+	}
+
+	fr, err := ParseFieldRule("NULLABLE=18446744073709551615")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nullable := fr.(*FRNullable)
+	if !nullable.IsNull(uint64(18446744073709551615)) || nullable.IsNull(uint64(0)) || !NewFRNullable().IsNull(uint8(0)) {
+		t.Fatalf("%s null values invalid", nullable)
+	}
+	field := NewField("Value", "", NewPrimitiveFieldTypeInfo(FTUint64))
+	if err := nullable.ValidateField(field); err != nil {
+		t.Fatal(err)
+	}
+	field = NewField("Value", "", NewPrimitiveFieldTypeInfo(FTInt8))
+	if err := nullable.ValidateField(field); err == nil {
+		t.Fatalf("NULLABLE=%s on int8 accepted", nullable.Values[0])
+	}
+}
+
+func TestFRDecimal(t *testing.T) {
+	ti, err := ParseFieldTypeInfo("decimal(2)?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.Type != FTDecimal || !ti.Optional || ti.GetScale() != 2 || ti.String() != "decimal(2)?" || !ti.Type.Numeric() || ti.Type.CanMapKey() {
+		t.Fatalf("field type %s invalid", ti)
+	}
+	for _, s := range []string{"decimal(19)", "map[decimal(2)]int32"} {
+		if _, err := ParseFieldTypeInfo(s); err == nil {
+			t.Fatalf("field type %s accepted", s)
+		}
+	}
+
+	fr, err := ParseFieldRule("RANGE=(0,1.5]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := fr.(*FRRange)
+	if err := r.ValidateField(NewField("Rate", "", NewArrayFieldTypeInfo(NewDecimalFieldTypeInfo(2)))); err != nil {
+		t.Fatal(err)
+	}
+	if r.ValidateValue(int64(150)) != nil || r.ValidateValue(int64(151)) == nil || r.ValidateValue(int64(0)) == nil {
+		t.Fatalf("%s validate decimal invalid", r)
+	}
+}
@@ -2,6 +2,8 @@ package gexcels
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -21,21 +23,45 @@ const (
 	FTStruct
 	FTArray
 	FTMap
+	FTInt8
+	FTInt16
+	FTUint8
+	FTUint16
+	FTUint32
+	FTUint64
+	FTDatetime
+	FTDuration
+	FTDecimal
+	FTUnion
+	FTTuple
+	FTText
 )
 
 // fieldTypeStrings 字段类型字符串映射
 var fieldTypeStrings = [...]string{
-	FTUnknown: "unknown",
-	FTInt32:   "int32",
-	FTInt64:   "int64",
-	FTFloat32: "float32",
-	FTFloat64: "float64",
-	FTBool:    "bool",
-	FTString:  "string",
-	FTEnum:    "enum",
-	FTStruct:  "struct",
-	FTArray:   "array",
-	FTMap:     "map",
+	FTUnknown:  "unknown",
+	FTInt32:    "int32",
+	FTInt64:    "int64",
+	FTFloat32:  "float32",
+	FTFloat64:  "float64",
+	FTBool:     "bool",
+	FTString:   "string",
+	FTEnum:     "enum",
+	FTStruct:   "struct",
+	FTArray:    "array",
+	FTMap:      "map",
+	FTInt8:     "int8",
+	FTInt16:    "int16",
+	FTUint8:    "uint8",
+	FTUint16:   "uint16",
+	FTUint32:   "uint32",
+	FTUint64:   "uint64",
+	FTDatetime: "datetime",
+	FTDuration: "duration",
+	FTDecimal:  "decimal",
+	FTUnion:    "union",
+	FTTuple:    "tuple",
+	FTText:     "text",
 }
 
 func (ft FieldType) String() string {
@@ -44,12 +70,22 @@ func (ft FieldType) String() string {
 
 // primitiveFieldTypeStrings primitive FieldType 到其字符形式的映射
 var primitiveFieldTypeStrings = map[FieldType]string{
-	FTInt32:   "int32",
-	FTInt64:   "int64",
-	FTFloat32: "float32",
-	FTFloat64: "float64",
-	FTBool:    "bool",
-	FTString:  "string",
+	FTInt32:    "int32",
+	FTInt64:    "int64",
+	FTInt8:     "int8",
+	FTInt16:    "int16",
+	FTUint8:    "uint8",
+	FTUint16:   "uint16",
+	FTUint32:   "uint32",
+	FTUint64:   "uint64",
+	FTFloat32:  "float32",
+	FTFloat64:  "float64",
+	FTBool:     "bool",
+	FTString:   "string",
+	FTDatetime: "datetime",
+	FTDuration: "duration",
+	FTText:     "text",
+	FTDecimal:  "decimal",
 }
 
 // Primitive 返回是否primitive类型
@@ -62,11 +98,69 @@ func (ft FieldType) PrimitiveString() string {
 	return primitiveFieldTypeStrings[ft]
 }
 
+// numericFieldTypes 数值类型映射
+var numericFieldTypes = map[FieldType]bool{
+	FTInt32:   true,
+	FTInt64:   true,
+	FTInt8:    true,
+	FTInt16:   true,
+	FTUint8:   true,
+	FTUint16:  true,
+	FTUint32:  true,
+	FTUint64:  true,
+	FTFloat32: true,
+	FTFloat64: true,
+	FTDecimal: true,
+}
+
+// Numeric 返回是否数值类型
+func (ft FieldType) Numeric() bool {
+	return numericFieldTypes[ft]
+}
+
+// integerFieldTypeBitSizes 整数类型位宽映射
+var integerFieldTypeBitSizes = map[FieldType]int{
+	FTInt32:  32,
+	FTInt64:  64,
+	FTInt8:   8,
+	FTInt16:  16,
+	FTUint8:  8,
+	FTUint16: 16,
+	FTUint32: 32,
+	FTUint64: 64,
+}
+
+// Integer 返回是否整数类型
+func (ft FieldType) Integer() bool {
+	return integerFieldTypeBitSizes[ft] > 0
+}
+
+// Unsigned 返回是否无符号整数类型
+func (ft FieldType) Unsigned() bool {
+	switch ft {
+	case FTUint8, FTUint16, FTUint32, FTUint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// BitSize 返回整数类型的位宽, 非整数类型返回0
+func (ft FieldType) BitSize() int {
+	return integerFieldTypeBitSizes[ft]
+}
+
 // mapKeyFieldTypes map key 类型映射
-// 仅支持 int32, int64, string, enum 类型
+// 仅支持整数, string, enum 类型
 var mapKeyFieldTypes = map[FieldType]bool{
 	FTInt32:  true,
 	FTInt64:  true,
+	FTInt8:   true,
+	FTInt16:  true,
+	FTUint8:  true,
+	FTUint16: true,
+	FTUint32: true,
+	FTUint64: true,
 	FTString: true,
 	FTEnum:   true,
 }
@@ -78,19 +172,108 @@ func (ft FieldType) CanMapKey() bool {
 
 // primitiveFieldStringTypes primitive FieldType 字符串到值映射
 var primitiveFieldStringTypes = map[string]FieldType{
-	"int32":   FTInt32,
-	"int64":   FTInt64,
-	"float32": FTFloat32,
-	"float64": FTFloat64,
-	"bool":    FTBool,
-	"string":  FTString,
+	"int32":    FTInt32,
+	"int64":    FTInt64,
+	"int8":     FTInt8,
+	"int16":    FTInt16,
+	"uint8":    FTUint8,
+	"uint16":   FTUint16,
+	"uint32":   FTUint32,
+	"uint64":   FTUint64,
+	"float32":  FTFloat32,
+	"float64":  FTFloat64,
+	"bool":     FTBool,
+	"string":   FTString,
+	"datetime": FTDatetime,
+	"duration": FTDuration,
+	"text":     FTText,
+	"i18n":     FTText, // FTText 别名
 }
 
-// ParsePrimitiveFieldType 解析 primitive FieldType 的字符形式, 返回类型 FieldType
+// ParsePrimitiveFieldType 解析 primitive FieldType 的字符形式, 返回类型 FieldType.
+// FTDecimal 需要指定小数位数, 通过 ParseDecimalFieldType 解析.
 func ParsePrimitiveFieldType(s string) FieldType {
 	return primitiveFieldStringTypes[s]
 }
 
+// MaxDecimalScale FTDecimal 最大小数位数, 保证缩放后的整数可由 int64 表示.
+const MaxDecimalScale = 18
+
+// decimalFieldTypeRegexp FTDecimal 类型的字符形式, e.g. decimal(2)
+var decimalFieldTypeRegexp = regexp.MustCompile(`^decimal\(\s*(\d+)\s*\)$`)
+
+// ParseDecimalFieldType 解析 FTDecimal 类型的字符形式, 返回小数位数.
+// s 不是 decimal(p) 形式时 ok 为 false.
+func ParseDecimalFieldType(s string) (scale int, ok bool, err error) {
+	matches := decimalFieldTypeRegexp.FindStringSubmatch(s)
+	if matches == nil {
+		return 0, false, nil
+	}
+	scale, err = strconv.Atoi(matches[1])
+	if err != nil || scale > MaxDecimalScale {
+		return 0, true, fmt.Errorf("gexcels: decimal scale %s must in [0,%d]", matches[1], MaxDecimalScale)
+	}
+	return scale, true, nil
+}
+
+// 内置元组类型名称, 元素均为 float32
+const (
+	TupleVec2    = "vec2"  // 二维向量 x,y
+	TupleVec3    = "vec3"  // 三维向量 x,y,z
+	TupleColor   = "color" // 颜色 r,g,b,a, 省略 a 时为1
+	TupleGeneric = "tuple" // 通用元组, e.g. tuple[int32,string]
+)
+
+// builtinTupleElementNames 内置元组类型的元素名称
+var builtinTupleElementNames = map[string][]string{
+	TupleVec2:  {"X", "Y"},
+	TupleVec3:  {"X", "Y", "Z"},
+	TupleColor: {"R", "G", "B", "A"},
+}
+
+// TupleElementSep 元组值紧凑写法的元素分隔符, e.g. 1.5,2,0
+const TupleElementSep = ","
+
+// TupleArraySep 元组数组值紧凑写法的元组分隔符, e.g. 1,2;3,4
+const TupleArraySep = ";"
+
+// MaxTupleElement 通用元组最大元素数量
+const MaxTupleElement = 8
+
+// ParseBuiltinTupleFieldType 解析内置元组类型 vec2, vec3, color, s 不是内置元组类型时返回 nil
+func ParseBuiltinTupleFieldType(s string) *FieldTypeInfo {
+	names := builtinTupleElementNames[s]
+	if names == nil {
+		return nil
+	}
+	elems := make([]*FieldTypeInfo, len(names))
+	for i := range elems {
+		elems[i] = NewPrimitiveFieldTypeInfo(FTFloat32)
+	}
+	return newTupleFieldTypeInfo(s, elems)
+}
+
+// SplitGenericTupleFieldType 分割通用元组类型 tuple[T1,T2,...] 的元素类型字符串.
+// s 不是 tuple[...] 形式时 ok 为 false.
+func SplitGenericTupleFieldType(s string) (elems []string, ok bool, err error) {
+	inner, ok := strings.CutPrefix(s, TupleGeneric+"[")
+	if !ok {
+		return nil, false, nil
+	}
+	inner, ok = strings.CutSuffix(inner, "]")
+	if !ok {
+		return nil, true, fmt.Errorf("gexcels: tuple type %s invalid", s)
+	}
+	elems = strings.Split(inner, TupleElementSep)
+	if len(elems) < 2 || len(elems) > MaxTupleElement {
+		return nil, true, fmt.Errorf("gexcels: tuple type %s element number must in [2,%d]", s, MaxTupleElement)
+	}
+	for i := range elems {
+		elems[i] = strings.TrimSpace(elems[i])
+	}
+	return elems, true, nil
+}
+
 // MaxStringLen 最大字符串长度 FTString.
 const MaxStringLen = 32767
 
@@ -100,16 +283,22 @@ const MaxElementLen = 32767
 // 字段类型参数索引.
 const (
 	_              = iota
-	ftpName        // 类型名称. for FTStruct, FTEnum
+	ftpName        // 类型名称. for FTStruct, FTUnion, FTEnum
 	ftpElementType // 元素类型. for FTArray
 	ftpMapKeyType  // map key type. for FTMap
 	ftpMapValType  // map value type. for FTMap
+	ftpScale       // 小数位数. for FTDecimal
+	ftpElements    // 元素类型列表. for FTTuple
 )
 
+// FTOptionalSuffix 可选类型后缀, 如 int32?
+const FTOptionalSuffix = "?"
+
 // FieldTypeInfo 字段类型信息
 type FieldTypeInfo struct {
-	Type   FieldType   // 类型
-	params map[int]any // 参数
+	Type     FieldType   // 类型
+	Optional bool        // 是否可选, 未填写的值为"未设置"而非零值. for primitive, FTEnum
+	params   map[int]any // 参数
 }
 
 // setName 设置类型名称
@@ -152,17 +341,80 @@ func (i *FieldTypeInfo) GetMapValueType() *FieldTypeInfo {
 	return i.params[ftpMapValType].(*FieldTypeInfo)
 }
 
+// setScale 设置小数位数
+func (i *FieldTypeInfo) setScale(scale int) {
+	i.params[ftpScale] = scale
+}
+
+// GetScale 获取小数位数
+func (i *FieldTypeInfo) GetScale() int {
+	return i.params[ftpScale].(int)
+}
+
+// setTupleElements 设置元组元素类型
+func (i *FieldTypeInfo) setTupleElements(elems []*FieldTypeInfo) {
+	i.params[ftpElements] = elems
+}
+
+// GetTupleElements 获取元组元素类型
+func (i *FieldTypeInfo) GetTupleElements() []*FieldTypeInfo {
+	return i.params[ftpElements].([]*FieldTypeInfo)
+}
+
+// GetTupleElementNames 获取元组元素名称, 内置元组为分量名称, e.g. X,Y,Z; 通用元组为 Item1,Item2...
+func (i *FieldTypeInfo) GetTupleElementNames() []string {
+	if names := builtinTupleElementNames[i.GetName()]; names != nil {
+		return names
+	}
+	names := make([]string, len(i.GetTupleElements()))
+	for j := range names {
+		names[j] = "Item" + strconv.Itoa(j+1)
+	}
+	return names
+}
+
+// LeafType 获取叶子类型, 即数组元素或 map 的值递归展开后的类型
+func (i *FieldTypeInfo) LeafType() *FieldTypeInfo {
+	switch i.Type {
+	case FTArray:
+		return i.GetElementType().LeafType()
+	case FTMap:
+		return i.GetMapValueType().LeafType()
+	default:
+		return i
+	}
+}
+
 // String 将 FieldTypeInfo 转换为字符串形式
 func (i *FieldTypeInfo) String() string {
+	if i.Optional {
+		return i.typeString() + FTOptionalSuffix
+	}
+	return i.typeString()
+}
+
+// typeString 不含可选后缀的类型字符串
+func (i *FieldTypeInfo) typeString() string {
 	switch i.Type {
 	case FTEnum:
 		return i.GetName()
-	case FTStruct:
+	case FTStruct, FTUnion:
 		return i.GetName()
 	case FTArray:
 		return "[]" + i.GetElementType().String()
 	case FTMap:
 		return "map[" + i.GetMapKeyType().String() + "]" + i.GetMapValueType().String()
+	case FTDecimal:
+		return fmt.Sprintf("decimal(%d)", i.GetScale())
+	case FTTuple:
+		if i.GetName() != TupleGeneric {
+			return i.GetName()
+		}
+		elems := make([]string, len(i.GetTupleElements()))
+		for j, et := range i.GetTupleElements() {
+			elems[j] = et.String()
+		}
+		return TupleGeneric + "[" + strings.Join(elems, TupleElementSep) + "]"
 	default:
 		return i.Type.String()
 	}
@@ -172,17 +424,27 @@ func newFieldTypeInfo(ft FieldType) *FieldTypeInfo {
 	return &FieldTypeInfo{Type: ft, params: make(map[int]any)}
 }
 
-// NewPrimitiveFieldTypeInfo 创建primitive字段类型信息
+// NewPrimitiveFieldTypeInfo 创建primitive字段类型信息, FTDecimal 需使用 NewDecimalFieldTypeInfo 创建
 func NewPrimitiveFieldTypeInfo(ft FieldType) *FieldTypeInfo {
-	if !ft.Primitive() {
+	if !ft.Primitive() || ft == FTDecimal {
 		panic(fmt.Sprintf("gexcels: NewPrimitiveFieldTypeInfo: field type %d must be primitive", ft))
 	}
 	return &FieldTypeInfo{Type: ft}
 }
 
+// NewDecimalFieldTypeInfo 创建定点小数字段类型信息, 值以 10^scale 缩放后的 int64 表示
+func NewDecimalFieldTypeInfo(scale int) *FieldTypeInfo {
+	if scale < 0 || scale > MaxDecimalScale {
+		panic(fmt.Sprintf("gexcels: NewDecimalFieldTypeInfo: scale %d must in [0,%d]", scale, MaxDecimalScale))
+	}
+	info := newFieldTypeInfo(FTDecimal)
+	info.setScale(scale)
+	return info
+}
+
 // NewPrimitiveArrayFieldTypeInfo 创建primitive数组字段类型
 func NewPrimitiveArrayFieldTypeInfo(et FieldType) *FieldTypeInfo {
-	if !et.Primitive() {
+	if !et.Primitive() || et == FTDecimal {
 		panic("gexcels.NewPrimitiveArrayFieldType: element type must be primitive")
 	}
 
@@ -211,6 +473,16 @@ func NewStructArrayFieldTypeInfo(structName string) *FieldTypeInfo {
 	return info
 }
 
+// NewUnionFieldTypeInfo 创建联合类型字段类型信息
+func NewUnionFieldTypeInfo(unionName string) *FieldTypeInfo {
+	if !MatchName(unionName) {
+		panic("gexcels: NewUnionFieldTypeInfo: union name " + unionName + " invalid")
+	}
+	info := newFieldTypeInfo(FTUnion)
+	info.setName(unionName)
+	return info
+}
+
 // NewEnumFieldTypeInfo 创建枚举字段类型信息
 func NewEnumFieldTypeInfo(enumName string) *FieldTypeInfo {
 	if !MatchName(enumName) {
@@ -221,6 +493,27 @@ func NewEnumFieldTypeInfo(enumName string) *FieldTypeInfo {
 	return info
 }
 
+// newTupleFieldTypeInfo 创建元组字段类型信息
+func newTupleFieldTypeInfo(name string, elems []*FieldTypeInfo) *FieldTypeInfo {
+	info := newFieldTypeInfo(FTTuple)
+	info.setName(name)
+	info.setTupleElements(elems)
+	return info
+}
+
+// NewTupleFieldTypeInfo 创建通用元组字段类型信息, 元素类型须为非可选的 primitive(FTText 除外) 或 FTEnum
+func NewTupleFieldTypeInfo(elems ...*FieldTypeInfo) *FieldTypeInfo {
+	if len(elems) < 2 || len(elems) > MaxTupleElement {
+		panic(fmt.Sprintf("gexcels: NewTupleFieldTypeInfo: element number %d must in [2,%d]", len(elems), MaxTupleElement))
+	}
+	for _, et := range elems {
+		if et == nil || et.Optional || et.Type == FTText || (!et.Type.Primitive() && et.Type != FTEnum) {
+			panic("gexcels: NewTupleFieldTypeInfo: element type must be primitive or enum")
+		}
+	}
+	return newTupleFieldTypeInfo(TupleGeneric, elems)
+}
+
 // NewArrayFieldTypeInfo 创建数组字段类型信息
 func NewArrayFieldTypeInfo(et *FieldTypeInfo) *FieldTypeInfo {
 	info := newFieldTypeInfo(FTArray)
@@ -228,6 +521,14 @@ func NewArrayFieldTypeInfo(et *FieldTypeInfo) *FieldTypeInfo {
 	return info
 }
 
+// NewOptionalFieldTypeInfo 基于 ti 创建可选字段类型信息, ti 本身不会被修改
+func NewOptionalFieldTypeInfo(ti *FieldTypeInfo) *FieldTypeInfo {
+	if !ti.Type.Primitive() && ti.Type != FTEnum {
+		panic(fmt.Sprintf("gexcels: NewOptionalFieldTypeInfo: field type %s must be primitive or enum", ti.Type))
+	}
+	return &FieldTypeInfo{Type: ti.Type, Optional: true, params: ti.params}
+}
+
 // NewMapFieldTypeInfo 创建 map 字段类型信息
 func NewMapFieldTypeInfo(kt *FieldTypeInfo, vt *FieldTypeInfo) *FieldTypeInfo {
 	if kt == nil {
@@ -288,11 +589,52 @@ func ParseFieldTypeInfo(s string) (*FieldTypeInfo, error) {
 			return NewPrimitiveFieldTypeInfo(ft), nil
 		}
 
+		if scale, ok, err := ParseDecimalFieldType(s); ok {
+			if err != nil {
+				return nil, err
+			}
+			return NewDecimalFieldTypeInfo(scale), nil
+		}
+
+		if ti := ParseBuiltinTupleFieldType(s); ti != nil {
+			return ti, nil
+		}
+
+		if elemStrs, ok, err := SplitGenericTupleFieldType(s); ok {
+			if err != nil {
+				return nil, err
+			}
+			elems := make([]*FieldTypeInfo, len(elemStrs))
+			for i, es := range elemStrs {
+				et, err := parse(es)
+				if err != nil {
+					return nil, err
+				}
+				if !et.Type.Primitive() {
+					return nil, fmt.Errorf("gexcels: ParseFieldTypeInfo: tuple element type %s must be primitive", es)
+				}
+				elems[i] = et
+			}
+			return NewTupleFieldTypeInfo(elems...), nil
+		}
+
 		if !MatchName(s) {
 			return nil, fmt.Errorf("gexcels: ParseFieldTypeInfo: struct name %s invalid", s)
 		}
 		return NewStructFieldTypeInfo(s), nil
 	}
 
+	// 可选标记仅允许出现在最外层的 primitive 类型上
+	if base, ok := strings.CutSuffix(strings.TrimSpace(s), FTOptionalSuffix); ok {
+		ti, err := parse(base)
+		if err != nil {
+			return nil, err
+		}
+		if !ti.Type.Primitive() {
+			return nil, fmt.Errorf("gexcels: ParseFieldTypeInfo: optional type %s must be primitive", base)
+		}
+		return NewOptionalFieldTypeInfo(ti), nil
+	}
+
 	return parse(s)
 }
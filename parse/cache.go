@@ -0,0 +1,311 @@
+package parse
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/godyy/gexcels"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// cacheVersion 缓存格式版本, 格式或解析逻辑变化时需要递增, 使旧缓存失效
+const cacheVersion = 13
+
+func init() {
+	// 条目值中可能出现的复合类型, 基础类型及其切片已由 gob 注册
+	gob.Register(map[string]any{})
+	gob.Register(map[int32]any{})
+	gob.Register(map[int64]any{})
+	gob.Register(map[int8]any{})
+	gob.Register(map[int16]any{})
+	gob.Register(map[uint8]any{})
+	gob.Register(map[uint16]any{})
+	gob.Register(map[uint32]any{})
+	gob.Register(map[uint64]any{})
+	gob.Register([]any{})
+	gob.Register(time.Time{})
+	gob.Register(time.Duration(0))
+	gob.Register([]time.Time{})
+	gob.Register([]time.Duration{})
+	gob.Register(&UnionValue{})
+}
+
+// cacheSheet 缓存的sheet
+type cacheSheet struct {
+	Name    string               // sheet名
+	Records [][]string           // sheet内容, 普通配置表仅包含表头
+	Entries []gexcels.TableEntry // 普通配置表已解析的条目
+	Rows    []int                // 普通配置表条目所在行号
+	Lints   []*Diagnostic        // 普通配置表条目的单元格lint
+}
+
+// cacheFile 缓存文件
+type cacheFile struct {
+	Version     int           // 缓存格式版本
+	Path        string        // 数据源路径
+	ContentHash string        // 数据源内容哈希
+	DepsHash    string        // 依赖(选项、枚举、结构体)哈希, 仅配置表文件使用
+	Sheets      []*cacheSheet // 缓存的sheet
+}
+
+// parseCache 增量解析缓存.
+// 每个数据源文件对应一个缓存文件, 以内容哈希判断是否可复用.
+// 配置表的解析结果依赖枚举与结构体定义, 因此配置表缓存还需校验依赖哈希,
+// 任意枚举或结构体文件变化都会使所有配置表缓存失效.
+type parseCache struct {
+	dir      string     // 缓存目录
+	fsys     fileSystem // 数据源文件系统
+	deps     []string   // 依赖项, 按解析顺序排列
+	depsHash string     // 依赖哈希
+	options  *Options   // 解析选项
+}
+
+// newParseCache 创建缓存, dir 为空时返回 nil, 表示不使用缓存
+func newParseCache(dir string, fsys fileSystem, options *Options) (*parseCache, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, pkg_errors.WithMessage(err, "create cache dir")
+	}
+	return &parseCache{
+		dir:     dir,
+		fsys:    fsys,
+		options: options,
+	}, nil
+}
+
+// hash 计算数据哈希
+func (c *parseCache) hash(data []byte) string {
+	if c == nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// addDep 添加依赖文件(枚举、结构体)
+func (c *parseCache) addDep(path, hash string) {
+	if c == nil {
+		return
+	}
+	c.deps = append(c.deps, c.fsys.normalize(path)+":"+hash)
+}
+
+// sealDeps 依赖添加完毕, 计算依赖哈希, 包含影响解析结果的选项.
+// 需在解析配置表之前调用.
+func (c *parseCache) sealDeps() {
+	if c == nil {
+		return
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "version:%d\n", cacheVersion)
+	fmt.Fprintf(&sb, "tags:%v\n", c.options.Tags)
+	fmt.Fprintf(&sb, "ruleSep:%s\n", c.options.FieldRuleSep)
+	fmt.Fprintf(&sb, "onlyFields:%v\n", c.options.OnlyFields)
+	fmt.Fprintf(&sb, "timeZone:%s\n", c.options.TimeZone)
+	fmt.Fprintf(&sb, "rules:%v\n", gexcels.FieldRuleNames())
+	for _, dep := range c.deps {
+		sb.WriteString(dep)
+		sb.WriteByte('\n')
+	}
+	c.depsHash = c.hash([]byte(sb.String()))
+}
+
+// filePath 获取数据源对应的缓存文件路径
+func (c *parseCache) filePath(path string) string {
+	return filepath.Join(c.dir, c.hash([]byte(c.fsys.normalize(path)))+".cache")
+}
+
+// load 加载数据源的缓存, 缓存不存在或已失效时返回 nil
+func (c *parseCache) load(path, contentHash string, withDeps bool) *cacheFile {
+	if c == nil {
+		return nil
+	}
+	data, err := os.ReadFile(c.filePath(path))
+	if err != nil {
+		return nil
+	}
+	var cf cacheFile
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cf); err != nil {
+		return nil
+	}
+	if cf.Version != cacheVersion || cf.Path != c.fsys.normalize(path) || cf.ContentHash != contentHash {
+		return nil
+	}
+	if withDeps && cf.DepsHash != c.depsHash {
+		return nil
+	}
+	return &cf
+}
+
+// store 存储数据源的缓存, 失败时仅放弃缓存
+func (c *parseCache) store(path, contentHash string, withDeps bool, sheets []*cacheSheet) {
+	if c == nil {
+		return
+	}
+	cf := &cacheFile{
+		Version:     cacheVersion,
+		Path:        c.fsys.normalize(path),
+		ContentHash: contentHash,
+		Sheets:      sheets,
+	}
+	if withDeps {
+		cf.DepsHash = c.depsHash
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cf); err != nil {
+		return
+	}
+
+	// 先写入临时文件再重命名, 避免并发或中断时产生不完整的缓存
+	file := c.filePath(path)
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(file)+".*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(buf.Bytes())
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// sheetRecords 读取sheet前 maxRow 行的内容
+func sheetRecords(sheet Sheet, maxRow int) ([][]string, error) {
+	if maxRow > sheet.MaxRow() {
+		maxRow = sheet.MaxRow()
+	}
+	records := make([][]string, maxRow)
+	for i := 0; i < maxRow; i++ {
+		record := make([]string, sheet.MaxCol())
+		for k := range record {
+			value, err := sheet.Cell(i, k)
+			if err != nil {
+				return nil, err
+			}
+			record[k] = value
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// newCacheSheet 通过sheet创建缓存sheet
+func newCacheSheet(sheet Sheet, maxRow int) (*cacheSheet, error) {
+	records, err := sheetRecords(sheet, maxRow)
+	if err != nil {
+		return nil, err
+	}
+	return &cacheSheet{Name: sheet.Name(), Records: records}, nil
+}
+
+// sheet 将缓存sheet还原为 Sheet
+func (cs *cacheSheet) sheet() Sheet {
+	return NewRecordSheet(cs.Name, cs.Records)
+}
+
+// openDefineWorkbook 打开枚举、结构体定义工作簿.
+// 定义文件的内容哈希作为配置表缓存的依赖, 缓存中保存所有sheet内容, 命中时免去解码.
+func (p *Parser) openDefineWorkbook(path string) (Workbook, error) {
+	data, err := p.fsys.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := p.cache.hash(data)
+	p.cache.addDep(path, hash)
+	if cf := p.cache.load(path, hash, false); cf != nil {
+		sheets := make([]Sheet, len(cf.Sheets))
+		for i, cs := range cf.Sheets {
+			sheets[i] = cs.sheet()
+		}
+		return NewWorkbook(sheets...), nil
+	}
+
+	wb, err := decodeWorkbook(filepath.Base(path), data)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.cache != nil {
+		cacheSheets := make([]*cacheSheet, 0, len(wb.Sheets()))
+		for _, sheet := range wb.Sheets() {
+			cs, err := newCacheSheet(sheet, sheet.MaxRow())
+			if err != nil {
+				return nil, err
+			}
+			cacheSheets = append(cacheSheets, cs)
+		}
+		p.cache.store(path, hash, false, cacheSheets)
+	}
+
+	return wb, nil
+}
+
+// newTableCacheSheet 创建配置表的缓存sheet.
+// 全局配置表缓存整个sheet; 普通配置表仅缓存表头以及解析后的条目.
+func newTableCacheSheet(sheet Sheet, td *Table) (*cacheSheet, error) {
+	if td.IsGlobal {
+		return newCacheSheet(sheet, sheet.MaxRow())
+	}
+	cs, err := newCacheSheet(sheet, gexcels.TableRowFirstEntry)
+	if err != nil {
+		return nil, err
+	}
+	cs.Entries = td.Entries
+	cs.Rows = td.entryRows
+	cs.Lints = td.lints
+	return cs, nil
+}
+
+// revalidateRestoredTable 调用缓存恢复的配置表中自定义规则的条目校验与配置表校验.
+// 自定义规则可能依赖配置内容以外的状态, 其结果不能缓存; 全局配置表恢复时已重新解析条目.
+// 校验失败时由调用方重新解析, 以得到与完整解析一致的错误信息.
+func (p *Parser) revalidateRestoredTable(td *Table) error {
+	if !td.IsGlobal {
+		for i, entry := range td.Entries {
+			if err := p.validateEntry(td, entry, td.entryRows[i], true); err != nil {
+				return err
+			}
+		}
+	}
+	return p.validateTable(td, true)
+}
+
+// restoreTableFile 通过缓存恢复配置表文件的解析结果
+func (p *Parser) restoreTableFile(path string, cf *cacheFile) *tableFileResult {
+	result := &tableFileResult{cached: true}
+	for _, cs := range cf.Sheets {
+		td, err := p.parseTableOfSheet(cs.sheet())
+		if td != nil {
+			td.Path, td.Sheet = path, cs.Name
+		}
+		if err == nil && !td.IsGlobal && !p.options.OnlyFields {
+			err = td.restoreEntries(cs.Entries, cs.Rows)
+			td.lints = cs.Lints
+		}
+		if err == nil && !p.options.OnlyFields {
+			err = p.revalidateRestoredTable(td)
+		}
+		if err != nil {
+			result.err = pkg_errors.WithMessagef(err, "[%s][%s] restore from cache", path, cs.Name)
+			return result
+		}
+		result.sheets = append(result.sheets, &tableSheetResult{sheet: cs.Name, table: td})
+	}
+	return result
+}
@@ -0,0 +1,1136 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/godyy/gexcels"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// CHECK 表达式语法:
+//
+//	expr    = "if" expr "then" expr ["else" expr] | or
+//	or      = and {("||" | "or") and}
+//	and     = not {("&&" | "and") not}
+//	not     = ("!" | "not") not | compare
+//	compare = sum [("==" | "!=" | "<" | "<=" | ">" | ">=") sum]
+//	sum     = product {("+" | "-") product}
+//	product = unary {("*" | "/" | "%") unary}
+//	unary   = "-" unary | postfix
+//	postfix = primary {"." name | "[" expr "]"}
+//	primary = number | string | "true" | "false" | name ["(" [expr {"," expr}] ")"] | "(" expr ")"
+//
+// name 为字段名, 或 Enum.Item 形式的枚举项; 与枚举字段比较时, 枚举项可省略枚举名.
+// 未填写的字段值为其类型的零值. if 不带 else 时, 条件不成立即视为通过.
+// 支持的函数: len(字符串/数组/map), sum(数值数组).
+// datetime 与 duration 字段值以毫秒数参与运算, e.g. End - Start >= Cooldown.
+// decimal 字段值以实际数值参与运算, e.g. Rate <= 1.5.
+// 元组字段通过元素名称访问元素, e.g. Pos.X >= 0, Pair.Item1.
+
+// errCheckFieldExcluded 表达式引用了被 tag 过滤的字段
+var errCheckFieldExcluded = errors.New("field excluded by tag")
+
+// tableCheck 配置表条目检查
+type tableCheck struct {
+	field *gexcels.TableField // 声明规则的字段
+	rule  *gexcels.FRCheck    // 规则
+	expr  checkNode           // 编译后的表达式, 为 nil 时不检查
+}
+
+// addCheck 添加条目检查, 表达式在所有字段解析后编译
+func (td *Table) addCheck(fd *gexcels.TableField, rule *gexcels.FRCheck) {
+	td.checks = append(td.checks, &tableCheck{field: fd, rule: rule})
+}
+
+// excludeField 记录被 tag 过滤的字段
+func (td *Table) excludeField(name string) {
+	if td.excludedFields == nil {
+		td.excludedFields = make(map[string]bool)
+	}
+	td.excludedFields[name] = true
+}
+
+// checkDiagnostic 获取检查规则单元格的诊断位置
+func (td *Table) checkDiagnostic(c *tableCheck) Diagnostic {
+	diag := Diagnostic{Field: c.field.Name, Rule: gexcels.FRNCheck}
+	if td.IsGlobal {
+		diag.Row, diag.Col = td.GetFieldRow(c.field.Name), gexcels.GlobalTableColFieldRule+1
+	} else {
+		diag.Row, diag.Col = gexcels.TableRowFieldRule+1, c.field.Col+1
+	}
+	return diag
+}
+
+// compileTableChecks 编译配置表td的条目检查表达式.
+// 引用了被 tag 过滤的字段的检查将被忽略.
+func (p *Parser) compileTableChecks(td *Table) error {
+	var errs Errors
+	for _, c := range td.checks {
+		expr, err := p.compileCheck(td, c.rule.Expr)
+		if errors.Is(err, errCheckFieldExcluded) {
+			continue
+		}
+		if err != nil {
+			err = withDiagnostic(pkg_errors.WithMessagef(err, "%s {%s}", gexcels.FRNCheck, c.rule.Expr), td.checkDiagnostic(c))
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+			continue
+		}
+		c.expr = expr
+	}
+	return errs.err()
+}
+
+// checkEntry 检查条目是否满足配置表的所有检查表达式, row 为条目所在行号.
+// 全局配置表的所有字段值视为一个条目, 错误定位到规则所在行.
+func (p *Parser) checkEntry(td *Table, entry gexcels.TableEntry, row int) error {
+	var errs Errors
+	for _, c := range td.checks {
+		if c.expr == nil {
+			continue
+		}
+
+		ok, err := evalCheck(c.expr, entry)
+		if err == nil && ok {
+			continue
+		}
+		if err == nil {
+			err = fmt.Errorf("%s {%s} failed", gexcels.FRNCheck, c.rule.Expr)
+		} else {
+			err = pkg_errors.WithMessagef(err, "%s {%s}", gexcels.FRNCheck, c.rule.Expr)
+		}
+
+		diag := td.checkDiagnostic(c)
+		if td.IsGlobal {
+			err = pkg_errors.WithMessagef(err, "field {%s}", c.field.Name)
+		} else {
+			diag = Diagnostic{Row: row, Rule: gexcels.FRNCheck}
+			err = pkg_errors.WithMessagef(err, "row[%d]", row)
+		}
+		if err := p.collectError(&errs, withDiagnostic(err, diag)); err != nil {
+			return err
+		}
+	}
+	return errs.err()
+}
+
+// compileCheck 编译配置表td的检查表达式s
+func (p *Parser) compileCheck(td *Table, s string) (checkNode, error) {
+	tokens, err := lexCheck(s)
+	if err != nil {
+		return nil, err
+	}
+	cp := &checkParser{p: p, td: td, tokens: tokens}
+	n, err := cp.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := cp.peek(); tok.kind != checkTokenEOF {
+		return nil, fmt.Errorf("unexpected %s", tok)
+	}
+	return cp.operand(n)
+}
+
+// evalCheck 计算表达式n在条目entry上的结果
+func evalCheck(n checkNode, entry gexcels.TableEntry) (bool, error) {
+	v, err := n.eval(entry)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("result %v not bool", v)
+	}
+	return b, nil
+}
+
+// checkTokenKind 表达式词法单元类型
+type checkTokenKind int
+
+const (
+	checkTokenEOF    checkTokenKind = iota // 结束
+	checkTokenNumber                       // 数值
+	checkTokenString                       // 字符串
+	checkTokenName                         // 名称或关键字
+	checkTokenOp                           // 运算符或标点
+)
+
+// checkToken 表达式词法单元
+type checkToken struct {
+	kind checkTokenKind
+	text string // 原始文本, 字符串为去除引号后的内容
+	pos  int    // 在表达式中的位置(从1开始)
+}
+
+func (t checkToken) String() string {
+	switch t.kind {
+	case checkTokenEOF:
+		return "end of expression"
+	case checkTokenString:
+		return fmt.Sprintf("%q at %d", t.text, t.pos)
+	default:
+		return fmt.Sprintf("{%s} at %d", t.text, t.pos)
+	}
+}
+
+// checkOps 运算符, 按长度优先匹配
+var checkOps = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", ".", ","}
+
+// lexCheck 将表达式s拆分为词法单元
+func lexCheck(s string) ([]checkToken, error) {
+	var tokens []checkToken
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' && j+1 < len(s) && s[j+1] >= '0' && s[j+1] <= '9') {
+				j++
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenNumber, text: s[i:j], pos: i + 1})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenName, text: s[i:j], pos: i + 1})
+			i = j
+		case r == '"' || r == '\'':
+			j := strings.IndexByte(s[i+1:], byte(r))
+			if j < 0 {
+				return nil, fmt.Errorf("string at %d not terminated", i+1)
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenString, text: s[i+1 : i+1+j], pos: i + 1})
+			i += j + 2
+		default:
+			op := ""
+			for _, o := range checkOps {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				if r == '=' {
+					return nil, fmt.Errorf("{=} at %d invalid, use {==}", i+1)
+				}
+				return nil, fmt.Errorf("{%c} at %d invalid", r, i+1)
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenOp, text: op, pos: i + 1})
+			i += len(op)
+		}
+	}
+	return append(tokens, checkToken{kind: checkTokenEOF, pos: len(s) + 1}), nil
+}
+
+// checkParser 表达式语法分析
+type checkParser struct {
+	p      *Parser
+	td     *Table
+	tokens []checkToken
+	pos    int
+}
+
+func (cp *checkParser) peek() checkToken {
+	return cp.tokens[cp.pos]
+}
+
+func (cp *checkParser) next() checkToken {
+	tok := cp.tokens[cp.pos]
+	if tok.kind != checkTokenEOF {
+		cp.pos++
+	}
+	return tok
+}
+
+// accept 下一个词法单元为运算符或关键字之一时, 读取并返回该词法单元
+func (cp *checkParser) accept(texts ...string) (string, bool) {
+	tok := cp.peek()
+	if tok.kind != checkTokenOp && tok.kind != checkTokenName {
+		return "", false
+	}
+	for _, text := range texts {
+		if tok.text == text {
+			cp.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// expect 读取指定的运算符或关键字
+func (cp *checkParser) expect(text string) error {
+	if _, ok := cp.accept(text); !ok {
+		return fmt.Errorf("expect {%s}, got %s", text, cp.peek())
+	}
+	return nil
+}
+
+// operand 检查n能否作为运算数, 未解析的名称不能作为运算数
+func (cp *checkParser) operand(n checkNode) (checkNode, error) {
+	if name, ok := n.(*checkName); ok {
+		return nil, fmt.Errorf("name %s not found", name.name)
+	}
+	return n, nil
+}
+
+func (cp *checkParser) parseExpr() (checkNode, error) {
+	if _, ok := cp.accept("if"); !ok {
+		return cp.parseOr()
+	}
+
+	node := &checkIf{}
+	var err error
+	if node.cond, err = cp.parseOperand(cp.parseExpr); err != nil {
+		return nil, err
+	}
+	if err = cp.expect("then"); err != nil {
+		return nil, err
+	}
+	if node.then, err = cp.parseOperand(cp.parseExpr); err != nil {
+		return nil, err
+	}
+	if _, ok := cp.accept("else"); ok {
+		if node.els, err = cp.parseOperand(cp.parseExpr); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// parseOperand 通过f解析运算数
+func (cp *checkParser) parseOperand(f func() (checkNode, error)) (checkNode, error) {
+	n, err := f()
+	if err != nil {
+		return nil, err
+	}
+	return cp.operand(n)
+}
+
+// parseLogic 解析左结合的逻辑运算
+func (cp *checkParser) parseLogic(f func() (checkNode, error), and bool, ops ...string) (checkNode, error) {
+	left, err := f()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := cp.accept(ops...); !ok {
+			return left, nil
+		}
+		if left, err = cp.operand(left); err != nil {
+			return nil, err
+		}
+		right, err := cp.parseOperand(f)
+		if err != nil {
+			return nil, err
+		}
+		left = &checkLogic{and: and, left: left, right: right}
+	}
+}
+
+func (cp *checkParser) parseOr() (checkNode, error) {
+	return cp.parseLogic(cp.parseAnd, false, "||", "or")
+}
+
+func (cp *checkParser) parseAnd() (checkNode, error) {
+	return cp.parseLogic(cp.parseNot, true, "&&", "and")
+}
+
+func (cp *checkParser) parseNot() (checkNode, error) {
+	if _, ok := cp.accept("!", "not"); ok {
+		n, err := cp.parseOperand(cp.parseNot)
+		if err != nil {
+			return nil, err
+		}
+		return &checkNot{n: n}, nil
+	}
+	return cp.parseCompare()
+}
+
+func (cp *checkParser) parseCompare() (checkNode, error) {
+	left, err := cp.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := cp.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	right, err := cp.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	// 与枚举字段比较时, 枚举项可省略枚举名
+	if left, err = cp.resolveEnumItem(left, right); err != nil {
+		return nil, err
+	}
+	if right, err = cp.resolveEnumItem(right, left); err != nil {
+		return nil, err
+	}
+	if left, err = cp.operand(left); err != nil {
+		return nil, err
+	}
+	if right, err = cp.operand(right); err != nil {
+		return nil, err
+	}
+	return &checkBinary{op: op, left: left, right: right}, nil
+}
+
+// parseArith 解析左结合的算术运算
+func (cp *checkParser) parseArith(f func() (checkNode, error), ops ...string) (checkNode, error) {
+	left, err := f()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := cp.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		if left, err = cp.operand(left); err != nil {
+			return nil, err
+		}
+		right, err := cp.parseOperand(f)
+		if err != nil {
+			return nil, err
+		}
+		left = &checkBinary{op: op, left: left, right: right}
+	}
+}
+
+func (cp *checkParser) parseSum() (checkNode, error) {
+	return cp.parseArith(cp.parseProduct, "+", "-")
+}
+
+func (cp *checkParser) parseProduct() (checkNode, error) {
+	return cp.parseArith(cp.parseUnary, "*", "/", "%")
+}
+
+func (cp *checkParser) parseUnary() (checkNode, error) {
+	if _, ok := cp.accept("-"); ok {
+		n, err := cp.parseOperand(cp.parseUnary)
+		if err != nil {
+			return nil, err
+		}
+		return &checkBinary{op: "-", left: &checkLiteral{value: int64(0)}, right: n}, nil
+	}
+	return cp.parsePostfix()
+}
+
+func (cp *checkParser) parsePostfix() (checkNode, error) {
+	n, err := cp.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := cp.accept(".", "[")
+		if !ok {
+			return n, nil
+		}
+
+		if op == "." {
+			tok := cp.next()
+			if tok.kind != checkTokenName {
+				return nil, fmt.Errorf("expect name, got %s", tok)
+			}
+			if n, err = cp.member(n, tok.text); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		ref, ok := n.(*checkRef)
+		if !ok {
+			return nil, fmt.Errorf("{[} at %d on non-field", cp.tokens[cp.pos-1].pos)
+		}
+		index, err := cp.parseOperand(cp.parseExpr)
+		if err != nil {
+			return nil, err
+		}
+		if err := cp.expect("]"); err != nil {
+			return nil, err
+		}
+		if n, err = cp.index(ref, index); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (cp *checkParser) parsePrimary() (checkNode, error) {
+	tok := cp.next()
+	switch tok.kind {
+	case checkTokenNumber:
+		if i, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return &checkLiteral{value: i}, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("number %s invalid", tok)
+		}
+		return &checkLiteral{value: f}, nil
+	case checkTokenString:
+		return &checkLiteral{value: tok.text}, nil
+	case checkTokenName:
+		switch tok.text {
+		case "true", "false":
+			return &checkLiteral{value: tok.text == "true"}, nil
+		case "if", "then", "else", "and", "or", "not":
+			return nil, fmt.Errorf("unexpected %s", tok)
+		}
+		if _, ok := cp.accept("("); ok {
+			return cp.parseCall(tok)
+		}
+		return cp.name(tok.text)
+	case checkTokenOp:
+		if tok.text == "(" {
+			n, err := cp.parseOperand(cp.parseExpr)
+			if err != nil {
+				return nil, err
+			}
+			if err := cp.expect(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s", tok)
+}
+
+// checkFuncs 表达式函数
+var checkFuncs = map[string]func(args []any) (any, error){
+	"len": checkFuncLen,
+	"sum": checkFuncSum,
+}
+
+func (cp *checkParser) parseCall(name checkToken) (checkNode, error) {
+	f := checkFuncs[name.text]
+	if f == nil {
+		return nil, fmt.Errorf("function %s not found", name)
+	}
+
+	node := &checkCall{name: name.text, f: f}
+	if _, ok := cp.accept(")"); ok {
+		return node, nil
+	}
+	for {
+		arg, err := cp.parseOperand(cp.parseExpr)
+		if err != nil {
+			return nil, err
+		}
+		node.args = append(node.args, arg)
+		if _, ok := cp.accept(")"); ok {
+			return node, nil
+		}
+		if err := cp.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// name 解析名称, 依次尝试字段和枚举名, 未找到时返回 checkName 等待作为枚举项解析
+func (cp *checkParser) name(name string) (checkNode, error) {
+	if fd := cp.td.GetFieldByName(name); fd != nil {
+		return cp.newRef(fd.FieldTypeInfo, nil, func(entry gexcels.TableEntry) (any, error) {
+			return entry[fd.Name], nil
+		})
+	}
+	if cp.td.excludedFields[name] {
+		return nil, errCheckFieldExcluded
+	}
+	if enum := cp.p.GetEnum(name); enum != nil {
+		return &checkName{name: name, enum: enum}, nil
+	}
+	return &checkName{name: name}, nil
+}
+
+// member 解析结构体字段或 Enum.Item 形式的枚举项
+func (cp *checkParser) member(n checkNode, name string) (checkNode, error) {
+	switch o := n.(type) {
+	case *checkName:
+		if o.enum == nil {
+			return nil, fmt.Errorf("name %s not found", o.name)
+		}
+		item := o.enum.ItemByName[name]
+		if item == nil {
+			return nil, fmt.Errorf("enum %s item %s not found", o.enum.Name, name)
+		}
+		return newCheckEnumItem(o.enum, item)
+	case *checkRef:
+		if o.ti.Type == gexcels.FTUnion {
+			// 联合类型的成员为分支, 值为分支结构体, 未选择该分支时为空
+			u := cp.p.GetUnionByName(o.ti.GetName())
+			if u == nil {
+				return nil, fmt.Errorf("union %s not define", o.ti.GetName())
+			}
+			c := u.GetCaseByName(name)
+			if c == nil {
+				return nil, fmt.Errorf("union %s case %s not found", u.Name, name)
+			}
+			return cp.newRef(gexcels.NewStructFieldTypeInfo(c.Struct), o, func(base any) (any, error) {
+				if uv, _ := base.(*UnionValue); uv != nil && uv.Case == c.Name {
+					return uv.Value, nil
+				}
+				return nil, nil
+			})
+		}
+		if o.ti.Type == gexcels.FTTuple {
+			// 元组的成员为元素, e.g. Pos.X, Pair.Item1
+			i := slices.Index(o.ti.GetTupleElementNames(), name)
+			if i < 0 {
+				return nil, fmt.Errorf("tuple %s element %s not found", o.ti, name)
+			}
+			return cp.newRef(o.ti.GetTupleElements()[i], o, func(base any) (any, error) {
+				if t, _ := base.([]any); t != nil {
+					return t[i], nil
+				}
+				return nil, nil
+			})
+		}
+		if o.ti.Type != gexcels.FTStruct {
+			return nil, fmt.Errorf("{.%s} on non-struct", name)
+		}
+		sd := cp.p.GetStructByName(o.ti.GetName())
+		if sd == nil {
+			return nil, errStructNotDefine(o.ti.GetName())
+		}
+		fd := sd.GetFieldByName(name)
+		if fd == nil {
+			return nil, fmt.Errorf("struct %s field %s not found", sd.Name, name)
+		}
+		return cp.newRef(fd.FieldTypeInfo, o, func(base any) (any, error) {
+			m, _ := base.(map[string]any)
+			return m[fd.Name], nil
+		})
+	default:
+		return nil, fmt.Errorf("{.%s} on non-field", name)
+	}
+}
+
+// index 解析数组元素或 map 值
+func (cp *checkParser) index(ref *checkRef, index checkNode) (checkNode, error) {
+	switch ref.ti.Type {
+	case gexcels.FTArray:
+		return cp.newRef(ref.ti.GetElementType(), ref, func(base any, key any) (any, error) {
+			i, ok := key.(int64)
+			if !ok {
+				return nil, fmt.Errorf("array index %v not integer", key)
+			}
+			if base == nil {
+				return nil, fmt.Errorf("array index %d out of range [0,0)", i)
+			}
+			rv := reflect.ValueOf(base)
+			if i < 0 || i >= int64(rv.Len()) {
+				return nil, fmt.Errorf("array index %d out of range [0,%d)", i, rv.Len())
+			}
+			return rv.Index(int(i)).Interface(), nil
+		}, index)
+	case gexcels.FTMap:
+		keyType, err := cp.p.getMapKeyType(ref.ti.GetMapKeyType())
+		if err != nil {
+			return nil, err
+		}
+		return cp.newRef(ref.ti.GetMapValueType(), ref, func(base any, key any) (any, error) {
+			if base == nil {
+				return nil, nil
+			}
+			kv, err := checkMapKey(key, keyType)
+			if err != nil {
+				return nil, err
+			}
+			rv := reflect.ValueOf(base).MapIndex(kv)
+			if !rv.IsValid() {
+				return nil, nil
+			}
+			return rv.Interface(), nil
+		}, index)
+	default:
+		return nil, fmt.Errorf("{[]} on non-array and non-map field")
+	}
+}
+
+// newRef 创建字段引用, get 为以下形式之一:
+//
+//	func(entry) 获取条目字段值
+//	func(base) 获取结构体字段值
+//	func(base, key) 获取数组元素或 map 值, 需指定 index
+func (cp *checkParser) newRef(ti *gexcels.FieldTypeInfo, base *checkRef, get any, index ...checkNode) (checkNode, error) {
+	zero, err := cp.zeroValue(ti)
+	if err != nil {
+		return nil, err
+	}
+	ref := &checkRef{ti: ti, zero: zero, base: base}
+	switch f := get.(type) {
+	case func(gexcels.TableEntry) (any, error):
+		ref.get = func(entry gexcels.TableEntry) (any, error) { return f(entry) }
+	case func(any) (any, error):
+		ref.get = func(entry gexcels.TableEntry) (any, error) {
+			v, err := base.eval(entry)
+			if err != nil {
+				return nil, err
+			}
+			return f(v)
+		}
+	case func(any, any) (any, error):
+		ref.get = func(entry gexcels.TableEntry) (any, error) {
+			v, err := base.eval(entry)
+			if err != nil {
+				return nil, err
+			}
+			key, err := index[0].eval(entry)
+			if err != nil {
+				return nil, err
+			}
+			return f(v, key)
+		}
+	}
+	return ref, nil
+}
+
+// zeroValue 获取类型ti的零值, 枚举为底层类型的零值
+func (cp *checkParser) zeroValue(ti *gexcels.FieldTypeInfo) (any, error) {
+	if ti.Type != gexcels.FTEnum {
+		return checkNormalize(zeroValue(ti.Type)), nil
+	}
+	enum := cp.p.GetEnum(ti.GetName())
+	if enum == nil {
+		return nil, errEnumNotDefine(ti.GetName())
+	}
+	return checkNormalize(zeroValue(enum.Type)), nil
+}
+
+// resolveEnumItem other 为枚举字段时, 将名称n解析为该枚举的枚举项
+func (cp *checkParser) resolveEnumItem(n, other checkNode) (checkNode, error) {
+	name, ok := n.(*checkName)
+	if !ok {
+		return n, nil
+	}
+	ref, ok := other.(*checkRef)
+	if !ok || ref.ti.Type != gexcels.FTEnum {
+		return n, nil
+	}
+	enum := cp.p.GetEnum(ref.ti.GetName())
+	if enum == nil {
+		return nil, errEnumNotDefine(ref.ti.GetName())
+	}
+	item := enum.ItemByName[name.name]
+	if item == nil {
+		return nil, fmt.Errorf("enum %s item %s not found", enum.Name, name.name)
+	}
+	return newCheckEnumItem(enum, item)
+}
+
+// newCheckEnumItem 创建枚举项字面值
+func newCheckEnumItem(enum *Enum, item *gexcels.EnumItem) (checkNode, error) {
+	return &checkLiteral{value: checkNormalize(enum.GetItemValue(item.Index))}, nil
+}
+
+// checkNode 表达式节点
+type checkNode interface {
+	eval(entry gexcels.TableEntry) (any, error)
+}
+
+// checkLiteral 字面值
+type checkLiteral struct {
+	value any
+}
+
+func (n *checkLiteral) eval(gexcels.TableEntry) (any, error) { return n.value, nil }
+
+// checkName 未解析的名称, 可能为枚举名或省略枚举名的枚举项
+type checkName struct {
+	name string
+	enum *Enum
+}
+
+func (n *checkName) eval(gexcels.TableEntry) (any, error) {
+	return nil, fmt.Errorf("name %s not found", n.name)
+}
+
+// checkRef 字段引用
+type checkRef struct {
+	ti   *gexcels.FieldTypeInfo // 字段类型
+	zero any                    // 值未填写时使用的零值
+	base *checkRef              // 所属的结构体、数组或 map
+	get  func(entry gexcels.TableEntry) (any, error)
+}
+
+func (n *checkRef) eval(entry gexcels.TableEntry) (any, error) {
+	v, err := n.get(entry)
+	if err != nil || v == nil {
+		return n.zero, err
+	}
+	if d := checkDecimal(n.ti, v); d != nil {
+		return d, nil
+	}
+	return checkNormalize(v), nil
+}
+
+// checkCall 函数调用
+type checkCall struct {
+	name string
+	f    func(args []any) (any, error)
+	args []checkNode
+}
+
+func (n *checkCall) eval(entry gexcels.TableEntry) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(entry)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.f(args)
+	if err != nil {
+		return nil, pkg_errors.WithMessage(err, n.name)
+	}
+	return v, nil
+}
+
+// checkIf 条件表达式
+type checkIf struct {
+	cond, then, els checkNode
+}
+
+func (n *checkIf) eval(entry gexcels.TableEntry) (any, error) {
+	cond, err := evalCheck(n.cond, entry)
+	if err != nil {
+		return nil, pkg_errors.WithMessage(err, "if")
+	}
+	if cond {
+		return n.then.eval(entry)
+	}
+	if n.els == nil {
+		return true, nil
+	}
+	return n.els.eval(entry)
+}
+
+// checkLogic 逻辑与/或, 短路求值
+type checkLogic struct {
+	and         bool
+	left, right checkNode
+}
+
+func (n *checkLogic) eval(entry gexcels.TableEntry) (any, error) {
+	left, err := evalCheck(n.left, entry)
+	if err != nil || left != n.and {
+		return left, err
+	}
+	return evalCheck(n.right, entry)
+}
+
+// checkNot 逻辑非
+type checkNot struct {
+	n checkNode
+}
+
+func (n *checkNot) eval(entry gexcels.TableEntry) (any, error) {
+	v, err := evalCheck(n.n, entry)
+	return !v, err
+}
+
+// checkBinary 比较或算术运算
+type checkBinary struct {
+	op          string
+	left, right checkNode
+}
+
+func (n *checkBinary) eval(entry gexcels.TableEntry) (any, error) {
+	left, err := n.left.eval(entry)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return checkCompare(n.op, left, right)
+	default:
+		return checkArith(n.op, left, right)
+	}
+}
+
+// checkDecimal 将 decimal 及 decimal 数组的值转换为实际数值, 其它类型返回 nil
+func checkDecimal(ti *gexcels.FieldTypeInfo, v any) any {
+	switch o := v.(type) {
+	case int64:
+		if ti.Type == gexcels.FTDecimal {
+			return decimalFloat64(ti.GetScale(), o)
+		}
+	case []int64:
+		if ti.Type == gexcels.FTArray && ti.GetElementType().Type == gexcels.FTDecimal {
+			out := make([]float64, len(o))
+			for i, e := range o {
+				out[i] = decimalFloat64(ti.GetElementType().GetScale(), e)
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+// checkNormalize 将数值统一为 int64 或 float64.
+// float32 保持不变, 以便比较时按 float32 精度进行.
+func checkNormalize(v any) any {
+	switch o := v.(type) {
+	case int8:
+		return int64(o)
+	case int16:
+		return int64(o)
+	case int32:
+		return int64(o)
+	case uint8:
+		return int64(o)
+	case uint16:
+		return int64(o)
+	case uint32:
+		return int64(o)
+	case uint64:
+		if o > math.MaxInt64 {
+			return float64(o)
+		}
+		return int64(o)
+	case time.Time:
+		return o.UnixMilli()
+	case time.Duration:
+		return o.Milliseconds()
+	default:
+		return v
+	}
+}
+
+// checkFloat 将数值转换为 float64
+func checkFloat(v any) (float64, bool) {
+	switch o := v.(type) {
+	case int64:
+		return float64(o), true
+	case float32:
+		return float64(o), true
+	case float64:
+		return o, true
+	default:
+		return 0, false
+	}
+}
+
+// checkCompare 比较运算
+func checkCompare(op string, left, right any) (bool, error) {
+	var c int
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			c = compareOrdered(l, r)
+			return checkCompareResult(op, c), nil
+		}
+	}
+	if l, r, ok := checkFloat32Pair(left, right); ok {
+		c = compareOrdered(l, r)
+		return checkCompareResult(op, c), nil
+	}
+	if l, ok := checkFloat(left); ok {
+		if r, ok := checkFloat(right); ok {
+			c = compareOrdered(l, r)
+			return checkCompareResult(op, c), nil
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			c = strings.Compare(l, r)
+			return checkCompareResult(op, c), nil
+		}
+	}
+	if l, ok := left.(bool); ok {
+		if r, ok := right.(bool); ok && (op == "==" || op == "!=") {
+			return (l == r) == (op == "=="), nil
+		}
+	}
+	return false, fmt.Errorf("%v %s %v invalid", left, op, right)
+}
+
+// checkFloat32Pair 一侧为 float32 且另一侧为浮点数时, 将两侧转换为 float32.
+// 否则如 float32(0.1) 扩展为 float64 后与字面量 0.1 不等.
+func checkFloat32Pair(left, right any) (l, r float32, ok bool) {
+	toFloat32 := func(v any) (float32, bool) {
+		switch o := v.(type) {
+		case float32:
+			return o, true
+		case float64:
+			return float32(o), true
+		default:
+			return 0, false
+		}
+	}
+	_, leftIs32 := left.(float32)
+	_, rightIs32 := right.(float32)
+	if !leftIs32 && !rightIs32 {
+		return 0, 0, false
+	}
+	if l, ok = toFloat32(left); !ok {
+		return 0, 0, false
+	}
+	if r, ok = toFloat32(right); !ok {
+		return 0, 0, false
+	}
+	return l, r, true
+}
+
+// compareOrdered 比较有序值
+func compareOrdered[T int64 | float32 | float64](l, r T) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// checkCompareResult 根据比较结果c计算比较运算的结果
+func checkCompareResult(op string, c int) bool {
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// checkArith 算术运算, 整数间运算结果为整数
+func checkArith(op string, left, right any) (any, error) {
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			switch op {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			}
+			if r == 0 {
+				return nil, fmt.Errorf("%d %s 0 division by zero", l, op)
+			}
+			if op == "/" {
+				return l / r, nil
+			}
+			return l % r, nil
+		}
+	}
+	if l, ok := checkFloat(left); ok {
+		if r, ok := checkFloat(right); ok {
+			switch op {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			case "/":
+				return l / r, nil
+			default:
+				return math.Mod(l, r), nil
+			}
+		}
+	}
+	if l, ok := left.(string); ok && op == "+" {
+		if r, ok := right.(string); ok {
+			return l + r, nil
+		}
+	}
+	return nil, fmt.Errorf("%v %s %v invalid", left, op, right)
+}
+
+// checkMapKey 将表达式值转换为 map 键
+func checkMapKey(key any, keyType reflect.Type) (reflect.Value, error) {
+	switch keyType.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := key.(int64); ok {
+			return reflect.ValueOf(i).Convert(keyType), nil
+		}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, ok := key.(int64); ok && i >= 0 {
+			return reflect.ValueOf(i).Convert(keyType), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := checkFloat(key); ok {
+			return reflect.ValueOf(f).Convert(keyType), nil
+		}
+	case reflect.String, reflect.Bool:
+		if rv := reflect.ValueOf(key); rv.Kind() == keyType.Kind() {
+			return rv, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("map key %v not %s", key, keyType)
+}
+
+// checkFuncLen 字符串长度, 或数组、map 元素数量
+func checkFuncLen(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("args %d, expected 1", len(args))
+	}
+	switch o := args[0].(type) {
+	case nil:
+		return int64(0), nil
+	case string:
+		return int64(utf8.RuneCountInString(o)), nil
+	}
+	rv := reflect.ValueOf(args[0])
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("arg %v invalid", args[0])
+	}
+	return int64(rv.Len()), nil
+}
+
+// checkFuncSum 数值数组元素之和
+func checkFuncSum(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("args %d, expected 1", len(args))
+	}
+	if args[0] == nil {
+		return int64(0), nil
+	}
+	rv := reflect.ValueOf(args[0])
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("arg %v invalid", args[0])
+	}
+	var sum any = int64(0)
+	for i := 0; i < rv.Len(); i++ {
+		v, err := checkArith("+", sum, checkNormalize(rv.Index(i).Interface()))
+		if err != nil {
+			return nil, err
+		}
+		sum = v
+	}
+	return sum, nil
+}
@@ -0,0 +1,107 @@
+package parse
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/godyy/gexcels"
+)
+
+// parseDecimalValue 解析 decimal 字段值, 返回按 10^scale 缩放后的整数.
+// 按十进制字符串精确解析, 小数位数超出 scale 时返回错误而不是舍入, e.g. decimal(2): 1.5 => 150.
+func parseDecimalValue(scale int, s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	digits, neg := s, false
+	if digits[0] == '-' || digits[0] == '+' {
+		neg = digits[0] == '-'
+		digits = digits[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if (intPart == "" && fracPart == "") || !isDecimalDigits(intPart) || !isDecimalDigits(fracPart) {
+		return 0, fmt.Errorf("decimal %s invalid, e.g. 1.25", s)
+	}
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > scale {
+		return 0, fmt.Errorf("decimal %s exceeds scale %d", s, scale)
+	}
+
+	// 负数的绝对值可以比正数大 1, 因此统一按负数累加
+	var v int64
+	for _, c := range intPart + fracPart + strings.Repeat("0", scale-len(fracPart)) {
+		d := int64(c - '0')
+		if v < (math.MinInt64+d)/10 {
+			return 0, fmt.Errorf("decimal %s out of range", s)
+		}
+		v = v*10 - d
+	}
+	if !neg {
+		if v == math.MinInt64 {
+			return 0, fmt.Errorf("decimal %s out of range", s)
+		}
+		v = -v
+	}
+	return v, nil
+}
+
+// isDecimalDigits s 是否仅包含十进制数字, 空字符串返回 true
+func isDecimalDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// convertJSONDecimalValue 将json raw对象转换为 decimal 值.
+// 建议使用字符串表示以保证精确, 数字按其最短十进制形式解析.
+func convertJSONDecimalValue(ti *gexcels.FieldTypeInfo, raw any, path string) (int64, error) {
+	var s string
+	switch v := raw.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return 0, fmt.Errorf("%s must be decimal string or number", path)
+	}
+	v, err := parseDecimalValue(ti.GetScale(), s)
+	if err != nil {
+		return 0, fmt.Errorf("%s %w", path, err)
+	}
+	return v, nil
+}
+
+// decimalFloat64 将 decimal 缩放后的整数值转换为 float64, 仅用于校验等非确定性场景
+func decimalFloat64(scale int, v int64) float64 {
+	return float64(v) / math.Pow10(scale)
+}
+
+// formatDecimalValue 将 decimal 缩放后的整数值格式化为带 scale 位小数的字符串, e.g. decimal(2): 150 => 1.50
+func formatDecimalValue(scale int, v int64) string {
+	u := uint64(v)
+	if v < 0 {
+		u = -u
+	}
+	s := strconv.FormatUint(u, 10)
+	if scale > 0 {
+		if len(s) <= scale {
+			s = strings.Repeat("0", scale-len(s)+1) + s
+		}
+		s = s[:len(s)-scale] + "." + s[len(s)-scale:]
+	}
+	if v < 0 {
+		s = "-" + s
+	}
+	return s
+}
@@ -0,0 +1,182 @@
+package parse
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Severity 诊断级别
+type Severity int8
+
+const (
+	SeverityError   = Severity(0) // 错误
+	SeverityWarning = Severity(1) // 警告
+)
+
+var severityStrings = [...]string{
+	SeverityError:   "error",
+	SeverityWarning: "warning",
+}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityStrings) {
+		return "unknown"
+	}
+	return severityStrings[s]
+}
+
+// Diagnostic 诊断信息, 描述解析过程中发现的问题及其位置.
+type Diagnostic struct {
+	Severity Severity // 级别
+	Path     string   // 工作簿路径
+	Sheet    string   // sheet名
+	Row      int      // 行号, 从1开始, 0表示未知
+	Col      int      // 列号, 从1开始, 0表示未知
+	Table    string   // 配置表名
+	Field    string   // 字段名, 嵌套字段以'.'连接
+	Rule     string   // 规则名
+	Message  string   // 描述
+}
+
+// Cell 单元格的 A1 引用, 例如 "C5". 行或列未知时返回空字符串.
+func (d *Diagnostic) Cell() string {
+	if d.Row <= 0 || d.Col <= 0 {
+		return ""
+	}
+	return columnName(d.Col) + strconv.Itoa(d.Row)
+}
+
+// String 格式化诊断信息, 例如 "error: [test.xlsx][道具|Item]!C5 Item.Name UNIQUE: message"
+func (d *Diagnostic) String() string {
+	var sb strings.Builder
+	sb.WriteString(d.Severity.String())
+	sb.WriteString(":")
+	if d.Path != "" || d.Sheet != "" {
+		sb.WriteString(" [" + d.Path + "]")
+		if d.Sheet != "" {
+			sb.WriteString("[" + d.Sheet + "]")
+		}
+		if cell := d.Cell(); cell != "" {
+			sb.WriteString("!" + cell)
+		} else if d.Row > 0 {
+			sb.WriteString("!row" + strconv.Itoa(d.Row))
+		}
+	}
+	if d.Table != "" || d.Field != "" {
+		sb.WriteString(" ")
+		sb.WriteString(d.Table)
+		if d.Table != "" && d.Field != "" {
+			sb.WriteString(".")
+		}
+		sb.WriteString(d.Field)
+	}
+	if d.Rule != "" {
+		sb.WriteString(" " + d.Rule)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(d.Message)
+	return sb.String()
+}
+
+// merge 使用 o 中的位置信息填充 d 中尚未设置的字段
+func (d *Diagnostic) merge(o *Diagnostic) {
+	if d.Path == "" {
+		d.Path = o.Path
+	}
+	if d.Sheet == "" {
+		d.Sheet = o.Sheet
+	}
+	if d.Row == 0 {
+		d.Row = o.Row
+	}
+	if d.Col == 0 {
+		d.Col = o.Col
+	}
+	if d.Table == "" {
+		d.Table = o.Table
+	}
+	if d.Field == "" {
+		d.Field = o.Field
+	}
+	if d.Rule == "" {
+		d.Rule = o.Rule
+	}
+}
+
+// columnName 将列号(从1开始)转换为列名, 例如 1->A, 27->AA
+func columnName(col int) string {
+	var buf [8]byte
+	i := len(buf)
+	for col > 0 {
+		col--
+		i--
+		buf[i] = byte('A' + col%26)
+		col /= 26
+	}
+	return string(buf[i:])
+}
+
+// diagnosticError 携带诊断信息的错误, 错误信息与被包装的错误保持一致
+type diagnosticError struct {
+	error
+	diag *Diagnostic
+}
+
+func (e *diagnosticError) Unwrap() error {
+	return e.error
+}
+
+// withDiagnostic 为错误补充诊断信息.
+// 已携带诊断信息的错误仅填充尚未设置的字段, 因此内层(更精确)的位置信息优先;
+// 对 Errors 中的每个错误分别处理.
+func withDiagnostic(err error, d Diagnostic) error {
+	if err == nil {
+		return nil
+	}
+	if list, ok := err.(Errors); ok {
+		wrapped := make(Errors, len(list))
+		for i, e := range list {
+			wrapped[i] = withDiagnostic(e, d)
+		}
+		return wrapped
+	}
+	var de *diagnosticError
+	if errors.As(err, &de) {
+		de.diag.merge(&d)
+		return err
+	}
+	diag := d
+	diag.Message = err.Error()
+	return &diagnosticError{error: err, diag: &diag}
+}
+
+// Diagnostics 将解析返回的错误转换为诊断信息列表.
+// 开启 Options.CollectErrors 时每个错误对应一条诊断信息.
+func Diagnostics(err error) []*Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	var list []error
+	switch e := err.(type) {
+	case Errors:
+		list = e
+	case *linkErrors:
+		list = e.errs
+	default:
+		list = []error{err}
+	}
+
+	diags := make([]*Diagnostic, 0, len(list))
+	for _, e := range list {
+		var de *diagnosticError
+		if errors.As(e, &de) {
+			diag := *de.diag
+			diags = append(diags, &diag)
+		} else {
+			diags = append(diags, &Diagnostic{Severity: SeverityError, Message: e.Error()})
+		}
+	}
+	return diags
+}
@@ -3,16 +3,19 @@ package parse
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/godyy/gexcels"
 	pkg_errors "github.com/pkg/errors"
-	"github.com/tealeg/xlsx/v3"
 )
 
 // Enum 枚举.
 type Enum struct {
-	*gexcels.Enum       // 基础信息
-	ItemValues    []any // 枚举项值列表
+	*gexcels.Enum        // 基础信息
+	ItemValues    []any  // 枚举项值列表
+	Path          string // 数据源路径
+	Sheet         string // sheet名
+	Row           int    // 定义开始行号(从1开始)
 }
 
 // newEnum 创建枚举.
@@ -45,8 +48,32 @@ func (e *Enum) GetItemValueByName(itemName string) (any, bool) {
 	}
 }
 
-// enumBeginRegexp 匹配开始定义枚举的正则表达式.
-var enumBeginRegexp = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9_]*)_BEGIN$`)
+// enumFlagsSep 位标志枚举值中枚举项名称的分隔符, e.g. Fire|Poison
+const enumFlagsSep = "|"
+
+// parseFlagsValue 解析位标志枚举值, s 为以 | 分隔的枚举项名称, 结果为各枚举项值的按位或.
+// s 为空时值为 0.
+func (e *Enum) parseFlagsValue(s string) (int32, error) {
+	var value int32
+	for _, name := range strings.Split(s, enumFlagsSep) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			if strings.TrimSpace(s) == "" {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("flags %s has empty item name", s)
+		}
+		item := e.GetItemByName(name)
+		if item == nil {
+			return 0, errEnumItemNotDefine(e.Name, name)
+		}
+		value |= e.GetItemValue(item.Index).(int32)
+	}
+	return value, nil
+}
+
+// enumBeginRegexp 匹配开始定义枚举的正则表达式, 以 _FLAGS 结尾时定义位标志枚举.
+var enumBeginRegexp = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9_]*)_(BEGIN|FLAGS)$`)
 
 // addEnum 添加枚举
 func (p *Parser) addEnum(enum *Enum) error {
@@ -66,12 +93,15 @@ func (p *Parser) GetEnum(name string) *Enum {
 
 // parseEnums 解析枚举.
 func (p *Parser) parseEnums(enumFiles []string) error {
+	var errs Errors
 	for _, file := range enumFiles {
 		if err := p.parseEnumFile(file); err != nil {
-			return err
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
 		}
 	}
-	return nil
+	return errs.err()
 }
 
 // enumSheetNameRegexp 枚举sheet名匹配正则表达式
@@ -79,27 +109,34 @@ var enumSheetNameRegexp = regexp.MustCompile(`^(.*)\|(Enum\w*)`)
 
 // parseEnumFile 解析枚举文件.
 func (p *Parser) parseEnumFile(path string) error {
-	file, err := xlsx.OpenFile(path)
+	file, err := p.openDefineWorkbook(path)
 	if err != nil {
-		return err
+		return withDiagnostic(err, Diagnostic{Path: path})
 	}
 
-	for _, sheet := range file.Sheets {
-		matches := enumSheetNameRegexp.FindStringSubmatch(sheet.Name)
+	var errs Errors
+	for _, sheet := range file.Sheets() {
+		matches := enumSheetNameRegexp.FindStringSubmatch(sheet.Name())
 		if len(matches) != 3 {
 			continue
 		}
 
-		if err := p.parseEnumSheet(sheet); err != nil {
-			return pkg_errors.WithMessagef(err, "enum file(%s).sheet(%s)", path, sheet.Name)
+		if err := p.parseEnumSheet(path, sheet); err != nil {
+			err = withDiagnostic(
+				pkg_errors.WithMessagef(err, "enum file(%s).sheet(%s)", path, sheet.Name()),
+				Diagnostic{Path: path, Sheet: sheet.Name()},
+			)
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
 		}
 	}
-	return nil
+	return errs.err()
 }
 
 // parseEnumOfSheet 解析枚举表
-func (p *Parser) parseEnumSheet(sheet *xlsx.Sheet) error {
-	if sheet.MaxRow < gexcels.EnumRowFirstEntry || sheet.MaxCol < gexcels.EnumCols {
+func (p *Parser) parseEnumSheet(path string, sheet Sheet) error {
+	if sheet.MaxRow() < gexcels.EnumRowFirstEntry || sheet.MaxCol() < gexcels.EnumCols {
 		return errSheetRowsOrColsNotMatch
 	}
 
@@ -110,15 +147,16 @@ func (p *Parser) parseEnumSheet(sheet *xlsx.Sheet) error {
 	)
 
 	row = gexcels.EnumRowFirstEntry
-	for row < sheet.MaxRow {
+	for row < sheet.MaxRow() {
 		var newRow int
 		enum, newRow, err = p.parseEnum(sheet, row)
 		if err != nil {
-			return pkg_errors.WithMessagef(err, "parse enum start at %d", row)
+			return withDiagnostic(pkg_errors.WithMessagef(err, "parse enum start at %d", row), Diagnostic{Row: row + 1})
 		}
 		if enum != nil {
+			enum.Path, enum.Sheet, enum.Row = path, sheet.Name(), row+1
 			if err := p.addEnum(enum); err != nil {
-				return pkg_errors.WithMessagef(err, "add enum %s", enum.Name)
+				return withDiagnostic(pkg_errors.WithMessagef(err, "add enum %s", enum.Name), Diagnostic{Row: row + 1})
 			}
 		}
 		row = newRow
@@ -127,7 +165,7 @@ func (p *Parser) parseEnumSheet(sheet *xlsx.Sheet) error {
 }
 
 // parseEnum 解析枚举.
-func (p *Parser) parseEnum(sheet *xlsx.Sheet, row int) (*Enum, int, error) {
+func (p *Parser) parseEnum(sheet Sheet, row int) (*Enum, int, error) {
 	if isSheetRowComment(sheet, row) {
 		return nil, row + 1, nil
 	}
@@ -140,7 +178,7 @@ func (p *Parser) parseEnum(sheet *xlsx.Sheet, row int) (*Enum, int, error) {
 		return nil, row + 1, nil
 	}
 	matches := enumBeginRegexp.FindStringSubmatch(enumBegin)
-	if len(matches) != 2 {
+	if len(matches) != 3 {
 		return nil, 0, fmt.Errorf("invalid begin \"%s\"", enumBegin)
 	}
 	enumName := matches[1]
@@ -153,7 +191,7 @@ func (p *Parser) parseEnum(sheet *xlsx.Sheet, row int) (*Enum, int, error) {
 		return nil, 0, fmt.Errorf("invalid tag %s", enumTag)
 	} else if !ok {
 		row += 1
-		for row < sheet.MaxRow {
+		for row < sheet.MaxRow() {
 			itemName, err := getSheetValue(sheet, row, gexcels.EnumColItemName, true)
 			if err != nil {
 				return nil, 0, pkg_errors.WithMessagef(err, "get item name cell at row %d", row)
@@ -174,7 +212,7 @@ func (p *Parser) parseEnum(sheet *xlsx.Sheet, row int) (*Enum, int, error) {
 	if err != nil {
 		return nil, 0, pkg_errors.WithMessage(err, "parse type")
 	}
-	if !enumTypeInfo.Type.CanEnum() {
+	if !enumTypeInfo.Type.CanEnum() || enumTypeInfo.Optional {
 		return nil, row, fmt.Errorf("invalid enum type %s", enumType)
 	}
 
@@ -184,8 +222,14 @@ func (p *Parser) parseEnum(sheet *xlsx.Sheet, row int) (*Enum, int, error) {
 	}
 
 	enum := newEnum(enumName, enumTypeInfo.Type, enumDesc)
+	if matches[2] == "FLAGS" {
+		if enum.Type != gexcels.FTInt32 {
+			return nil, row, fmt.Errorf("invalid flags enum type %s, must be int32", enumType)
+		}
+		enum.Flags = true
+	}
 	row += 1
-	for row < sheet.MaxRow {
+	for row < sheet.MaxRow() {
 		if isSheetRowComment(sheet, row) {
 			row++
 			continue
@@ -213,6 +257,9 @@ func (p *Parser) parseEnum(sheet *xlsx.Sheet, row int) (*Enum, int, error) {
 		if err != nil {
 			return nil, 0, pkg_errors.WithMessage(err, "parse [%d ]item value")
 		}
+		if v, ok := value.(int32); ok && enum.Flags && v&(v-1) != 0 {
+			return nil, 0, fmt.Errorf("flags item %s value %d must be 0 or power of two", itemName, v)
+		}
 		enum.addItem(gexcels.NewEnumItem(itemName, itemDesc, itemValue), value)
 		row++
 	}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/godyy/gexcels"
+	pkg_errors "github.com/pkg/errors"
 )
 
 // Exported Errors
@@ -13,6 +14,9 @@ var (
 	// ErrNoPathSpecified 未指定路径
 	ErrNoPathSpecified = errors.New("parse: no path specified")
 
+	// ErrNoFSSpecified 未指定文件系统
+	ErrNoFSSpecified = errors.New("parse: no fs specified")
+
 	// ErrLinkErrorsFound 发现link错误
 	ErrLinkErrorsFound = errors.New("parse: link errors found")
 )
@@ -30,6 +34,12 @@ var (
 	// errIDNonPrimitive ID字段非primitive类型
 	errIDNonPrimitive = fmt.Errorf("field %s type must be primitive", gexcels.TableFieldIDName)
 
+	// errIDOptional ID字段为可选类型
+	errIDOptional = fmt.Errorf("field %s type cant be optional", gexcels.TableFieldIDName)
+
+	// errIDText ID字段为文本类型
+	errIDText = fmt.Errorf("field %s type cant be text", gexcels.TableFieldIDName)
+
 	// errStructFieldDefineInvalid 结构体字段定义无效
 	errStructFieldDefineInvalid = errors.New("struct field definition invalid")
 
@@ -40,6 +50,74 @@ var (
 	errArrayLengthExceedLimit = errors.New("array length exceed limit")
 )
 
+// Errors 多个错误, 开启 Options.CollectErrors 时解析返回所有发现的错误
+type Errors []error
+
+func (errs Errors) Error() string {
+	var sb strings.Builder
+	for i, err := range errs {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap 支持 errors.Is/errors.As 检查其中的错误
+func (errs Errors) Unwrap() []error {
+	return errs
+}
+
+// err 无错误时返回 nil
+func (errs Errors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// appendError 追加错误, Errors 会被展开
+func appendError(errs Errors, err error) Errors {
+	if list, ok := err.(Errors); ok {
+		return append(errs, list...)
+	}
+	return append(errs, err)
+}
+
+// withMessage 为错误附加信息, 对 Errors 中的每个错误分别附加
+func withMessage(err error, message string) error {
+	if list, ok := err.(Errors); ok {
+		wrapped := make(Errors, len(list))
+		for i, e := range list {
+			wrapped[i] = pkg_errors.WithMessage(e, message)
+		}
+		return wrapped
+	}
+	return pkg_errors.WithMessage(err, message)
+}
+
+// withMessagef 为错误附加格式化信息, 对 Errors 中的每个错误分别附加
+func withMessagef(err error, format string, args ...any) error {
+	return withMessage(err, fmt.Sprintf(format, args...))
+}
+
+// collectError 收集错误.
+// 未开启 Options.CollectErrors 时返回 err, 调用方应终止解析;
+// 否则将 err 记录到 errs 中并返回 nil, 调用方可继续解析.
+func (p *Parser) collectError(errs *Errors, err error) error {
+	if !p.options.CollectErrors {
+		return err
+	}
+	*errs = appendError(*errs, err)
+	return nil
+}
+
+// errSourceNameDuplicate 数据源名称重复
+func errSourceNameDuplicate(name string) error {
+	return fmt.Errorf("source name %s duplicate", name)
+}
+
 // errFieldTypeInvalid 字段类型无效
 func errFieldTypeInvalid(ft any) error {
 	return fmt.Errorf("field type %v invalid", ft)
@@ -95,11 +173,43 @@ func errFieldRuleOnNonPrimitiveField(name string) error {
 	return fmt.Errorf("field rule %s on non-primitive field", name)
 }
 
+// errFieldRuleOnOptionalField 字段规则应用在可选字段上
+func errFieldRuleOnOptionalField(name string) error {
+	return fmt.Errorf("field rule %s on optional field", name)
+}
+
+// errFieldRuleWithout 字段规则缺少依赖的规则
+func errFieldRuleWithout(name, required string) error {
+	return fmt.Errorf("field rule %s without %s", name, required)
+}
+
+// errFieldRuleOnTextField 字段规则应用在文本字段上
+func errFieldRuleOnTextField(name string) error {
+	return fmt.Errorf("field rule %s on text field", name)
+}
+
 // errFieldRuleOnGlobalTable 字段规则应用在全局配置表上
 func errFieldRuleOnGlobalTable(name string) error {
 	return fmt.Errorf("field rule %s on global table", name)
 }
 
+// linkErrors 链接检查错误, 可通过 errors.Is(err, ErrLinkErrorsFound) 判断
+type linkErrors struct {
+	errs Errors
+}
+
+func (e *linkErrors) Error() string {
+	return ErrLinkErrorsFound.Error()
+}
+
+func (e *linkErrors) Is(target error) bool {
+	return target == ErrLinkErrorsFound
+}
+
+func (e *linkErrors) Unwrap() []error {
+	return e.errs
+}
+
 // tableLinkError 封装TableLink失败错误
 type tableLinkError struct {
 	srcTable string
@@ -112,7 +222,21 @@ func (err *tableLinkError) Error() string {
 	if err.link.kind == tableLinkKindMapKey {
 		srcField += fmt.Sprintf("[k%d]", err.link.mapLevel)
 	}
-	return fmt.Sprintf(" link [%s.%s -> %s.%s] %s", err.srcTable, srcField, err.link.dstTable, err.link.dstField, err.msg)
+	dstField := err.link.dstField
+	if len(err.link.dstKey) > 0 {
+		keys := make([]string, len(err.link.dstKey))
+		for i, key := range err.link.dstKey {
+			keys[i] = key
+			if err.link.srcKey[i] != key {
+				keys[i] += gexcels.FRNameValueSep + err.link.srcKey[i]
+			}
+		}
+		dstField = "(" + strings.Join(keys, gexcels.FRValueSep) + ")"
+	}
+	if err.link.filter != "" {
+		dstField += "[" + err.link.filter + "]"
+	}
+	return fmt.Sprintf(" link [%s.%s -> %s.%s] %s", err.srcTable, srcField, err.link.dstTable, dstField, err.msg)
 }
 
 // errTableLink 通过配置表link规则生成配置表链接错误
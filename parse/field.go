@@ -34,6 +34,13 @@ func parsePrimitiveValue(ft gexcels.FieldType, s string) (any, error) {
 		}
 		return int64(i), nil
 
+	case gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return zeroValue(ft), nil
+		}
+		return parseIntegerValue(ft, s)
+
 	case gexcels.FTFloat32:
 		s = strings.TrimSpace(s)
 		if s == "" {
@@ -65,7 +72,7 @@ func parsePrimitiveValue(ft gexcels.FieldType, s string) (any, error) {
 		}
 		return b, nil
 
-	case gexcels.FTString:
+	case gexcels.FTString, gexcels.FTText:
 		if len(s) > gexcels.MaxStringLen {
 			return nil, errStringLengthExceedLimit
 		}
@@ -75,8 +82,51 @@ func parsePrimitiveValue(ft gexcels.FieldType, s string) (any, error) {
 	}
 }
 
+// parseIntegerValue 按整数类型 ft 的位宽解析整数值, 超出范围时返回错误
+func parseIntegerValue(ft gexcels.FieldType, s string) (any, error) {
+	if ft.Unsigned() {
+		u, err := strconv.ParseUint(s, 10, ft.BitSize())
+		if err != nil {
+			return nil, err
+		}
+		switch ft {
+		case gexcels.FTUint8:
+			return uint8(u), nil
+		case gexcels.FTUint16:
+			return uint16(u), nil
+		case gexcels.FTUint32:
+			return uint32(u), nil
+		default:
+			return u, nil
+		}
+	}
+
+	i, err := strconv.ParseInt(s, 10, ft.BitSize())
+	if err != nil {
+		return nil, err
+	}
+	return signedIntegerValue(ft, i), nil
+}
+
+// signedIntegerValue 将 i 转换为有符号整数类型 ft 的值, 调用方需保证 i 在 ft 的范围内
+func signedIntegerValue(ft gexcels.FieldType, i int64) any {
+	switch ft {
+	case gexcels.FTInt8:
+		return int8(i)
+	case gexcels.FTInt16:
+		return int16(i)
+	case gexcels.FTInt32:
+		return int32(i)
+	default:
+		return i
+	}
+}
+
 // addCustomFieldType 添加自定义字段类型
 func (p *Parser) addCustomFieldType(ft *gexcels.FieldTypeInfo) error {
+	if gexcels.ParseBuiltinTupleFieldType(ft.GetName()) != nil {
+		return fmt.Errorf("custom field type %s conflicts with builtin tuple type", ft.GetName())
+	}
 	if ft, ok := p.customFieldTypes[ft.GetName()]; ok {
 		return fmt.Errorf("custom field type [%s,%s] already define", ft.Type, ft.GetName())
 	}
@@ -149,6 +199,39 @@ func (p *Parser) parseFieldTypeInfo(typeStr string) (*gexcels.FieldTypeInfo, err
 			return gexcels.NewPrimitiveFieldTypeInfo(ft), nil
 		}
 
+		if scale, ok, err := gexcels.ParseDecimalFieldType(s); ok {
+			if err != nil {
+				return nil, err
+			}
+			return gexcels.NewDecimalFieldTypeInfo(scale), nil
+		}
+
+		if ti := gexcels.ParseBuiltinTupleFieldType(s); ti != nil {
+			return ti, nil
+		}
+
+		if elemStrs, ok, err := gexcels.SplitGenericTupleFieldType(s); ok {
+			// 元组元素：非可选的 primitive 或枚举
+			if err != nil {
+				return nil, err
+			}
+			elems := make([]*gexcels.FieldTypeInfo, len(elemStrs))
+			for i, es := range elemStrs {
+				et, err := parse(es)
+				if err != nil {
+					return nil, err
+				}
+				if !et.Type.Primitive() && et.Type != gexcels.FTEnum {
+					return nil, fmt.Errorf("parseFieldTypeInfo: tuple element type %s must be primitive or enum", es)
+				}
+				if et.Type == gexcels.FTText {
+					return nil, fmt.Errorf("parseFieldTypeInfo: text type %s can not be tuple element", es)
+				}
+				elems[i] = et
+			}
+			return gexcels.NewTupleFieldTypeInfo(elems...), nil
+		}
+
 		if !gexcels.MatchName(s) {
 			return nil, fmt.Errorf("parseFieldTypeInfo: custom field type name %s invalid", s)
 		}
@@ -159,6 +242,22 @@ func (p *Parser) parseFieldTypeInfo(typeStr string) (*gexcels.FieldTypeInfo, err
 		return customFieldTypeInfo, nil
 	}
 
+	// 可选标记仅允许出现在最外层的 primitive/enum 类型上
+	if base, ok := strings.CutSuffix(strings.TrimSpace(typeStr), gexcels.FTOptionalSuffix); ok {
+		ti, err := parse(base)
+		if err != nil {
+			return nil, err
+		}
+		if !ti.Type.Primitive() && ti.Type != gexcels.FTEnum {
+			return nil, fmt.Errorf("parseFieldTypeInfo: optional type %s must be primitive or enum", base)
+		}
+		if ti.Type == gexcels.FTText {
+			// 文本为空时即为空key, 无需可选
+			return nil, fmt.Errorf("parseFieldTypeInfo: text type %s can not be optional", base)
+		}
+		return gexcels.NewOptionalFieldTypeInfo(ti), nil
+	}
+
 	return parse(typeStr)
 }
 
@@ -166,6 +265,11 @@ func (p *Parser) parseFieldTypeInfo(typeStr string) (*gexcels.FieldTypeInfo, err
 func (p *Parser) parseFieldValue(fd *gexcels.Field, s string) (any, error) {
 	s = strings.TrimSpace(s)
 
+	// 可选字段未填写时视为未设置
+	if fd.Optional && s == "" {
+		return nil, nil
+	}
+
 	if fd.Type == gexcels.FTEnum {
 		return p.parseEnumFieldValue(fd, s)
 	} else if fd.Type == gexcels.FTArray {
@@ -174,6 +278,14 @@ func (p *Parser) parseFieldValue(fd *gexcels.Field, s string) (any, error) {
 		return p.parseMapFieldValue(fd, s)
 	} else if fd.Type == gexcels.FTStruct {
 		return p.parseStructFieldValue(fd, s)
+	} else if fd.Type == gexcels.FTUnion {
+		return p.parseUnionFieldValue(fd, s)
+	} else if fd.Type == gexcels.FTTuple {
+		return p.parseTupleFieldValue(fd, s)
+	} else if fd.Type == gexcels.FTDatetime || fd.Type == gexcels.FTDuration {
+		return p.parseTimeValue(fd.Type, s)
+	} else if fd.Type == gexcels.FTDecimal {
+		return parseDecimalValue(fd.GetScale(), s)
 	} else {
 		return parsePrimitiveValue(fd.Type, s)
 	}
@@ -181,16 +293,20 @@ func (p *Parser) parseFieldValue(fd *gexcels.Field, s string) (any, error) {
 
 // parseEnumFieldValue 解析枚举字段值
 func (p *Parser) parseEnumFieldValue(fd *gexcels.Field, s string) (any, error) {
-	if s == "" {
-		return nil, errors.New("empty enum item name")
-	}
-
 	enumName := fd.FieldTypeInfo.GetName()
 	enum := p.GetEnum(enumName)
 	if enum == nil {
 		return nil, errEnumNotDefine(enumName)
 	}
 
+	if enum.Flags {
+		return enum.parseFlagsValue(s)
+	}
+
+	if s == "" {
+		return nil, errors.New("empty enum item name")
+	}
+
 	item := enum.GetItemByName(s)
 	if item == nil {
 		return nil, errEnumItemNotDefine(enumName, s)
@@ -205,6 +321,16 @@ func (p *Parser) parseArrayFieldValue(fd *gexcels.Field, s string) (any, error)
 		return nil, nil
 	}
 
+	// 元组数组支持紧凑写法, e.g. 1,2;3,4
+	if fd.GetElementType().Type == gexcels.FTTuple && !strings.HasPrefix(s, "[") {
+		tokens := strings.Split(s, gexcels.TupleArraySep)
+		raw := make([]any, len(tokens))
+		for i, token := range tokens {
+			raw[i] = token
+		}
+		return p.convertFieldJSONValue(fd.FieldTypeInfo, raw, fd.Name)
+	}
+
 	var raw any
 	if err := json.Unmarshal(([]byte)(s), &raw); err != nil {
 		return nil, err
@@ -0,0 +1,235 @@
+package parse
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/godyy/gexcels"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// checkFieldRuleType 检查规则 fr 能否作用于字段 fd
+func checkFieldRuleType(fd *gexcels.Field, fr gexcels.FieldRule) error {
+	if v, ok := fr.(gexcels.FRFieldValidator); ok {
+		return v.ValidateField(fd)
+	}
+	return nil
+}
+
+// checkFieldValue 检查字段值是否满足字段规则, 结构体值同时检查结构体字段的规则
+func (p *Parser) checkFieldValue(fd *gexcels.Field, val any) error {
+	return p.checkValueRules(fd, fd.FieldTypeInfo, val, fd.Name)
+}
+
+// checkValueRules 检查类型为 ti 的值 val 是否满足字段 fd 的规则.
+// 数组元素以及 map 的值沿用所属字段的规则.
+func (p *Parser) checkValueRules(fd *gexcels.Field, ti *gexcels.FieldTypeInfo, val any, path string) error {
+	switch ti.Type {
+	case gexcels.FTArray:
+		if val == nil {
+			return nil
+		}
+		rv := reflect.ValueOf(val)
+		for i := 0; i < rv.Len(); i++ {
+			if err := p.checkValueRules(fd, ti.GetElementType(), rv.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case gexcels.FTMap:
+		if val == nil {
+			return nil
+		}
+		rv := reflect.ValueOf(val)
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			if err := p.checkValueRules(fd, ti.GetMapValueType(), rv.MapIndex(key).Interface(), fmt.Sprintf("%s[%v]", path, key.Interface())); err != nil {
+				return err
+			}
+		}
+	case gexcels.FTStruct:
+		sd := p.GetStructByName(ti.GetName())
+		m, ok := val.(map[string]any)
+		if sd == nil || !ok {
+			return nil
+		}
+		for _, sfd := range sd.Fields {
+			if err := p.checkValueRules(sfd, sfd.FieldTypeInfo, m[sfd.Name], path+"."+sfd.Name); err != nil {
+				return err
+			}
+		}
+	case gexcels.FTUnion:
+		// 联合类型值检查所选分支结构体字段的规则
+		u := p.GetUnionByName(ti.GetName())
+		uv, ok := val.(*UnionValue)
+		if u == nil || !ok || uv == nil {
+			return nil
+		}
+		c := u.GetCaseByName(uv.Case)
+		if c == nil {
+			return nil
+		}
+		return p.checkValueRules(fd, gexcels.NewStructFieldTypeInfo(c.Struct), uv.Value, path+"."+uv.Case)
+	default:
+		// 未填写的值导出时为零值, 同样需要满足规则; 可选字段未设置时不检查
+		if val == nil {
+			if ti.Optional {
+				return nil
+			}
+			val = zeroValue(ti.Type)
+		}
+		for _, fr := range fd.Rules() {
+			if err := checkValueRule(fr, ti, val, path); err != nil {
+				return withDiagnostic(err, Diagnostic{Rule: fr.FRName()})
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseFieldDefault 按字段类型解析字段的默认值, 默认值需满足字段的其它规则.
+// 字段没有默认值规则时 ok 为 false.
+func (p *Parser) parseFieldDefault(fd *gexcels.Field) (val any, ok bool, err error) {
+	r := fd.GetFRDefault()
+	if r == nil {
+		return nil, false, nil
+	}
+	if val, err = p.parseFieldValue(fd, r.Value); err != nil {
+		return nil, false, pkg_errors.WithMessagef(err, "%s value {%s}", gexcels.FRNDefault, r.Value)
+	}
+	if err = p.checkFieldValue(fd, val); err != nil {
+		return nil, false, pkg_errors.WithMessagef(err, "%s value {%s}", gexcels.FRNDefault, r.Value)
+	}
+	return val, true, nil
+}
+
+// checkValueRule 检查类型为 ti 的值 val 是否满足规则 fr
+func checkValueRule(fr gexcels.FieldRule, ti *gexcels.FieldTypeInfo, val any, path string) error {
+	v, ok := fr.(gexcels.FRValueValidator)
+	if !ok {
+		return nil
+	}
+	if err := v.ValidateValue(val); err != nil {
+		switch o := val.(type) {
+		case string:
+			return fmt.Errorf("%s={%s} %w", path, o, err)
+		case int64:
+			// decimal 值按小数位数输出, 而不是缩放后的整数
+			if ti.Type == gexcels.FTDecimal {
+				return fmt.Errorf("%s=%s %w", path, formatDecimalValue(ti.GetScale(), o), err)
+			}
+		}
+		return fmt.Errorf("%s=%v %w", path, val, err)
+	}
+	return nil
+}
+
+// validateEntry 调用字段规则的条目校验, row 为条目所在行号.
+// 全局配置表的所有字段值视为一个条目, 错误定位到字段所在行.
+// customOnly 为 true 时仅调用自定义规则.
+func (p *Parser) validateEntry(td *Table, entry gexcels.TableEntry, row int, customOnly bool) error {
+	var errs Errors
+	for _, fd := range td.Fields {
+		for _, fr := range fd.Rules() {
+			v, ok := fr.(gexcels.FREntryValidator)
+			if !ok || (customOnly && gexcels.IsBuiltinFieldRule(fr.FRName())) {
+				continue
+			}
+			err := v.ValidateEntry(fd.Field, entry)
+			if err == nil {
+				continue
+			}
+
+			diag := Diagnostic{Field: fd.Name, Rule: fr.FRName()}
+			if td.IsGlobal {
+				diag.Row, diag.Col = td.GetFieldRow(fd.Name), gexcels.GlobalTableColFieldValue+1
+				err = pkg_errors.WithMessagef(err, "field {%s} %s", fd.Name, fr.FRName())
+			} else {
+				diag.Row, diag.Col = row, fd.Col+1
+				err = pkg_errors.WithMessagef(err, "row[%d] %s %s", row, fd.Name, fr.FRName())
+			}
+			if err := p.collectError(&errs, withDiagnostic(err, diag)); err != nil {
+				return err
+			}
+		}
+	}
+	return errs.err()
+}
+
+// validateTable 调用字段规则的配置表校验, 错误定位到字段规则单元格.
+// customOnly 为 true 时仅调用自定义规则.
+func (p *Parser) validateTable(td *Table, customOnly bool) error {
+	entries := td.Entries
+	if td.IsGlobal {
+		entries = []gexcels.TableEntry{td.entryByName}
+	}
+
+	var errs Errors
+	for _, fd := range td.Fields {
+		for _, fr := range fd.Rules() {
+			v, ok := fr.(gexcels.FRTableValidator)
+			if !ok || (customOnly && gexcels.IsBuiltinFieldRule(fr.FRName())) {
+				continue
+			}
+			err := v.ValidateTable(td.Table, fd.Field, entries)
+			if err == nil {
+				continue
+			}
+
+			diag := Diagnostic{Table: td.Name, Field: fd.Name, Rule: fr.FRName()}
+			if td.IsGlobal {
+				diag.Row, diag.Col = td.GetFieldRow(fd.Name), gexcels.GlobalTableColFieldRule+1
+			} else {
+				diag.Row, diag.Col = gexcels.TableRowFieldRule+1, fd.Col+1
+			}
+			err = withDiagnostic(pkg_errors.WithMessagef(err, "field %s %s", fd.Name, fr.FRName()), diag)
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+		}
+	}
+	return errs.err()
+}
+
+// zeroValue 获取 primitive 类型的零值, 其它类型返回 nil
+func zeroValue(ft gexcels.FieldType) any {
+	switch ft {
+	case gexcels.FTInt32:
+		return int32(0)
+	case gexcels.FTInt64:
+		return int64(0)
+	case gexcels.FTInt8:
+		return int8(0)
+	case gexcels.FTInt16:
+		return int16(0)
+	case gexcels.FTUint8:
+		return uint8(0)
+	case gexcels.FTUint16:
+		return uint16(0)
+	case gexcels.FTUint32:
+		return uint32(0)
+	case gexcels.FTUint64:
+		return uint64(0)
+	case gexcels.FTFloat32:
+		return float32(0)
+	case gexcels.FTFloat64:
+		return float64(0)
+	case gexcels.FTBool:
+		return false
+	case gexcels.FTString, gexcels.FTText:
+		return ""
+	case gexcels.FTDatetime:
+		return time.Time{}
+	case gexcels.FTDuration:
+		return time.Duration(0)
+	case gexcels.FTDecimal:
+		return int64(0)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+package parse
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileSystem 解析器读取数据源使用的文件系统
+type fileSystem interface {
+	// walk 遍历 root 下的所有文件
+	walk(root string, fn func(path string) error) error
+
+	// readFile 读取文件内容
+	readFile(name string) ([]byte, error)
+
+	// normalize 规范化路径, 用于比较两个路径是否指向同一文件
+	normalize(name string) string
+}
+
+// osFileSystem 操作系统文件系统
+type osFileSystem struct{}
+
+func (osFileSystem) walk(root string, fn func(path string) error) error {
+	return filepath.Walk(root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+func (osFileSystem) readFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (osFileSystem) normalize(name string) string {
+	abs, _ := filepath.Abs(name)
+	return abs
+}
+
+// ioFileSystem io/fs 文件系统, 路径均相对于 fs.FS 的根目录
+type ioFileSystem struct {
+	fsys fs.FS
+}
+
+func (f ioFileSystem) walk(root string, fn func(path string) error) error {
+	return fs.WalkDir(f.fsys, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+func (f ioFileSystem) readFile(name string) ([]byte, error) {
+	return fs.ReadFile(f.fsys, f.normalize(name))
+}
+
+func (ioFileSystem) normalize(name string) string {
+	return normalizeFSPath(name)
+}
+
+// memFileSystem 内存文件系统, 由 ParseSources 提供的数据源构成
+type memFileSystem struct {
+	names []string          // 按名称排序的文件列表
+	files map[string][]byte // 文件内容
+}
+
+func (f *memFileSystem) walk(root string, fn func(path string) error) error {
+	for _, name := range f.names {
+		if err := fn(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *memFileSystem) readFile(name string) ([]byte, error) {
+	data, ok := f.files[f.normalize(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return data, nil
+}
+
+func (*memFileSystem) normalize(name string) string {
+	return normalizeFSPath(name)
+}
+
+// normalizeFSPath 规范化 io/fs 路径
+func normalizeFSPath(name string) string {
+	return strings.TrimPrefix(path.Clean(filepath.ToSlash(name)), "/")
+}
+
+// Source 以 io.Reader 提供的单个数据源(工作簿).
+// 对于 io.ReaderAt, 可通过 io.NewSectionReader 转换为 io.Reader.
+type Source struct {
+	// Name 数据源名称, 扩展名决定解码格式, 例如 "test.xlsx", "道具|Item.csv".
+	// 也用于 Options.EnumFiles/StructFiles 匹配以及默认的枚举/结构体文件识别.
+	Name string
+
+	// Reader 数据源内容
+	Reader io.Reader
+}
+
+// newMemFileSystem 读取所有数据源, 创建内存文件系统
+func newMemFileSystem(sources []Source) (*memFileSystem, error) {
+	f := &memFileSystem{
+		names: make([]string, 0, len(sources)),
+		files: make(map[string][]byte, len(sources)),
+	}
+	for _, source := range sources {
+		name := f.normalize(source.Name)
+		if _, ok := f.files[name]; ok {
+			return nil, errSourceNameDuplicate(source.Name)
+		}
+		data, err := io.ReadAll(source.Reader)
+		if err != nil {
+			return nil, err
+		}
+		f.names = append(f.names, name)
+		f.files[name] = data
+	}
+	sort.Strings(f.names)
+	return f, nil
+}
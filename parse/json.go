@@ -27,12 +27,25 @@ func (p *Parser) convertFieldJSONValue(ti *gexcels.FieldTypeInfo, raw any, path
 	}
 
 	switch ti.Type {
-	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString:
+	case gexcels.FTInt32, gexcels.FTInt64, gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64,
+		gexcels.FTFloat32, gexcels.FTFloat64, gexcels.FTBool, gexcels.FTString, gexcels.FTText:
 		return convertFieldJSONPrimitive(ti, raw, path)
+	case gexcels.FTDatetime, gexcels.FTDuration:
+		return p.convertJSONTimeValue(ti, raw, path)
+	case gexcels.FTDecimal:
+		return convertJSONDecimalValue(ti, raw, path)
 	case gexcels.FTEnum:
 		return p.convertJSONEnumValue(ti, raw, path)
 	case gexcels.FTStruct:
 		return p.convertJSONStructValue(ti, raw, path)
+	case gexcels.FTUnion:
+		uv, err := p.convertJSONUnionValue(ti, raw, path)
+		if err != nil {
+			return nil, err
+		}
+		return uv, nil
+	case gexcels.FTTuple:
+		return p.convertJSONTupleValue(ti, raw, path)
 	case gexcels.FTArray:
 		arr, ok := raw.([]any)
 		if !ok {
@@ -57,13 +70,15 @@ func convertFieldJSONPrimitive(ft *gexcels.FieldTypeInfo, raw any, path string)
 			return int32(0), nil
 		case gexcels.FTInt64:
 			return int64(0), nil
+		case gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+			return zeroValue(ft.Type), nil
 		case gexcels.FTFloat32:
 			return float32(0), nil
 		case gexcels.FTFloat64:
 			return float64(0), nil
 		case gexcels.FTBool:
 			return false, nil
-		case gexcels.FTString:
+		case gexcels.FTString, gexcels.FTText:
 			return "", nil
 		default:
 			return nil, errFieldTypeInvalid(ft)
@@ -82,6 +97,34 @@ func convertFieldJSONPrimitive(ft *gexcels.FieldTypeInfo, raw any, path string)
 		return int32(i64), nil
 	case gexcels.FTInt64:
 		return convertJSONInt64(raw, path)
+	case gexcels.FTInt8, gexcels.FTInt16:
+		i64, err := convertJSONInt64(raw, path)
+		if err != nil {
+			return nil, err
+		}
+		bits := ft.Type.BitSize()
+		if i64 < -1<<(bits-1) || i64 > 1<<(bits-1)-1 {
+			return nil, fmt.Errorf("%s out of %s range", path, ft.Type)
+		}
+		return signedIntegerValue(ft.Type, i64), nil
+	case gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+		u64, err := convertJSONUint64(raw, path)
+		if err != nil {
+			return nil, err
+		}
+		if bits := ft.Type.BitSize(); bits < 64 && u64 > 1<<bits-1 {
+			return nil, fmt.Errorf("%s out of %s range", path, ft.Type)
+		}
+		switch ft.Type {
+		case gexcels.FTUint8:
+			return uint8(u64), nil
+		case gexcels.FTUint16:
+			return uint16(u64), nil
+		case gexcels.FTUint32:
+			return uint32(u64), nil
+		default:
+			return u64, nil
+		}
 	case gexcels.FTFloat32:
 		f64, err := convertJSONFloat64(raw, path)
 		if err != nil {
@@ -106,7 +149,7 @@ func convertFieldJSONPrimitive(ft *gexcels.FieldTypeInfo, raw any, path string)
 		default:
 			return nil, fmt.Errorf("%s must be bool", path)
 		}
-	case gexcels.FTString:
+	case gexcels.FTString, gexcels.FTText:
 		s, ok := raw.(string)
 		if !ok {
 			return nil, fmt.Errorf("%s must be string", path)
@@ -120,6 +163,41 @@ func convertFieldJSONPrimitive(ft *gexcels.FieldTypeInfo, raw any, path string)
 	}
 }
 
+// convertJSONUint64 将json raw对象转换为uint64类型值
+func convertJSONUint64(raw any, path string) (uint64, error) {
+	switch v := raw.(type) {
+	case uint64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%s must be integer", path)
+		}
+		if v < 0 || v >= math.MaxUint64 {
+			return 0, fmt.Errorf("%s out of uint64 range", path)
+		}
+		return uint64(v), nil
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return 0, nil
+		}
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s invalid uint", path)
+		}
+		return u, nil
+	default:
+		i64, err := convertJSONInt64(raw, path)
+		if err != nil {
+			return 0, err
+		}
+		if i64 < 0 {
+			return 0, fmt.Errorf("%s out of uint64 range", path)
+		}
+		return uint64(i64), nil
+	}
+}
+
 // convertJSONInt64 将json raw对象转换为int64类型值
 func convertJSONInt64(raw any, path string) (int64, error) {
 	switch v := raw.(type) {
@@ -197,6 +275,13 @@ func (p *Parser) convertJSONEnumValue(ft *gexcels.FieldTypeInfo, raw any, path s
 	if !ok {
 		return nil, fmt.Errorf("%s must be string", path)
 	}
+	if enum.Flags {
+		v, err := enum.parseFlagsValue(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s %w", path, err)
+		}
+		return v, nil
+	}
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return nil, fmt.Errorf("%s is empty", path)
@@ -226,7 +311,15 @@ func (p *Parser) convertJSONStructValue(ft *gexcels.FieldTypeInfo, raw any, path
 		fieldPath := path + "." + fd.Name
 		v, ok := m[fd.Name]
 		if !ok || v == nil {
-			if fd.Type == gexcels.FTEnum {
+			if def, ok := sd.defaults[fd.Name]; ok {
+				out[fd.Name] = def
+				continue
+			}
+			if fd.Type == gexcels.FTEnum && !fd.Optional {
+				if enum := p.GetEnum(fd.GetName()); enum != nil && enum.Flags {
+					out[fd.Name] = int32(0)
+					continue
+				}
 				return nil, fmt.Errorf("%s must be string", fieldPath)
 			}
 			continue
@@ -306,6 +399,8 @@ func (p *Parser) getMapKeyType(keyType *gexcels.FieldTypeInfo) (reflect.Type, er
 		return reflect.TypeOf(int32(0)), nil
 	case gexcels.FTInt64:
 		return reflect.TypeOf(int64(0)), nil
+	case gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+		return reflect.TypeOf(zeroValue(keyType.Type)), nil
 	case gexcels.FTFloat32:
 		return reflect.TypeOf(float32(0)), nil
 	case gexcels.FTFloat64:
@@ -352,6 +447,12 @@ func (p *Parser) parseJSONMapKey(keyType *gexcels.FieldTypeInfo, keyStr string,
 			return nil, fmt.Errorf("%s map key invalid int64", path)
 		}
 		return i, nil
+	case gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+		k, err := parseIntegerValue(keyType.Type, keyStr)
+		if err != nil {
+			return nil, fmt.Errorf("%s map key invalid %s", path, keyType.Type)
+		}
+		return k, nil
 	case gexcels.FTString:
 		return keyStr, nil
 	case gexcels.FTEnum:
@@ -397,6 +498,16 @@ func (p *Parser) convertJSONArrayValue(ft *gexcels.FieldTypeInfo, raw any, path
 			out[i] = c.(int64)
 		}
 		return out, nil
+	case gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+		out := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(zeroValue(elem.Type))), len(arr), len(arr))
+		for i, v := range arr {
+			c, err := convertFieldJSONPrimitive(elem, v, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out.Index(i).Set(reflect.ValueOf(c))
+		}
+		return out.Interface(), nil
 	case gexcels.FTFloat32:
 		out := make([]float32, len(arr))
 		for i, v := range arr {
@@ -427,7 +538,7 @@ func (p *Parser) convertJSONArrayValue(ft *gexcels.FieldTypeInfo, raw any, path
 			out[i] = c.(bool)
 		}
 		return out, nil
-	case gexcels.FTString:
+	case gexcels.FTString, gexcels.FTText:
 		out := make([]string, len(arr))
 		for i, v := range arr {
 			c, err := convertFieldJSONPrimitive(elem, v, fmt.Sprintf("%s[%d]", path, i))
@@ -437,6 +548,26 @@ func (p *Parser) convertJSONArrayValue(ft *gexcels.FieldTypeInfo, raw any, path
 			out[i] = c.(string)
 		}
 		return out, nil
+	case gexcels.FTDatetime, gexcels.FTDuration:
+		out := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(zeroValue(elem.Type))), len(arr), len(arr))
+		for i, v := range arr {
+			c, err := p.convertJSONTimeValue(elem, v, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out.Index(i).Set(reflect.ValueOf(c))
+		}
+		return out.Interface(), nil
+	case gexcels.FTDecimal:
+		out := make([]int64, len(arr))
+		for i, v := range arr {
+			c, err := convertJSONDecimalValue(elem, v, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
 	case gexcels.FTEnum:
 		out := make([]any, len(arr))
 		for i, v := range arr {
@@ -457,6 +588,26 @@ func (p *Parser) convertJSONArrayValue(ft *gexcels.FieldTypeInfo, raw any, path
 			out[i] = c
 		}
 		return out, nil
+	case gexcels.FTUnion:
+		out := make([]any, len(arr))
+		for i, v := range arr {
+			c, err := p.convertJSONUnionValue(elem, v, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
+	case gexcels.FTTuple:
+		out := make([]any, len(arr))
+		for i, v := range arr {
+			c, err := p.convertJSONTupleValue(elem, v, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
 	case gexcels.FTMap:
 		out := make([]any, len(arr))
 		for i, v := range arr {
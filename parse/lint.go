@@ -0,0 +1,283 @@
+package parse
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/godyy/gexcels"
+	"github.com/godyy/gexcels/internal/log"
+)
+
+// lint 名称, 用于 LintOptions 以及 Diagnostic.Rule
+const (
+	LintAll              = "all"               // 所有lint
+	LintTrailingSpace    = "trailing-space"    // 字符串单元格首尾存在空白字符, 解析时会被去除
+	LintFloat32Precision = "float32-precision" // float32 字段中的整数超出 float32 精度
+	LintEmptyFlags       = "empty-flags"       // 位标志枚举字段未填写, 值默认为 0
+	LintUnusedEnum       = "unused-enum"       // 未被任何字段使用的枚举
+	LintUnusedStruct     = "unused-struct"     // 未被任何字段使用的结构体
+	LintUnlinkedTable    = "unlinked-table"    // 未被任何配置表链接的配置表
+	LintDuplicateDesc    = "duplicate-desc"    // 同一配置表中字段描述重复
+)
+
+// lintNames 所有lint名称
+var lintNames = map[string]bool{
+	LintAll:              true,
+	LintTrailingSpace:    true,
+	LintFloat32Precision: true,
+	LintEmptyFlags:       true,
+	LintUnusedEnum:       true,
+	LintUnusedStruct:     true,
+	LintUnlinkedTable:    true,
+	LintDuplicateDesc:    true,
+}
+
+// LintOptions lint选项.
+// lint 检查合法但可疑的数据, 默认产生警告, 不影响解析结果.
+type LintOptions struct {
+	// Disable 禁用的lint, 可使用 LintAll 禁用所有lint
+	Disable []string
+
+	// Errors 提升为错误级别的lint, 可使用 LintAll 提升所有lint.
+	// 提升后的lint与解析错误一样, 使解析失败.
+	Errors []string
+}
+
+// init 检查lint名称, 并生成名称映射
+func (opt *LintOptions) init() (disabled, errors map[string]bool, err error) {
+	toMap := func(names []string) (map[string]bool, error) {
+		m := make(map[string]bool, len(names))
+		for _, name := range names {
+			if !lintNames[name] {
+				return nil, fmt.Errorf("parse: lint %s invalid", name)
+			}
+			m[name] = true
+		}
+		return m, nil
+	}
+	if disabled, err = toMap(opt.Disable); err != nil {
+		return
+	}
+	errors, err = toMap(opt.Errors)
+	return
+}
+
+// lintEnabled lint是否开启
+func (opt *Options) lintEnabled(name string) bool {
+	return opt.Lint != nil && !opt.lintDisabled[LintAll] && !opt.lintDisabled[name]
+}
+
+// lintSeverity lint诊断级别
+func (opt *Options) lintSeverity(name string) Severity {
+	if opt.lintErrors[LintAll] || opt.lintErrors[name] {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// addLint 添加配置表单元格lint, 位置相对于配置表所在sheet
+func (td *Table) addLint(name string, d Diagnostic, format string, args ...any) {
+	d.Rule = name
+	d.Message = fmt.Sprintf(format, args...)
+	td.lints = append(td.lints, &d)
+}
+
+// lintCell 检查单元格原始值 s 以及解析后的值 val
+func (td *Table) lintCell(fd *gexcels.Field, s string, val any, d Diagnostic) {
+	switch fd.Type {
+	case gexcels.FTString, gexcels.FTText:
+		if s != strings.TrimSpace(s) {
+			td.addLint(LintTrailingSpace, d, "%s={%s} has leading or trailing spaces", fd.Name, s)
+		}
+	case gexcels.FTFloat32:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || f != math.Trunc(f) {
+			return
+		}
+		if v, ok := val.(float32); ok && float64(v) != f {
+			td.addLint(LintFloat32Precision, d, "%s={%s} exceeds float32 precision, got %v", fd.Name, s, v)
+		}
+	case gexcels.FTEnum:
+		// 普通枚举不能为空, 未填写且非可选、无默认值时仅可能为位标志枚举
+		if _, ok := td.defaults[fd.Name]; !ok && !fd.Optional && strings.TrimSpace(s) == "" {
+			td.addLint(LintEmptyFlags, d, "%s is empty, flags default to %v", fd.Name, val)
+		}
+	}
+}
+
+// lint 执行lint检查.
+// 警告记录到 Parser.Warnings, 提升为错误的lint以错误返回.
+func (p *Parser) lint() error {
+	var diags []*Diagnostic
+
+	for _, td := range p.Tables {
+		for _, d := range td.lints {
+			diag := *d
+			diag.merge(&Diagnostic{Path: td.Path, Sheet: td.Sheet, Table: td.Name})
+			diags = append(diags, &diag)
+		}
+	}
+	diags = append(diags, p.lintUnusedTypes()...)
+	diags = append(diags, p.lintUnlinkedTables()...)
+	diags = append(diags, p.lintDuplicateDesc()...)
+
+	var errs Errors
+	for _, diag := range diags {
+		if !p.options.lintEnabled(diag.Rule) {
+			continue
+		}
+		diag.Severity = p.options.lintSeverity(diag.Rule)
+		if diag.Severity == SeverityError {
+			err := &diagnosticError{error: fmt.Errorf("lint %s: %s", diag.Rule, diag.Message), diag: diag}
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Println(diag)
+		p.Warnings = append(p.Warnings, diag)
+	}
+	return errs.err()
+}
+
+// lintUnusedTypes 检查未使用的枚举、结构体和联合类型
+func (p *Parser) lintUnusedTypes() (diags []*Diagnostic) {
+	used := make(map[string]bool)
+	for _, td := range p.Tables {
+		for _, fd := range td.Fields {
+			collectTypeNames(fd.FieldTypeInfo, used)
+		}
+	}
+	for _, sd := range p.Structs {
+		// 被继承的结构体视为已使用
+		if sd.Parent != nil {
+			used[sd.Parent.Name] = true
+		}
+		for _, fd := range sd.OwnFields() {
+			collectTypeNames(fd.FieldTypeInfo, used)
+		}
+	}
+	for _, u := range p.Unions {
+		used[u.Enum] = true
+		for _, c := range u.Cases {
+			used[c.Struct] = true
+		}
+	}
+
+	for _, enum := range p.Enums {
+		if !used[enum.Name] {
+			diags = append(diags, &Diagnostic{
+				Path:    enum.Path,
+				Sheet:   enum.Sheet,
+				Row:     enum.Row,
+				Col:     gexcels.EnumColBegin + 1,
+				Rule:    LintUnusedEnum,
+				Message: fmt.Sprintf("enum %s unused", enum.Name),
+			})
+		}
+	}
+	for _, sd := range p.Structs {
+		if !used[sd.Name] {
+			diags = append(diags, &Diagnostic{
+				Path:    sd.Path,
+				Sheet:   sd.Sheet,
+				Row:     sd.Row,
+				Col:     gexcels.TableStructColName + 1,
+				Rule:    LintUnusedStruct,
+				Message: fmt.Sprintf("struct %s unused", sd.Name),
+			})
+		}
+	}
+	for _, u := range p.Unions {
+		if !used[u.Name] {
+			diags = append(diags, &Diagnostic{
+				Path:    u.Path,
+				Sheet:   u.Sheet,
+				Row:     u.Row,
+				Col:     gexcels.TableStructColName + 1,
+				Rule:    LintUnusedStruct,
+				Message: fmt.Sprintf("union %s unused", u.Name),
+			})
+		}
+	}
+	return
+}
+
+// collectTypeNames 收集类型中引用的枚举、结构体和联合类型名称
+func collectTypeNames(ti *gexcels.FieldTypeInfo, names map[string]bool) {
+	if ti == nil {
+		return
+	}
+	switch ti.Type {
+	case gexcels.FTEnum, gexcels.FTStruct, gexcels.FTUnion:
+		names[ti.GetName()] = true
+	case gexcels.FTArray:
+		collectTypeNames(ti.GetElementType(), names)
+	case gexcels.FTMap:
+		collectTypeNames(ti.GetMapKeyType(), names)
+		collectTypeNames(ti.GetMapValueType(), names)
+	case gexcels.FTTuple:
+		for _, et := range ti.GetTupleElements() {
+			collectTypeNames(et, names)
+		}
+	}
+}
+
+// lintUnlinkedTables 检查未被任何配置表链接的配置表, 全局配置表除外
+func (p *Parser) lintUnlinkedTables() (diags []*Diagnostic) {
+	linked := make(map[string]bool)
+	for _, td := range p.Tables {
+		for _, link := range td.links {
+			linked[link.dstTable] = true
+		}
+	}
+	for _, td := range p.Tables {
+		if td.IsGlobal || linked[td.Name] {
+			continue
+		}
+		diags = append(diags, &Diagnostic{
+			Path:    td.Path,
+			Sheet:   td.Sheet,
+			Table:   td.Name,
+			Rule:    LintUnlinkedTable,
+			Message: fmt.Sprintf("table %s not linked by any table", td.Name),
+		})
+	}
+	return
+}
+
+// lintDuplicateDesc 检查同一配置表中重复的字段描述
+func (p *Parser) lintDuplicateDesc() (diags []*Diagnostic) {
+	for _, td := range p.Tables {
+		descs := make(map[string]string, len(td.Fields))
+		for _, fd := range td.Fields {
+			desc := strings.TrimSpace(fd.Desc)
+			if desc == "" {
+				continue
+			}
+			first, ok := descs[desc]
+			if !ok {
+				descs[desc] = fd.Name
+				continue
+			}
+
+			diag := &Diagnostic{
+				Path:    td.Path,
+				Sheet:   td.Sheet,
+				Table:   td.Name,
+				Field:   fd.Name,
+				Rule:    LintDuplicateDesc,
+				Message: fmt.Sprintf("field %s desc {%s} duplicate with field %s", fd.Name, desc, first),
+			}
+			if td.IsGlobal {
+				diag.Row, diag.Col = td.GetFieldRow(fd.Name), gexcels.GlobalTableColFieldDesc+1
+			} else {
+				diag.Row, diag.Col = gexcels.TableRowFieldDesc+1, fd.Col+1
+			}
+			diags = append(diags, diag)
+		}
+	}
+	return
+}
@@ -5,14 +5,15 @@ import (
 	"io/fs"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/godyy/gexcels"
 	"github.com/godyy/gexcels/internal/log"
 	pkg_errors "github.com/pkg/errors"
-	"github.com/tealeg/xlsx/v3"
 )
 
 // Options 解析选项
@@ -38,24 +39,49 @@ type Options struct {
 	// OnlyFields 是否仅解析字段定义, 默认为 false
 	OnlyFields bool
 
+	// Concurrency 并发解析配置表文件的最大数量, 默认(<=0)为 runtime.GOMAXPROCS(0)
+	Concurrency int
+
+	// CollectErrors 是否收集所有错误, 默认为 false.
+	// 开启后解析遇到错误时继续解析后续的行、列、sheet及文件, 最终以 Errors 返回所有错误;
+	// 只要存在错误, 解析就不会返回 Parser, 因此也不会产生任何导出结果.
+	CollectErrors bool
+
+	// CacheDir 增量解析缓存目录, 为空时不使用缓存.
+	// 内容未变化的数据源直接复用缓存的解析结果; 枚举或结构体文件变化时, 所有配置表缓存失效.
+	CacheDir string
+
+	// TimeZone datetime 字段的时区, IANA 时区名, e.g. "Asia/Shanghai", 默认为 UTC.
+	// 未指定时区的时间字符串以及 Excel 日期序列值按该时区解析, 解析结果统一转换为 UTC.
+	TimeZone string
+
+	// Lint lint选项, 为 nil 时不执行lint检查.
+	// lint 在解析成功后执行, 警告记录在 Parser.Warnings 中.
+	Lint *LintOptions
+
+	fsys          fileSystem
+	location      *time.Location
 	tagMap        map[gexcels.Tag]bool
 	enumFileMap   map[string]bool
 	structFileMap map[string]bool
+	lintDisabled  map[string]bool
+	lintErrors    map[string]bool
 }
 
-func (opt *Options) init() error {
+func (opt *Options) init(fsys fileSystem) error {
+	opt.fsys = fsys
+	opt.enumFileMap = nil
 	if len(opt.EnumFiles) > 0 {
 		opt.enumFileMap = make(map[string]bool, len(opt.EnumFiles))
 		for _, file := range opt.EnumFiles {
-			abs, _ := filepath.Abs(file)
-			opt.enumFileMap[abs] = true
+			opt.enumFileMap[fsys.normalize(file)] = true
 		}
 	}
+	opt.structFileMap = nil
 	if len(opt.StructFiles) > 0 {
 		opt.structFileMap = make(map[string]bool, len(opt.StructFiles))
 		for _, file := range opt.StructFiles {
-			abs, _ := filepath.Abs(file)
-			opt.structFileMap[abs] = true
+			opt.structFileMap[fsys.normalize(file)] = true
 		}
 	}
 
@@ -74,6 +100,20 @@ func (opt *Options) init() error {
 		opt.FieldRuleSep = "|"
 	}
 
+	location, err := time.LoadLocation(opt.TimeZone)
+	if err != nil {
+		return pkg_errors.WithMessagef(err, "parse: time zone %s invalid", opt.TimeZone)
+	}
+	opt.location = location
+
+	opt.lintDisabled, opt.lintErrors = nil, nil
+	if opt.Lint != nil {
+		var err error
+		if opt.lintDisabled, opt.lintErrors, err = opt.Lint.init(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -82,8 +122,7 @@ func (opt *Options) isEnumFile(path string) bool {
 	if opt.enumFileMap == nil {
 		return false
 	}
-	abs, _ := filepath.Abs(path)
-	return opt.enumFileMap[abs]
+	return opt.enumFileMap[opt.fsys.normalize(path)]
 }
 
 // isStructFile 是否为结构体文件
@@ -91,8 +130,7 @@ func (opt *Options) isStructFile(path string) bool {
 	if opt.structFileMap == nil {
 		return false
 	}
-	abs, _ := filepath.Abs(path)
-	return opt.structFileMap[abs]
+	return opt.structFileMap[opt.fsys.normalize(path)]
 }
 
 // checkTag 检查tag
@@ -111,6 +149,14 @@ func (opt *Options) checkTag(tags []string) bool {
 	return false
 }
 
+// getConcurrency 获取并发解析数量
+func (opt *Options) getConcurrency() int {
+	if opt.Concurrency > 0 {
+		return opt.Concurrency
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
 func defaultOptions() *Options {
 	return &Options{}
 }
@@ -122,7 +168,33 @@ func Parse(path string, options ...*Options) (*Parser, error) {
 	if path == "" {
 		return nil, ErrNoPathSpecified
 	}
+	return parseFileSystem(osFileSystem{}, path, options...)
+}
+
+// ParseFS 解析 fsys 中 root 目录下的配置表.
+// Options.EnumFiles/StructFiles 为相对于 fsys 根目录的路径.
+func ParseFS(fsys fs.FS, root string, options ...*Options) (*Parser, error) {
+	if fsys == nil {
+		return nil, ErrNoFSSpecified
+	}
+	if root == "" {
+		root = "."
+	}
+	return parseFileSystem(ioFileSystem{fsys: fsys}, normalizeFSPath(root), options...)
+}
+
+// ParseSources 解析以 io.Reader 提供的数据源.
+// Options.EnumFiles/StructFiles 与 Source.Name 进行匹配.
+func ParseSources(sources []Source, options ...*Options) (*Parser, error) {
+	fsys, err := newMemFileSystem(sources)
+	if err != nil {
+		return nil, pkg_errors.WithMessage(err, "parse")
+	}
+	return parseFileSystem(fsys, ".", options...)
+}
 
+// parseFileSystem 解析文件系统 fsys 中 root 目录下的配置表
+func parseFileSystem(fsys fileSystem, root string, options ...*Options) (*Parser, error) {
 	var opts *Options
 	if len(options) > 0 {
 		opts = options[0]
@@ -130,13 +202,13 @@ func Parse(path string, options ...*Options) (*Parser, error) {
 	if opts == nil {
 		opts = defaultOptions()
 	}
-	if err := opts.init(); err != nil {
+	if err := opts.init(fsys); err != nil {
 		return nil, err
 	}
 
-	log.Printf("parse file inside [%s] with tag(%v)", path, opts.Tags)
+	log.Printf("parse file inside [%s] with tag(%v)", root, opts.Tags)
 
-	p := newParser(path, opts)
+	p := newParser(fsys, root, opts)
 	if err := p.parse(); err != nil {
 		return nil, err
 	}
@@ -146,28 +218,39 @@ func Parse(path string, options ...*Options) (*Parser, error) {
 
 // Parser excel配置表解析器
 type Parser struct {
+	fsys             fileSystem                        // 文件系统
 	path             string                            // 配置路径
 	options          *Options                          // 选项
+	cache            *parseCache                       // 增量解析缓存
 	Structs          []*Struct                         // 解析出的结构体
 	structByName     map[string]*Struct                // 结构体名称映射
+	Unions           []*Union                          // 解析出的联合类型
+	unionByName      map[string]*Union                 // 联合类型名称映射
 	Tables           []*Table                          // 解析出的配置表
 	tableByName      map[string]*Table                 // 配置表名称映射
 	Enums            []*Enum                           // 枚举列表
 	enumByName       map[string]*Enum                  // 枚举名称映射
 	customFieldTypes map[string]*gexcels.FieldTypeInfo // 自定义字段类型
+	Warnings         []*Diagnostic                     // lint产生的警告
+	Texts            []*Text                           // 从文本字段提取的文本
+	textByKey        map[string]*Text                  // 文本key映射
 }
 
-func newParser(path string, options *Options) *Parser {
+func newParser(fsys fileSystem, path string, options *Options) *Parser {
 	p := &Parser{
+		fsys:             fsys,
 		path:             path,
 		options:          options,
 		Structs:          make([]*Struct, 0),
 		structByName:     make(map[string]*Struct),
+		Unions:           make([]*Union, 0),
+		unionByName:      make(map[string]*Union),
 		Tables:           make([]*Table, 0),
 		tableByName:      make(map[string]*Table),
 		Enums:            make([]*Enum, 0),
 		enumByName:       make(map[string]*Enum),
 		customFieldTypes: make(map[string]*gexcels.FieldTypeInfo),
+		textByKey:        make(map[string]*Text),
 	}
 	return p
 }
@@ -182,17 +265,27 @@ func (p *Parser) checkTag(tag string) (bool, bool) {
 }
 
 func (p *Parser) parse() error {
+	cache, err := newParseCache(p.options.CacheDir, p.fsys, p.options)
+	if err != nil {
+		return pkg_errors.WithMessage(err, "parse")
+	}
+	p.cache = cache
+
 	result := p.searchFiles()
 	if result.err != nil {
 		return pkg_errors.WithMessage(result.err, "parse")
 	}
 
+	var errs Errors
+
 	enumFiles := p.options.EnumFiles
 	if len(enumFiles) == 0 {
 		enumFiles = result.enumFiles
 	}
 	if err := p.parseEnums(enumFiles); err != nil {
-		return pkg_errors.WithMessage(err, "parse")
+		if err := p.collectError(&errs, withMessage(err, "parse")); err != nil {
+			return err
+		}
 	}
 
 	structFiles := p.options.StructFiles
@@ -200,21 +293,45 @@ func (p *Parser) parse() error {
 		structFiles = result.structFiles
 	}
 	if err := p.parseStructs(structFiles); err != nil {
-		return pkg_errors.WithMessage(err, "parse")
+		if err := p.collectError(&errs, withMessage(err, "parse")); err != nil {
+			return err
+		}
 	}
 
+	p.cache.sealDeps()
 	if err := p.parseTables(result.tableFiles); err != nil {
-		return pkg_errors.WithMessage(err, "parse")
+		if err := p.collectError(&errs, withMessage(err, "parse")); err != nil {
+			return err
+		}
+	}
+
+	if linkErrs := p.checkLinksBetweenTable(); linkErrs != nil {
+		if !p.options.CollectErrors {
+			for _, err := range linkErrs {
+				log.Errorln(err)
+			}
+			return &linkErrors{errs: linkErrs}
+		}
+		for _, err := range linkErrs {
+			errs = appendError(errs, withMessage(err, "parse"))
+		}
+	}
+
+	// 存在错误时数据不完整, 不再执行lint
+	if p.options.Lint != nil && len(errs) == 0 {
+		if err := p.lint(); err != nil {
+			return err
+		}
 	}
 
-	if errs := p.checkLinksBetweenTable(); errs != nil {
-		for _, err := range errs {
-			log.Errorln(err)
+	// 文本提取会将文本字段的值替换为key, 需在所有检查之后执行
+	if len(errs) == 0 {
+		if err := p.extractTexts(); err != nil {
+			return withMessage(err, "parse")
 		}
-		return ErrLinkErrorsFound
 	}
 
-	return nil
+	return errs.err()
 }
 
 // priorityFileInfo 枚举文件信息
@@ -242,19 +359,11 @@ func (p *Parser) searchFiles() (result struct {
 		searchStruct = len(p.options.StructFiles) == 0
 	)
 
-	if err := filepath.Walk(p.path, func(path string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if info.IsDir() {
-			return nil
-		}
-
+	if err := p.fsys.walk(p.path, func(path string) error {
 		fileName := filepath.Base(path)
 		ext := filepath.Ext(fileName)
 
-		if ext != ".xlsx" {
+		if getWorkbookDecoder(ext) == nil {
 			return nil
 		}
 
@@ -265,11 +374,16 @@ func (p *Parser) searchFiles() (result struct {
 		fileName = strings.TrimSuffix(fileName, ext)
 
 		// 匹配枚举文件
+		// 单 sheet 数据源(csv/tsv)的文件名即 sheet 名, 同样按 sheet 名匹配.
 		if searchEnum {
 			if matches := enumFileNameRegexp.FindStringSubmatch(fileName); len(matches) > 0 {
 				result.enumFiles = append(result.enumFiles, path)
 				return nil
 			}
+			if matches := enumSheetNameRegexp.FindStringSubmatch(fileName); len(matches) > 0 {
+				result.enumFiles = append(result.enumFiles, path)
+				return nil
+			}
 		}
 
 		// 匹配结构体文件
@@ -282,6 +396,14 @@ func (p *Parser) searchFiles() (result struct {
 				})
 				return nil
 			}
+			if matches := structSheetNameRegexp.FindStringSubmatch(fileName); len(matches) > 0 {
+				priority, _ := strconv.Atoi(matches[3])
+				structFiles = append(structFiles, priorityFileInfo{
+					path:     path,
+					priority: priority,
+				})
+				return nil
+			}
 		}
 
 		// 匹配配置表文件
@@ -308,21 +430,21 @@ func (p *Parser) searchFiles() (result struct {
 
 // prioritySheetInfo 结构体sheet信息
 type prioritySheetInfo struct {
-	*xlsx.Sheet     // sheet
-	priority    int // 优先级
+	Sheet        // sheet
+	priority int // 优先级
 }
 
 // 获取sheet中的value
-func getSheetValue(sheet *xlsx.Sheet, row, col int, trim ...bool) (string, error) {
-	cell, err := sheet.Cell(row, col)
+func getSheetValue(sheet Sheet, row, col int, trim ...bool) (string, error) {
+	value, err := sheet.Cell(row, col)
 	if err != nil {
 		return "", err
 	}
 
 	if len(trim) > 0 && trim[0] {
-		return strings.TrimSpace(cell.Value), nil
+		return strings.TrimSpace(value), nil
 	} else {
-		return cell.Value, nil
+		return value, nil
 	}
 }
 
@@ -330,14 +452,14 @@ func getSheetValue(sheet *xlsx.Sheet, row, col int, trim ...bool) (string, error
 var commentRegexp = regexp.MustCompile(`^#[\s\S]*$`)
 
 // 是否注释行
-func isRowComment(row *xlsx.Row) bool {
-	value := row.GetCell(0).Value
+func isRowComment(row sheetRow) bool {
+	value := row.value(0)
 	return isValueComment(value)
 }
 
 // isSheetRowComment 是否是注释行
-func isSheetRowComment(sheet *xlsx.Sheet, row int) bool {
-	if row > sheet.MaxRow || sheet.MaxCol < 1 {
+func isSheetRowComment(sheet Sheet, row int) bool {
+	if row > sheet.MaxRow() || sheet.MaxCol() < 1 {
 		return false
 	}
 	cell, _ := getSheetValue(sheet, row, 0, true)
@@ -1,8 +1,16 @@
 package parse
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/godyy/gexcels"
 	"github.com/tealeg/xlsx/v3"
@@ -453,3 +461,1747 @@ func TestRegexp(t *testing.T) {
 	t.Log(structSheetNameRegexp.FindStringSubmatch("|StructCommon"))
 	t.Log(structSheetNameRegexp.FindStringSubmatch("|StructCommon.0"))
 }
+
+// csvTestFiles 测试用 csv/tsv 数据源
+var csvTestFiles = map[string]string{
+	"枚举|Enum.tsv": "\n" +
+		"\tColor_BEGIN\tint32\tcolor\n" +
+		"\tRed\t1\tred\n" +
+		"\tBlue\t2\tblue\n",
+	"结构体|Struct.csv": "\n" +
+		`,Point,"X:int32:""x"",Y:int32:""y""",,point` + "\n",
+	"道具|Item.csv": "\xEF\xBB\xBF" +
+		"ID,Name,Color,Pos\n" +
+		"id,name,color,pos\n" +
+		"int32,string,Color,Point\n" +
+		",,,\n" +
+		",,,\n" +
+		`1,Sword,Red,"{""X"":1,""Y"":2}"` + "\n" +
+		"#2,Comment,Blue,\n" +
+		"3,Shield,Blue,\n",
+	"全局|GlobalConst.csv": "\n" +
+		",MaxLevel,int32,100,,max level\n",
+}
+
+// checkCSVTestParser 检查 csvTestFiles 的解析结果
+func checkCSVTestParser(t *testing.T, p *Parser) {
+	t.Helper()
+
+	if p.GetEnum("Color") == nil {
+		t.Fatalf("enum Color not found")
+	}
+	if p.GetStructByName("Point") == nil {
+		t.Fatalf("struct Point not found")
+	}
+
+	td := p.GetTableByName("Item")
+	if td == nil {
+		t.Fatalf("table Item not found")
+	}
+	if len(td.Entries) != 2 {
+		t.Fatalf("table Item entry amount invalid: %d", len(td.Entries))
+	}
+	if td.Entries[0]["Name"] != "Sword" || td.Entries[0]["Color"] != int32(1) {
+		t.Fatalf("table Item entry[0] invalid: %+v", td.Entries[0])
+	}
+	pos, ok := td.Entries[0]["Pos"].(map[string]any)
+	if !ok || pos["Y"] != int32(2) {
+		t.Fatalf("table Item entry[0].Pos invalid: %+v", td.Entries[0]["Pos"])
+	}
+
+	gtd := p.GetTableByName("GlobalConst")
+	if gtd == nil || !gtd.IsGlobal {
+		t.Fatalf("global table GlobalConst not found")
+	}
+	if gtd.GetEntryByName("MaxLevel") != int32(100) {
+		t.Fatalf("global table GlobalConst.MaxLevel invalid: %v", gtd.GetEntryByName("MaxLevel"))
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range csvTestFiles {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := Parse(dir, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCSVTestParser(t, p)
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, content := range csvTestFiles {
+		fsys["configs/"+name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	// 指定的枚举文件位于 root 之外, 相对于 fsys 根目录
+	fsys["defs/颜色|EnumColor.tsv"] = fsys["configs/枚举|Enum.tsv"]
+	delete(fsys, "configs/枚举|Enum.tsv")
+
+	p, err := ParseFS(fsys, "configs", &Options{EnumFiles: []string{"defs/颜色|EnumColor.tsv"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCSVTestParser(t, p)
+}
+
+func TestParseSources(t *testing.T) {
+	sources := make([]Source, 0, len(csvTestFiles))
+	for name, content := range csvTestFiles {
+		sources = append(sources, Source{Name: name, Reader: strings.NewReader(content)})
+	}
+
+	p, err := ParseSources(sources, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCSVTestParser(t, p)
+
+	sources = append(sources, Source{Name: "道具|Item.csv", Reader: strings.NewReader("")})
+	if _, err := ParseSources(sources, &Options{}); err == nil {
+		t.Fatalf("duplicate source name not detected")
+	}
+}
+
+func TestParseConcurrency(t *testing.T) {
+	newSources := func(dupIndex int) []Source {
+		sources := make([]Source, 0, 64)
+		for i := 0; i < 64; i++ {
+			name := fmt.Sprintf("T%02d", i)
+			if i == dupIndex {
+				name = "T00"
+			}
+			content := "ID,Value\nid,value\nint32,int32\n,\n,\n1,10\n2,20\n"
+			sources = append(sources, Source{
+				Name:   fmt.Sprintf("%02d|%s.csv", i, name),
+				Reader: strings.NewReader(content),
+			})
+		}
+		return sources
+	}
+
+	for _, concurrency := range []int{1, 8} {
+		p, err := ParseSources(newSources(-1), &Options{Concurrency: concurrency})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.Tables) != 64 {
+			t.Fatalf("concurrency %d: table amount invalid: %d", concurrency, len(p.Tables))
+		}
+		for i, td := range p.Tables {
+			if td.Name != fmt.Sprintf("T%02d", i) {
+				t.Fatalf("concurrency %d: table[%d] %s order invalid", concurrency, i, td.Name)
+			}
+		}
+
+		_, err = ParseSources(newSources(40), &Options{Concurrency: concurrency})
+		if err == nil || !strings.Contains(err.Error(), "[40|T00.csv][40|T00]: table name duplicate") {
+			t.Fatalf("concurrency %d: duplicate error invalid: %v", concurrency, err)
+		}
+	}
+}
+
+func TestParseCache(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	// 缓存命中时的解析结果需与完整解析一致
+	options := &Options{Tags: []gexcels.Tag{"s"}, CacheDir: cacheDir}
+	p1, err := Parse("../internal/test/excels", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := Parse("../internal/test/excels", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p1.Tables) != len(p2.Tables) {
+		t.Fatalf("cached table amount %d != %d", len(p2.Tables), len(p1.Tables))
+	}
+	for i, td1 := range p1.Tables {
+		td2 := p2.Tables[i]
+		if td1.Name != td2.Name || len(td1.Fields) != len(td2.Fields) {
+			t.Fatalf("cached table[%d] %s fields mismatch", i, td2.Name)
+		}
+		if !reflect.DeepEqual(td1.Entries, td2.Entries) || !reflect.DeepEqual(td1.entryByName, td2.entryByName) {
+			t.Fatalf("cached table %s entries mismatch", td2.Name)
+		}
+		if !reflect.DeepEqual(td1.uniqueValues, td2.uniqueValues) {
+			t.Fatalf("cached table %s unique values mismatch", td2.Name)
+		}
+	}
+
+	// 数据源或其依赖的枚举变化时缓存失效
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range csvTestFiles {
+		writeFile(name, content)
+	}
+	options = &Options{CacheDir: cacheDir}
+	for i := 0; i < 2; i++ {
+		p, err := Parse(dir, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCSVTestParser(t, p)
+	}
+
+	writeFile("枚举|Enum.tsv", strings.Replace(csvTestFiles["枚举|Enum.tsv"], "\tRed\t1\t", "\tRed\t5\t", 1))
+	p, err := Parse(dir, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := p.GetTableByName("Item").Entries[0]["Color"]; v != int32(5) {
+		t.Fatalf("enum changed, but cached table not invalidated: Color=%v", v)
+	}
+
+	writeFile("道具|Item.csv", strings.Replace(csvTestFiles["道具|Item.csv"], "Sword", "Blade", 1))
+	p, err = Parse(dir, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := p.GetTableByName("Item").Entries[0]["Name"]; v != "Blade" {
+		t.Fatalf("table changed, but cache not invalidated: Name=%v", v)
+	}
+}
+
+// testAssets 测试用资源集合, 模拟配置内容以外的状态
+var testAssets = map[string]bool{}
+
+// testFRAsset 测试用条目校验规则, 字段值需为存在的资源.
+type testFRAsset struct{}
+
+func (r *testFRAsset) FRName() string { return "ASSET" }
+
+func (r *testFRAsset) FRKey() string { return "ASSET" }
+
+func (r *testFRAsset) ParseValue(value string) error { return nil }
+
+func (r *testFRAsset) String() string { return "ASSET" }
+
+func (r *testFRAsset) ValidateEntry(field *gexcels.Field, entry gexcels.TableEntry) error {
+	if v, _ := entry[field.Name].(string); !testAssets[v] {
+		return fmt.Errorf("asset %s missing", v)
+	}
+	return nil
+}
+
+var registerTestAssetRule sync.Once
+
+func TestParseCacheCustomRule(t *testing.T) {
+	registerTestAssetRule.Do(func() {
+		gexcels.RegisterFieldRule("ASSET", func() gexcels.FieldRule { return &testFRAsset{} })
+	})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a|A.csv"), []byte(""+
+		"ID,P\n"+
+		"id,p\n"+
+		"int32,string\n"+
+		",ASSET\n"+
+		",\n"+
+		"1,a.png\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 缓存命中时仍需调用自定义规则的条目校验
+	options := &Options{CacheDir: t.TempDir()}
+	testAssets["a.png"] = true
+	if _, err := Parse(dir, options); err != nil {
+		t.Fatal(err)
+	}
+	delete(testAssets, "a.png")
+	_, err := Parse(dir, options)
+	if err == nil || !strings.Contains(err.Error(), "row[6] P ASSET: asset a.png missing") {
+		t.Fatalf("cached table not revalidated: %v", err)
+	}
+	testAssets["a.png"] = true
+	if _, err := Parse(dir, options); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newCollectErrorSources 包含多处错误的数据源
+func newCollectErrorSources() []Source {
+	return []Source{
+		{Name: "a|A.csv", Reader: strings.NewReader("" +
+			"ID,Num,Bad\n" +
+			"id,num,bad\n" +
+			"int32,int32,unknown\n" +
+			",,\n" +
+			",,\n" +
+			"1,1,\n" +
+			"2,y,\n" +
+			"3,x,\n" +
+			"1,3,\n")},
+		{Name: "b|B.csv", Reader: strings.NewReader("" +
+			"ID,Ref\n" +
+			"id,ref\n" +
+			"int32,int32\n" +
+			",LINK=A.ID\n" +
+			",\n" +
+			"1,1\n" +
+			"2,99\n")},
+	}
+}
+
+func TestParseCollectErrors(t *testing.T) {
+	_, err := ParseSources(newCollectErrorSources(), &Options{})
+	if err == nil {
+		t.Fatalf("error not found")
+	}
+	var errs Errors
+	if errors.As(err, &errs) {
+		t.Fatalf("errors collected without CollectErrors: %v", err)
+	}
+
+	p, err := ParseSources(newCollectErrorSources(), &Options{CollectErrors: true})
+	if p != nil {
+		t.Fatalf("parser returned with errors")
+	}
+	if !errors.As(err, &errs) {
+		t.Fatalf("errors not collected: %v", err)
+	}
+	expected := []string{
+		"coll[2]: field type (unknown)",
+		"row[7] Num={y}",
+		"row[8] Num={x}",
+		"row[9] ID=1 duplicate",
+		"link [B.Ref -> A.ID]",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("collected %d errors, expected %d:\n%v", len(errs), len(expected), errs)
+	}
+	for i, s := range expected {
+		if !strings.Contains(errs[i].Error(), s) {
+			t.Fatalf("error[%d] %q not contains %q", i, errs[i].Error(), s)
+		}
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	_, err := ParseSources(newCollectErrorSources(), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []Diagnostic{
+		{Path: "a|A.csv", Sheet: "a|A", Row: 3, Col: 3, Table: "A", Field: "Bad"},
+		{Path: "a|A.csv", Sheet: "a|A", Row: 7, Col: 2, Table: "A", Field: "Num"},
+		{Path: "a|A.csv", Sheet: "a|A", Row: 8, Col: 2, Table: "A", Field: "Num"},
+		{Path: "a|A.csv", Sheet: "a|A", Row: 9, Col: 1, Table: "A", Field: "ID", Rule: gexcels.FRNUnique},
+		{Path: "b|B.csv", Sheet: "b|B", Row: 7, Col: 2, Table: "B", Field: "Ref", Rule: gexcels.FRNLink},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, diag := range diags {
+		if diag.Severity != SeverityError || diag.Message == "" {
+			t.Fatalf("diagnostic[%d] %s severity or message invalid", i, diag)
+		}
+		expected[i].Message = diag.Message
+		if *diag != expected[i] {
+			t.Fatalf("diagnostic[%d] %+v, expected %+v", i, *diag, expected[i])
+		}
+	}
+	if cell := diags[1].Cell(); cell != "B7" {
+		t.Fatalf("diagnostic cell %s, expected B7", cell)
+	}
+
+	// 未开启 CollectErrors 时, link 错误同样携带位置信息
+	_, err = ParseSources([]Source{
+		{Name: "a|A.csv", Reader: strings.NewReader("ID\nid\nint32\n\n\n1\n")},
+		{Name: "b|B.csv", Reader: strings.NewReader("ID,Ref\nid,ref\nint32,int32\n,LINK=A.ID\n,\n1,1\n2,99\n")},
+	}, &Options{})
+	if !errors.Is(err, ErrLinkErrorsFound) {
+		t.Fatalf("link errors not found: %v", err)
+	}
+	diags = Diagnostics(err)
+	if len(diags) != 1 || diags[0].Path != "b|B.csv" || diags[0].Cell() != "B7" || diags[0].Rule != gexcels.FRNLink {
+		t.Fatalf("link diagnostics invalid: %v", diags)
+	}
+}
+
+func TestParseLint(t *testing.T) {
+	newSources := func() []Source {
+		return []Source{
+			{Name: "枚举|EnumColor.tsv", Reader: strings.NewReader("" +
+				"\n" +
+				"\tColor_BEGIN\tint32\tcolor\n" +
+				"\tRed\t1\tred\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader("" +
+				"ID,Name,Weight,Alias\n" +
+				"id,name,weight,name\n" +
+				"int32,string,float32,string\n" +
+				",,,\n" +
+				",,,\n" +
+				"1,sword ,16777217,\n" +
+				"2,shield,1.5,\n")},
+			{Name: "b|B.csv", Reader: strings.NewReader("" +
+				"ID,Ref\n" +
+				"id,ref\n" +
+				"int32,int32\n" +
+				",LINK=A.ID\n" +
+				",\n" +
+				"1,1\n")},
+		}
+	}
+
+	p, err := ParseSources(newSources(), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Warnings) != 0 {
+		t.Fatalf("warnings without lint: %v", p.Warnings)
+	}
+
+	p, err = ParseSources(newSources(), &Options{Lint: &LintOptions{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		rule, path, cell string
+	}{
+		{LintTrailingSpace, "a|A.csv", "B6"},
+		{LintFloat32Precision, "a|A.csv", "C6"},
+		{LintUnusedEnum, "枚举|EnumColor.tsv", "B2"},
+		{LintUnlinkedTable, "b|B.csv", ""},
+		{LintDuplicateDesc, "a|A.csv", "D2"},
+	}
+	if len(p.Warnings) != len(expected) {
+		t.Fatalf("warnings %d, expected %d: %v", len(p.Warnings), len(expected), p.Warnings)
+	}
+	for i, e := range expected {
+		w := p.Warnings[i]
+		if w.Severity != SeverityWarning || w.Rule != e.rule || w.Path != e.path || w.Cell() != e.cell {
+			t.Fatalf("warning[%d] %s, expected %s %s %s", i, w, e.rule, e.path, e.cell)
+		}
+	}
+
+	p, err = ParseSources(newSources(), &Options{Lint: &LintOptions{
+		Disable: []string{LintUnusedEnum, LintUnlinkedTable, LintDuplicateDesc},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Warnings) != 2 {
+		t.Fatalf("warnings %d with disabled lints, expected 2: %v", len(p.Warnings), p.Warnings)
+	}
+
+	_, err = ParseSources(newSources(), &Options{
+		CollectErrors: true,
+		Lint:          &LintOptions{Disable: []string{LintAll}, Errors: []string{LintAll}},
+	})
+	if err != nil {
+		t.Fatalf("disabled lints promoted: %v", err)
+	}
+
+	_, err = ParseSources(newSources(), &Options{
+		CollectErrors: true,
+		Lint:          &LintOptions{Errors: []string{LintTrailingSpace, LintFloat32Precision}},
+	})
+	diags := Diagnostics(err)
+	if len(diags) != 2 || diags[0].Severity != SeverityError || diags[0].Rule != LintTrailingSpace || diags[1].Rule != LintFloat32Precision {
+		t.Fatalf("promoted lint errors invalid: %v", err)
+	}
+
+	if _, err = ParseSources(newSources(), &Options{Lint: &LintOptions{Disable: []string{"unknown"}}}); err == nil {
+		t.Fatalf("invalid lint name accepted")
+	}
+}
+
+func TestParseRangeRule(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Point,"X:int32:""x"",Y:int32:""y""","RANGE=X,0,10",point` + "\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+
+	_, err := ParseSources(newSources(""+
+		"ID,Rate,Levels,Scores,Pos\n"+
+		"id,rate,levels,scores,pos\n"+
+		"int32,float32,[]int32,map[string]int32,Point\n"+
+		`,"RANGE=[0,1)","RANGE=1,200","RANGE=(0,",`+"\n"+
+		",,,,\n"+
+		`1,0.5,"[1,200]","{""a"":1}","{""X"":10}"`+"\n"+
+		"2,1,,,\n"+
+		`3,0,"[1,0]",,`+"\n"+
+		`4,0,,"{""a"":0}",`+"\n"+
+		`5,0,,,"{""X"":11}"`+"\n",
+	), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []struct {
+		cell, message string
+	}{
+		{"B7", "Rate=1 out of range [0,1)"},
+		{"C8", "Levels[1]=0 out of range [1,200]"},
+		{"D9", "Scores[a]=0 out of range (0,]"},
+		{"E10", "Pos.X=11 out of range [0,10]"},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Rule != gexcels.FRNRange || d.Cell() != e.cell || !strings.Contains(d.Message, e.message) {
+			t.Fatalf("diagnostic[%d] %s, expected %s %s", i, d, e.cell, e.message)
+		}
+	}
+
+	_, err = ParseSources(newSources(""+
+		"ID,Name\n"+
+		"id,name\n"+
+		"int32,string\n"+
+		`,"RANGE=0,1"`+"\n"+
+		",\n"), &Options{})
+	if err == nil || !strings.Contains(err.Error(), "non-numeric") {
+		t.Fatalf("RANGE on string field: %v", err)
+	}
+
+	// float32 值按 float32 精度与边界比较
+	for _, test := range []struct {
+		rule, value string
+		valid       bool
+	}{
+		{"RANGE=0,0.1", "0.1", true},
+		{"RANGE=0.1,1", "0.1", true},
+		{"RANGE=[0,0.1)", "0.1", false},
+		{"RANGE=(0.1,1]", "0.1", false},
+		{"RANGE=[0,0.1)", "0.09", true},
+	} {
+		for _, typ := range []string{"float32", "[]float32"} {
+			value := test.value
+			if typ == "[]float32" {
+				value = `"[` + value + `]"`
+			}
+			_, err = ParseSources(newSources(""+
+				"ID,Rate\n"+
+				"id,rate\n"+
+				"int32,"+typ+"\n"+
+				`,"`+test.rule+`"`+"\n"+
+				",\n"+
+				"1,"+value+"\n"), &Options{})
+			if (err == nil) != test.valid {
+				t.Fatalf("%s %s %s, valid %v: %v", typ, test.rule, test.value, test.valid, err)
+			}
+		}
+	}
+}
+
+func TestParseStringRules(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Named,"Name:string:""name""","NOTEMPTY=Name|LEN=Name,1,4",named` + "\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+
+	_, err := ParseSources(newSources(""+
+		"ID,Key,Tags,Names,Obj\n"+
+		"id,key,tags,names,obj\n"+
+		"int32,string,[]string,map[int32]string,Named\n"+
+		`,REGEX=^[a-z_]+$,"LEN=,3",NOTEMPTY,`+"\n"+
+		",,,,\n"+
+		`1,item_a,"[""ab""]","{""1"":""x""}","{""Name"":""abc""}"`+"\n"+
+		"2,Item,,,\n"+
+		`3,b,"[""abcd""]",,`+"\n"+
+		`4,c,,"{""1"":""x"",""2"":""""}",`+"\n"+
+		`5,d,,,"{}"`+"\n"+
+		`6,e,,,"{""Name"":""abcde""}"`+"\n",
+	), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []struct {
+		rule, cell, message string
+	}{
+		{gexcels.FRNRegex, "B7", "Key={Item} not match ^[a-z_]+$"},
+		{gexcels.FRNLen, "C8", "Tags[0]={abcd} length 4 out of range [0,3]"},
+		{gexcels.FRNNotEmpty, "D9", "Names[2]={} gexcels: field-rule notempty value empty"},
+		{gexcels.FRNNotEmpty, "E10", "Obj.Name={} gexcels: field-rule notempty value empty"},
+		{gexcels.FRNLen, "E11", "Obj.Name={abcde} length 5 out of range [1,4]"},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Rule != e.rule || d.Cell() != e.cell || !strings.Contains(d.Message, e.message) {
+			t.Fatalf("diagnostic[%d] %s, expected %s %s %s", i, d, e.rule, e.cell, e.message)
+		}
+	}
+
+	_, err = ParseSources(newSources(""+
+		"ID,Num\n"+
+		"id,num\n"+
+		"int32,[]int32\n"+
+		",NOTEMPTY\n"+
+		",\n"), &Options{})
+	if err == nil || !strings.Contains(err.Error(), "non-string") {
+		t.Fatalf("NOTEMPTY on int32 field: %v", err)
+	}
+}
+
+// testFRLessEqual 测试用条目校验规则, 字段值不能大于同条目中指定字段的值.
+// .e.g: LE=Max
+type testFRLessEqual struct{ field string }
+
+func (r *testFRLessEqual) FRName() string { return "LE" }
+
+func (r *testFRLessEqual) FRKey() string { return "LE" }
+
+func (r *testFRLessEqual) ParseValue(value string) error {
+	r.field = value
+	return nil
+}
+
+func (r *testFRLessEqual) String() string { return "LE=" + r.field }
+
+func (r *testFRLessEqual) ValidateField(field *gexcels.Field) error {
+	if field.Type != gexcels.FTInt32 {
+		return fmt.Errorf("LE on non-int32 field %s", field.Name)
+	}
+	return nil
+}
+
+func (r *testFRLessEqual) ValidateEntry(field *gexcels.Field, entry gexcels.TableEntry) error {
+	v, _ := entry[field.Name].(int32)
+	max, _ := entry[r.field].(int32)
+	if v > max {
+		return fmt.Errorf("%d greater than %s %d", v, r.field, max)
+	}
+	return nil
+}
+
+// testFRSorted 测试用配置表校验规则, 字段值需按条目顺序递增.
+type testFRSorted struct{}
+
+func (r *testFRSorted) FRName() string { return "SORTED" }
+
+func (r *testFRSorted) FRKey() string { return "SORTED" }
+
+func (r *testFRSorted) ParseValue(value string) error { return nil }
+
+func (r *testFRSorted) String() string { return "SORTED" }
+
+func (r *testFRSorted) ValidateTable(table *gexcels.Table, field *gexcels.Field, entries []gexcels.TableEntry) error {
+	for i := 1; i < len(entries); i++ {
+		prev, _ := entries[i-1][field.Name].(int32)
+		cur, _ := entries[i][field.Name].(int32)
+		if cur <= prev {
+			return fmt.Errorf("%s entry[%d] %d not greater than %d", table.Name, i, cur, prev)
+		}
+	}
+	return nil
+}
+
+// testFRPrefix 测试用值校验规则, 字符串值需具备指定前缀.
+// .e.g: PREFIX=icon_
+type testFRPrefix struct{ prefix string }
+
+func (r *testFRPrefix) FRName() string { return "PREFIX" }
+
+func (r *testFRPrefix) FRKey() string { return "PREFIX" }
+
+func (r *testFRPrefix) ParseValue(value string) error {
+	r.prefix = value
+	return nil
+}
+
+func (r *testFRPrefix) String() string { return "PREFIX=" + r.prefix }
+
+func (r *testFRPrefix) ValidateField(field *gexcels.Field) error {
+	if field.LeafType().Type != gexcels.FTString {
+		return fmt.Errorf("PREFIX on non-string field %s", field.Name)
+	}
+	return nil
+}
+
+func (r *testFRPrefix) ValidateValue(value any) error {
+	if s, _ := value.(string); !strings.HasPrefix(s, r.prefix) {
+		return fmt.Errorf("without prefix %s", r.prefix)
+	}
+	return nil
+}
+
+var registerTestFieldRules sync.Once
+
+func TestParseCustomFieldRules(t *testing.T) {
+	registerTestFieldRules.Do(func() {
+		gexcels.RegisterFieldRule("LE", func() gexcels.FieldRule { return &testFRLessEqual{} })
+		gexcels.RegisterFieldRule("SORTED", func() gexcels.FieldRule { return &testFRSorted{} })
+		gexcels.RegisterFieldRule("PREFIX", func() gexcels.FieldRule { return &testFRPrefix{} })
+	})
+
+	_, err := ParseSources([]Source{
+		{Name: "a|A.csv", Reader: strings.NewReader("" +
+			"ID,Min,Max\n" +
+			"id,min,max\n" +
+			"int32,int32,int32\n" +
+			",LE=Max,\n" +
+			",,\n" +
+			"1,1,2\n" +
+			"2,3,2\n")},
+		{Name: "b|B.csv", Reader: strings.NewReader("" +
+			"ID,Order\n" +
+			"id,order\n" +
+			"int32,int32\n" +
+			",SORTED\n" +
+			",\n" +
+			"1,1\n" +
+			"2,2\n" +
+			"3,2\n")},
+		{Name: "全局|GlobalB.csv", Reader: strings.NewReader("" +
+			"\n" +
+			",Min,int32,5,LE=Max,min\n" +
+			",Max,int32,4,,max\n")},
+	}, &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []struct {
+		table, cell, rule, message string
+	}{
+		{"A", "B7", "LE", "3 greater than Max 2"},
+		{"B", "B4", "SORTED", "entry[2] 2 not greater than 2"},
+		{"GlobalB", "D2", "LE", "5 greater than Max 4"},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Table != e.table || d.Cell() != e.cell || d.Rule != e.rule || !strings.Contains(d.Message, e.message) {
+			t.Fatalf("diagnostic[%d] %s, expected %s %s %s %s", i, d, e.table, e.cell, e.rule, e.message)
+		}
+	}
+
+	_, err = ParseSources([]Source{{Name: "a|A.csv", Reader: strings.NewReader("" +
+		"ID,Name,Max\n" +
+		"id,name,max\n" +
+		"int32,string,int32\n" +
+		",LE=Max,\n" +
+		",,\n")}}, &Options{})
+	if err == nil || !strings.Contains(err.Error(), "LE on non-int32 field Name") {
+		t.Fatalf("LE on string field: %v", err)
+	}
+
+	// 自定义值校验规则作用于结构体字段
+	newStructSources := func(rule, value string) []Source {
+		return []Source{
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Skill,"Icon:string:""icon"",Level:int32:""level""","` + rule + `",skill` + "\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader("" +
+				"ID,Skill\n" +
+				"id,skill\n" +
+				"int32,Skill\n" +
+				",\n" +
+				",\n" +
+				value + "\n")},
+		}
+	}
+	if _, err = ParseSources(newStructSources("PREFIX=Icon,icon_", `1,"{Icon:icon_fire,Level:1}"`), &Options{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		rule, value, message string
+	}{
+		{"PREFIX=Icon,icon_", `1,"{Icon:fire,Level:1}"`, "Skill.Icon={fire} without prefix icon_"},
+		{"PREFIX=Level,icon_", `1,`, "PREFIX on non-string field Level"},
+		{"LE=Level,Icon", `1,`, "LE on field[Level] not value validator"},
+		{"UNIQUE=Level", `1,`, "field rule {UNIQUE} invalid"},
+	} {
+		_, err = ParseSources(newStructSources(test.rule, test.value), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.rule, test.value, err)
+		}
+	}
+}
+
+func TestParseCheckRule(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "枚举|EnumKind.tsv", Reader: strings.NewReader("" +
+				"\n" +
+				"\tKind_BEGIN\tint32\tkind\n" +
+				"\tNormal\t1\tnormal\n" +
+				"\tBoss\t2\tboss\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+			{Name: "全局|GlobalB.csv", Reader: strings.NewReader("" +
+				"\n" +
+				",MinCount,int32,5,CHECK=MinCount <= MaxCount,min\n" +
+				",MaxCount,int32,3,,max\n")},
+		}
+	}
+
+	_, err := ParseSources(newSources(""+
+		"ID,Kind,HP,MinLevel,MaxLevel,Rewards,Weights\n"+
+		"id,kind,hp,min,max,rewards,weights\n"+
+		"int32,Kind,int32,int32,int32,[]int32,[]int32\n"+
+		`,,"RANGE=0,|CHECK=if Kind == Boss then HP > 10000","CHECK=MinLevel <= MaxLevel",,"CHECK=len(Rewards) == len(Weights) || sum(Weights) == 0",`+"\n"+
+		",,,,,,\n"+
+		`1,Normal,100,1,10,"[1,2]","[5,5]"`+"\n"+
+		"2,Boss,500,1,10,,\n"+
+		"3,Normal,100,10,1,,\n"+
+		`4,Normal,100,1,1,[1],"[1,2]"`+"\n"+
+		`5,Boss,20000,1,1,[1],"[0,0]"`+"\n",
+	), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []struct {
+		table    string
+		row, col int
+		message  string
+	}{
+		{"A", 7, 0, "CHECK {if Kind == Boss then HP > 10000} failed"},
+		{"A", 8, 0, "CHECK {MinLevel <= MaxLevel} failed"},
+		{"A", 9, 0, "CHECK {len(Rewards) == len(Weights) || sum(Weights) == 0} failed"},
+		{"GlobalB", 2, gexcels.GlobalTableColFieldRule + 1, "CHECK {MinCount <= MaxCount} failed"},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Table != e.table || d.Row != e.row || d.Col != e.col || d.Rule != gexcels.FRNCheck || !strings.Contains(d.Message, e.message) {
+			t.Fatalf("diagnostic[%d] %s, expected %s row %d col %d %s", i, d, e.table, e.row, e.col, e.message)
+		}
+	}
+
+	// 引用被 tag 过滤的字段的检查被忽略
+	_, err = ParseSources(newSources(""+
+		"ID,Kind,HP\n"+
+		"id,kind,hp\n"+
+		"int32,Kind,int32\n"+
+		",CHECK=if Kind == Boss then HP > 10000,\n"+
+		",c,s\n"+
+		"1,Boss,1\n"), &Options{Tags: []gexcels.Tag{gexcels.TagEmpty, "c"}, CollectErrors: true})
+	if err == nil || !strings.Contains(err.Error(), "MinCount <= MaxCount") {
+		t.Fatalf("check with excluded field: %v", err)
+	}
+
+	for expr, message := range map[string]string{
+		"Level > 1":           "name Level not found",
+		"Type == Elite":       "enum Kind item Elite not found",
+		"Type = Boss":         "{=} at 6 invalid",
+		"(Type == Boss":       "expect {)}",
+		"Type.Boss == 1":      "{.Boss} on non-struct",
+		"count(Type) > 1":     "function {count} at 1 not found",
+		"Type == Kind.Normal": "",
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,Type\n"+
+			"id,type\n"+
+			"int32,Kind\n"+
+			",CHECK="+expr+"\n"+
+			",\n"), &Options{OnlyFields: true})
+		if message == "" {
+			if err != nil {
+				t.Fatalf("CHECK=%s: %v", expr, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatalf("CHECK=%s: %v, expected %s", expr, err, message)
+		}
+	}
+
+	// float32 字段与浮点数按 float32 精度比较
+	for expr, valid := range map[string]bool{
+		"Rate <= 0.1":          true,
+		"Rate == 0.1":          true,
+		"Rate >= 0.1":          true,
+		"0.1 == Rate":          true,
+		"Rate < 0.1":           false,
+		"Rate != 0.1":          false,
+		"Rate == Rate64":       true,
+		"Rate * 10 >= 1":       true,
+		"Rate == 0.1 && 1 < 2": true,
+	} {
+		_, err = ParseSources([]Source{{Name: "a|A.csv", Reader: strings.NewReader("" +
+			"ID,Rate,Rate64\n" +
+			"id,rate,rate64\n" +
+			"int32,float32,float64\n" +
+			",\"CHECK=" + expr + "\",\n" +
+			",,\n" +
+			"1,0.1,0.1\n")}}, &Options{})
+		if (err == nil) != valid {
+			t.Fatalf("CHECK=%s valid %v: %v", expr, valid, err)
+		}
+	}
+}
+
+func TestParseLinkFilter(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "枚举|EnumItemType.tsv", Reader: strings.NewReader("" +
+				"\n" +
+				"\tItemType_BEGIN\tint32\titem type\n" +
+				"\tWeapon\t1\tweapon\n" +
+				"\tArmor\t2\tarmor\n")},
+			{Name: "item|Item.csv", Reader: strings.NewReader("" +
+				"ID,Type,Name\n" +
+				"id,type,name\n" +
+				"int32,ItemType,string\n" +
+				",,UNIQUE\n" +
+				",,\n" +
+				"1,Weapon,sword\n" +
+				"2,Armor,shield\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+
+	_, err := ParseSources(newSources(""+
+		"ID,Weapon,ByName,Bag\n"+
+		"id,weapon,by name,bag\n"+
+		"int32,int32,string,map[int32]int32\n"+
+		",LINK=Item.ID[Type == Weapon],LINK=Item.Name[Type == Armor],LINK=k1:Item.ID[Type == ItemType.Weapon]\n"+
+		",,,\n"+
+		`1,1,shield,"{""1"":1}"`+"\n"+
+		"2,2,shield,\n"+
+		"3,1,sword,\n"+
+		"4,9,shield,\n"+
+		`5,1,shield,"{""2"":1}"`+"\n",
+	), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []struct {
+		cell, message string
+	}{
+		{"B7", "dst.ID=2 not match filter"},
+		{"B9", "dst.ID=9 not found"},
+		{"C8", "dst.Name=sword not match filter"},
+		{"D10", "dst.ID=2 not match filter"},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Rule != gexcels.FRNLink || d.Cell() != e.cell || !strings.Contains(d.Message, e.message) {
+			t.Fatalf("diagnostic[%d] %s, expected %s %s", i, d, e.cell, e.message)
+		}
+	}
+	if !strings.Contains(diags[0].Message, "Item.ID[Type == Weapon]") {
+		t.Fatalf("diagnostic %s without filter", diags[0])
+	}
+
+	_, err = ParseSources(newSources(""+
+		"ID,Weapon\n"+
+		"id,weapon\n"+
+		"int32,int32\n"+
+		",LINK=Item.ID[Color == 1]\n"+
+		",\n"+
+		"1,1\n"), &Options{})
+	if diags := Diagnostics(err); len(diags) != 1 || diags[0].Cell() != "B4" || !strings.Contains(diags[0].Message, "filter name Color not found") {
+		t.Fatalf("LINK with invalid filter: %v", diags)
+	}
+}
+
+func TestParseLinkNullable(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "item|Item.csv", Reader: strings.NewReader("" +
+				"ID,Name\n" +
+				"id,name\n" +
+				"int32,string\n" +
+				",UNIQUE\n" +
+				",\n" +
+				"1,sword\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+
+	_, err := ParseSources(newSources(""+
+		"ID,Item,Bak,ByName,Items\n"+
+		"id,item,bak,by name,items\n"+
+		"int32,int32,int32,string,[]int32\n"+
+		",LINK=Item.ID|NULLABLE,LINK=Item.ID|NULLABLE=-1,NULLABLE|LINK=Item.Name,LINK=Item.ID|NULLABLE\n"+
+		",,,,\n"+
+		`1,0,-1,,"[0,1]"`+"\n"+
+		`2,5,0,x,"[0,5]"`+"\n",
+	), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []struct {
+		cell, message string
+	}{
+		{"B7", "dst.ID=5 not found"},
+		{"C7", "dst.ID=0 not found"},
+		{"D7", "dst.Name=x not found"},
+		{"E7", "dst.ID=5 not found"},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Rule != gexcels.FRNLink || d.Cell() != e.cell || !strings.Contains(d.Message, e.message) {
+			t.Fatalf("diagnostic[%d] %s, expected %s %s", i, d, e.cell, e.message)
+		}
+	}
+
+	for _, test := range []struct {
+		typ, rule, message string
+	}{
+		{"int32", "NULLABLE", "NULLABLE without LINK"},
+		{"float32", "LINK=Item.ID|NULLABLE", "non-integer or string field"},
+		{"int32", "LINK=Item.ID|NULLABLE=a", "NULLABLE value a invalid"},
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,Item\n"+
+			"id,item\n"+
+			"int32,"+test.typ+"\n"+
+			","+test.rule+"\n"+
+			",\n"+
+			"1,1\n"), &Options{})
+		if diags := Diagnostics(err); len(diags) != 1 || diags[0].Cell() != "B4" || diags[0].Rule != gexcels.FRNNullable || !strings.Contains(diags[0].Message, test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.rule, err)
+		}
+	}
+}
+
+func TestParseLinkCompositeKey(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,StageRef,"Chapter:int32:""chapter"",Stage:int32:""stage""",,stage ref` + "\n")},
+			{Name: "stage|Stage.csv", Reader: strings.NewReader("" +
+				"ID,Chapter,Stage,Name\n" +
+				"id,chapter,stage,name\n" +
+				"int32,int32,int32,string\n" +
+				`,"CKEY=CS,0","CKEY=CS,1",` + "\n" +
+				",,,\n" +
+				"1,1,1,a\n" +
+				"2,1,2,b\n" +
+				"3,2,1,c\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+
+	_, err := ParseSources(newSources(""+
+		"ID,Refs,NextChapter,NextStage\n"+
+		"id,refs,next chapter,next stage\n"+
+		"int32,[]StageRef,int32,int32\n"+
+		`,"LINK=Stage.(Chapter,Stage)[Chapter == 1]","LINK=Stage.(Chapter=NextChapter,Stage=NextStage)|NULLABLE",`+"\n"+
+		",,,\n"+
+		`1,"[{""Chapter"":1,""Stage"":2}]",2,1`+"\n"+
+		`2,"[{""Chapter"":1,""Stage"":1},{""Chapter"":2,""Stage"":2}]",0,5`+"\n"+
+		"3,,3,1\n"+
+		`4,"[{""Chapter"":2,""Stage"":1}]",1,1`+"\n",
+	), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	expected := []struct {
+		cell, message string
+	}{
+		{"B7", "dst.(Chapter,Stage)=(2,2) not found"},
+		{"B9", "dst.(Chapter,Stage)=(2,1) not match filter"},
+		{"C8", "dst.(Chapter,Stage)=(3,1) not found"},
+	}
+	if len(diags) != len(expected) {
+		t.Fatalf("diagnostics %d, expected %d:\n%v", len(diags), len(expected), err)
+	}
+	for i, e := range expected {
+		d := diags[i]
+		if d.Rule != gexcels.FRNLink || d.Cell() != e.cell || !strings.Contains(d.Message, e.message) {
+			t.Fatalf("diagnostic[%d] %s, expected %s %s", i, d, e.cell, e.message)
+		}
+	}
+	if !strings.Contains(diags[2].Message, "Stage.(Chapter=NextChapter,Stage=NextStage)") {
+		t.Fatalf("diagnostic %s without composite-key", diags[2])
+	}
+
+	for _, test := range []struct {
+		rule, message string
+	}{
+		{"LINK=Stage.(Stage,Chapter)", "dst composite-key not found"},
+		{"LINK=Stage.(Chapter=NextStage,Stage=Other)", "src field not in composite-key src fields"},
+		{"LINK=Stage.(Chapter=NextChapter,Stage=Missing)", "src field Missing not found"},
+		{"LINK=Stage.(Chapter=NextChapter,Stage=Name)", "src field Name type not match"},
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,NextChapter,Name\n"+
+			"id,next chapter,name\n"+
+			"int32,int32,string\n"+
+			`,"`+test.rule+`",`+"\n"+
+			",,\n"+
+			"1,1,a\n"), &Options{})
+		if diags := Diagnostics(err); len(diags) != 1 || diags[0].Cell() != "B4" || !strings.Contains(diags[0].Message, test.message) {
+			t.Fatalf("%s: %v", test.rule, err)
+		}
+	}
+}
+
+func TestParseOptionalField(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "枚举|EnumKind.tsv", Reader: strings.NewReader("" +
+				"\n" +
+				"\tKind_BEGIN\tint32\tkind\n" +
+				"\tNormal\t1\tnormal\n" +
+				"\tBoss\t2\tboss\n")},
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Drop,"Count:int32?:""count"",Kind:Kind?:""kind""",,drop` + "\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+
+	p, err := ParseSources(newSources(""+
+		"ID,Count,Kind,Name,Drop\n"+
+		"id,count,kind,name,drop\n"+
+		"int32,int32?,Kind?,string?,Drop\n"+
+		`,"RANGE=0,10",,,`+"\n"+
+		",,,,\n"+
+		"1,,,,{}\n"+
+		`2,0,Boss,x,"{""Count"":0}"`+"\n",
+	), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := p.GetTableByName("A")
+	if s := td.GetFieldByName("Kind").FieldTypeInfo.String(); s != "Kind?" {
+		t.Fatalf("field type %s invalid", s)
+	}
+	for _, name := range []string{"Count", "Kind", "Name"} {
+		if v, ok := td.Entries[0][name]; ok {
+			t.Fatalf("entry[0] %s=%v, expected not set", name, v)
+		}
+	}
+	if drop := td.Entries[0]["Drop"].(map[string]any); len(drop) != 0 {
+		t.Fatalf("entry[0] Drop=%v, expected empty", drop)
+	}
+	if e := td.Entries[1]; e["Count"] != int32(0) || e["Kind"] != int32(2) || e["Name"] != "x" || e["Drop"].(map[string]any)["Count"] != int32(0) {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+
+	for _, test := range []struct {
+		typ, rule, message string
+	}{
+		{"[]int32?", "", "optional type []int32 must be primitive or enum"},
+		{"Drop?", "", "optional type Drop must be primitive or enum"},
+		{"int32?", "UNIQUE", "field rule UNIQUE on optional field"},
+		{"int32?", `"CKEY=ck,0"`, "field rule CKEY on optional field"},
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,Count\n"+
+			"id,count\n"+
+			"int32,"+test.typ+"\n"+
+			","+test.rule+"\n"+
+			",\n"+
+			"1,1\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.rule, err)
+		}
+	}
+
+	_, err = ParseSources(newSources(""+
+		"ID,Count\n"+
+		"id,count\n"+
+		"int32?,int32\n"+
+		",\n"+
+		",\n"+
+		"1,1\n"), &Options{})
+	if !errors.Is(err, errIDOptional) {
+		t.Fatalf("optional ID: %v", err)
+	}
+}
+
+func TestParseFieldDefault(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{
+			{Name: "枚举|EnumKind.tsv", Reader: strings.NewReader("" +
+				"\n" +
+				"\tKind_BEGIN\tint32\tkind\n" +
+				"\tNormal\t1\tnormal\n" +
+				"\tBoss\t2\tboss\n")},
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Drop,"Item:int32:""item"",Count:int32:""count""","DEFAULT=Count,5",drop` + "\n")},
+			{Name: "全局|GlobalA.csv", Reader: strings.NewReader("\n" +
+				",MaxLevel,int32,,DEFAULT=100,max level\n" +
+				",MinLevel,int32,2,DEFAULT=1,min level\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+
+	p, err := ParseSources(newSources(""+
+		"ID,Count,Kind,List,Drop\n"+
+		"id,count,kind,list,drop\n"+
+		"int32,int32,Kind,[]int32,Drop\n"+
+		`,"DEFAULT=100|RANGE=0,100",DEFAULT=Boss,"DEFAULT=[1,2]",`+"\n"+
+		",,,,\n"+
+		`1,,,,"{""Item"":1}"`+"\n"+
+		`2,0,Normal,[3],"{""Item"":2,""Count"":0}"`+"\n",
+	), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := p.GetTableByName("A")
+	if r := td.GetFieldByName("Count").GetFRDefault(); r == nil || r.Value != "100" {
+		t.Fatalf("field Count rule DEFAULT invalid: %v", r)
+	}
+	e := td.Entries[0]
+	if e["Count"] != int32(100) || e["Kind"] != int32(2) || fmt.Sprint(e["List"]) != "[1 2]" || e["Drop"].(map[string]any)["Count"] != int32(5) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	e = td.Entries[1]
+	if e["Count"] != int32(0) || e["Kind"] != int32(1) || fmt.Sprint(e["List"]) != "[3]" || e["Drop"].(map[string]any)["Count"] != int32(0) {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+	gtd := p.GetTableByName("GlobalA")
+	if gtd.GetEntryByName("MaxLevel") != int32(100) || gtd.GetEntryByName("MinLevel") != int32(2) {
+		t.Fatalf("global table GlobalA entries invalid: %v, %v", gtd.GetEntryByName("MaxLevel"), gtd.GetEntryByName("MinLevel"))
+	}
+
+	for _, test := range []struct {
+		typ, rule, message string
+	}{
+		{"int32", `"DEFAULT=200|RANGE=0,100"`, "DEFAULT value {200}"},
+		{"Kind", "DEFAULT=Elite", "enum Kind item Elite not define"},
+		{"int32?", "DEFAULT=1", "field-rule DEFAULT on optional field Count"},
+		{"int32", "DEFAULT=", "DEFAULT"},
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,Count\n"+
+			"id,count\n"+
+			"int32,"+test.typ+"\n"+
+			","+test.rule+"\n"+
+			",\n"+
+			"1,1\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.rule, err)
+		}
+		if diags := Diagnostics(err); len(diags) != 1 || diags[0].Cell() != "B4" {
+			t.Fatalf("%s %s: diagnostics %v invalid", test.typ, test.rule, diags)
+		}
+	}
+}
+
+func TestParseIntegerTypes(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{{Name: "a|A.csv", Reader: strings.NewReader(table)}}
+	}
+
+	p, err := ParseSources(newSources(""+
+		"ID,I8,I16,U8,U16,U32,U64,List,Map\n"+
+		"id,i8,i16,u8,u16,u32,u64,list,map\n"+
+		"uint16,int8,int16,uint8,uint16,uint32,uint64,[]uint8,map[uint32]int8\n"+
+		`,,,"RANGE=0,200",,,,,`+"\n"+
+		",,,,,,,,\n"+
+		`1,-128,-32768,200,65535,4294967295,18446744073709551615,"[0,255]","{""4294967295"":-1}"`+"\n"+
+		"2,127,32767,0,0,0,0,,\n",
+	), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := p.GetTableByName("A")
+	if s := td.GetFieldByName("Map").FieldTypeInfo.String(); s != "map[uint32]int8" {
+		t.Fatalf("field type %s invalid", s)
+	}
+	e := td.Entries[0]
+	if e["ID"] != uint16(1) || e["I8"] != int8(-128) || e["I16"] != int16(-32768) || e["U8"] != uint8(200) ||
+		e["U16"] != uint16(65535) || e["U32"] != uint32(4294967295) || e["U64"] != uint64(18446744073709551615) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	if !reflect.DeepEqual(e["List"], []uint8{0, 255}) || !reflect.DeepEqual(e["Map"], map[uint32]any{4294967295: int8(-1)}) {
+		t.Fatalf("entry[0] List=%#v Map=%#v invalid", e["List"], e["Map"])
+	}
+	if e := td.Entries[1]; e["I8"] != int8(127) || e["U64"] != uint64(0) {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+
+	for _, test := range []struct {
+		typ, value, message string
+	}{
+		{"int8", "128", "value out of range"},
+		{"int16", "-32769", "value out of range"},
+		{"uint8", "-1", "invalid syntax"},
+		{"uint16", "65536", "value out of range"},
+		{"uint32", "4294967296", "value out of range"},
+		{"uint64", "18446744073709551616", "value out of range"},
+		{"[]uint8", "[256]", "out of uint8 range"},
+		{"[]int8", `["-129"]`, "out of int8 range"},
+		{"map[uint8]int32", `{"-1":1}`, "map key invalid uint8"},
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,Value\n"+
+			"id,value\n"+
+			"int32,"+test.typ+"\n"+
+			",\n"+
+			",\n"+
+			`1,"`+strings.ReplaceAll(test.value, `"`, `""`)+`"`+"\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.value, err)
+		}
+	}
+}
+
+func TestParseTimeTypes(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{{Name: "a|A.csv", Reader: strings.NewReader(table)}}
+	}
+
+	p, err := ParseSources(newSources(""+
+		"ID,Start,End,Cooldown,Optional,Times,Durations\n"+
+		"id,start,end,cooldown,optional,times,durations\n"+
+		"int32,datetime,datetime,duration,datetime?,[]datetime,map[string]duration\n"+
+		",,,,,,\n"+
+		",,,,,,\n"+
+		`1,2024-01-02 08:00:00,45293.5,1h30m,,"[""2024-01-02T00:00:00Z""]","{""a"":""1d12h""}"`+"\n"+
+		"2,2024-01-02T08:00:00+08:00,2024/01/02,-90s,2024-01-02,,\n",
+	), &Options{TimeZone: "Asia/Shanghai"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := p.GetTableByName("A")
+	e := td.Entries[0]
+	if e["Start"] != time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) || e["End"] != time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC) ||
+		e["Cooldown"] != 90*time.Minute {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	if v, ok := e["Optional"]; ok {
+		t.Fatalf("entry[0] Optional=%v, expected not set", v)
+	}
+	if !reflect.DeepEqual(e["Times"], []time.Time{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}) ||
+		!reflect.DeepEqual(e["Durations"], map[string]any{"a": 36 * time.Hour}) {
+		t.Fatalf("entry[0] Times=%#v Durations=%#v invalid", e["Times"], e["Durations"])
+	}
+	if e := td.Entries[1]; e["Start"] != time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) ||
+		e["End"] != time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC) || e["Cooldown"] != -90*time.Second ||
+		e["Optional"] != time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC) {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+
+	for _, test := range []struct {
+		typ, value, message string
+	}{
+		{"datetime", "2024-13-01", "datetime 2024-13-01 invalid"},
+		{"datetime", "-1", "excel date serial -1 out of range"},
+		{"duration", "1x", "duration 1x invalid"},
+		{"duration", "1d-1h", "duration 1d-1h invalid"},
+		{"[]duration", "[90]", "must be string"},
+		{"map[datetime]int32", "{}", "map key type datetime invalid"},
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,Value\n"+
+			"id,value\n"+
+			"int32,"+test.typ+"\n"+
+			",\n"+
+			",\n"+
+			`1,"`+strings.ReplaceAll(test.value, `"`, `""`)+`"`+"\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.value, err)
+		}
+	}
+
+	if _, err = ParseSources(newSources(""), &Options{TimeZone: "Nowhere/City"}); err == nil || !strings.Contains(err.Error(), "time zone Nowhere/City invalid") {
+		t.Fatalf("invalid time zone: %v", err)
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	newSources := func(table string) []Source {
+		return []Source{{Name: "a|A.csv", Reader: strings.NewReader(table)}}
+	}
+
+	p, err := ParseSources(newSources(""+
+		"ID,Rate,Price,List,Map\n"+
+		"id,rate,price,list,map\n"+
+		"int32,decimal(2),decimal(0)?,[]decimal(4),map[int32]decimal(1)\n"+
+		`,"CHECK=Rate <= 1.5 && (ID != 1 || sum(List) > 5.5 && List[0] == 0.0001 && Map[1] == -0.5)",,,`+"\n"+
+		",,,,\n"+
+		`1,1.50,-12,"[""0.0001"",2.5,3]","{""1"":""-0.5""}"`+"\n"+
+		"2,-.05,,,\n",
+	), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := p.GetTableByName("A")
+	if s := td.GetFieldByName("List").FieldTypeInfo.String(); s != "[]decimal(4)" {
+		t.Fatalf("field type %s invalid", s)
+	}
+	e := td.Entries[0]
+	if e["Rate"] != int64(150) || e["Price"] != int64(-12) ||
+		!reflect.DeepEqual(e["List"], []int64{1, 25000, 30000}) || !reflect.DeepEqual(e["Map"], map[int32]any{1: int64(-5)}) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	if e := td.Entries[1]; e["Rate"] != int64(-5) || e["Price"] != nil {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+
+	for _, test := range []struct {
+		typ, rule, value, message string
+	}{
+		{"decimal(2)", "", "1.005", "decimal 1.005 exceeds scale 2"},
+		{"decimal(2)", "", "1e3", "decimal 1e3 invalid"},
+		{"decimal(18)", "", "10", "decimal 10 out of range"},
+		{"decimal(19)", "", "1", "decimal scale 19 must in [0,18]"},
+		{"decimal(2)", `"RANGE=0,1"`, "1.01", "Value=1.01 out of range [0,1]"},
+		{"decimal(2)", `"RANGE=0,1"`, "1.5", "Value=1.50 out of range [0,1]"},
+		{"[]decimal(3)", `"RANGE=0,"`, `["-0.05"]`, "Value[0]=-0.050 out of range [0,]"},
+		{"[]decimal(2)", "", `[true]`, "must be decimal string or number"},
+	} {
+		_, err = ParseSources(newSources(""+
+			"ID,Value\n"+
+			"id,value\n"+
+			"int32,"+test.typ+"\n"+
+			","+test.rule+"\n"+
+			",\n"+
+			`1,"`+strings.ReplaceAll(test.value, `"`, `""`)+`"`+"\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.value, err)
+		}
+	}
+}
+
+func TestParseFlagsEnum(t *testing.T) {
+	newSources := func(enum, table string) []Source {
+		return []Source{
+			{Name: "枚举|EnumElement.tsv", Reader: strings.NewReader(enum)},
+			{Name: "a|A.csv", Reader: strings.NewReader(table)},
+		}
+	}
+	const enum = "" +
+		"\n" +
+		"\tElement_FLAGS\tint32\telement\n" +
+		"\tNone\t0\tnone\n" +
+		"\tFire\t1\tfire\n" +
+		"\tPoison\t2\tpoison\n" +
+		"\tIce\t4\tice\n"
+
+	p, err := ParseSources(newSources(enum, ""+
+		"ID,Element,List\n"+
+		"id,element,list\n"+
+		"int32,Element,[]Element\n"+
+		",,\n"+
+		",,\n"+
+		`1,Fire|Poison,"[""Ice"",""Fire | Ice""]"`+"\n"+
+		"2,,\n",
+	), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.GetEnum("Element").Flags {
+		t.Fatalf("enum Element not flags")
+	}
+	td := p.GetTableByName("A")
+	if e := td.Entries[0]; e["Element"] != int32(3) || !reflect.DeepEqual(e["List"], []any{int32(4), int32(5)}) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	if e := td.Entries[1]; e["Element"] != int32(0) {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+
+	// 未填写的位标志枚举单元格产生lint警告, 可选字段或具备默认值时不产生
+	p, err = ParseSources(newSources(enum, ""+
+		"ID,Element,Default,Optional\n"+
+		"id,element,default,optional\n"+
+		"int32,Element,Element,Element?\n"+
+		",,DEFAULT=Fire,\n"+
+		",,,\n"+
+		"1,,,\n",
+	), &Options{Lint: &LintOptions{Disable: []string{LintUnlinkedTable}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Warnings) != 1 || p.Warnings[0].Rule != LintEmptyFlags || p.Warnings[0].Cell() != "B6" {
+		t.Fatalf("warnings %v, expected %s at B6", p.Warnings, LintEmptyFlags)
+	}
+
+	for _, test := range []struct {
+		enum, value, message string
+	}{
+		{enum, "Fire|Water", "enum Element item Water not define"},
+		{enum, "Fire||Ice", "flags Fire||Ice has empty item name"},
+		{strings.Replace(enum, "\tIce\t4\t", "\tIce\t6\t", 1), "Fire", "flags item Ice value 6 must be 0 or power of two"},
+		{strings.Replace(enum, "\tElement_FLAGS\tint32\t", "\tElement_FLAGS\tstring\t", 1), "Fire", "invalid flags enum type string, must be int32"},
+	} {
+		_, err = ParseSources(newSources(test.enum, ""+
+			"ID,Element\n"+
+			"id,element\n"+
+			"int32,Element\n"+
+			",\n"+
+			",\n"+
+			"1,"+test.value+"\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s: %v", test.value, err)
+		}
+	}
+}
+
+func TestParseUnion(t *testing.T) {
+	newSources := func(union, value string) []Source {
+		return []Source{
+			{Name: "枚举|EnumEffectType.tsv", Reader: strings.NewReader("" +
+				"\n" +
+				"\tEffectType_BEGIN\tint32\teffect type\n" +
+				"\tDamage\t1\tdamage\n" +
+				"\tHeal\t2\theal\n" +
+				"\tStun\t3\tstun\n")},
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,DamageEffect,"Amount:int32:""amount"",ItemID:int32:""item""","LINK=ItemID,Item.ID|RANGE=Amount,0,100",damage` + "\n" +
+				`,Heal,"HP:int32:""hp""",,heal` + "\n" +
+				`,Effect,"` + union + `",,effect` + "\n")},
+			{Name: "item|Item.csv", Reader: strings.NewReader("ID\nid\nint32\n\n\n1\n2\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader("" +
+				"ID,Effect,List\n" +
+				"id,effect,list\n" +
+				"int32,Effect,[]Effect\n" +
+				",,\n" +
+				",,\n" +
+				value)},
+		}
+	}
+
+	p, err := ParseSources(newSources("union(EffectType):Damage=DamageEffect,Heal", ""+
+		`1,"Damage{Amount:10,ItemID:1}","[""Heal{HP:5}"",{""Damage"":{""Amount"":1}}]"`+"\n"+
+		`2,{Heal:{HP:3}},`+"\n"+
+		"3,Heal,\n",
+	), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := p.GetUnionByName("Effect")
+	if u == nil || u.Enum != "EffectType" || len(u.Cases) != 2 || u.GetCaseByName("Damage").Struct != "DamageEffect" || u.GetCaseByName("Heal").Struct != "Heal" {
+		t.Fatalf("union Effect %+v invalid", u)
+	}
+	td := p.GetTableByName("A")
+	if e := td.Entries[0]; !reflect.DeepEqual(e["Effect"], &UnionValue{Case: "Damage", Value: map[string]any{"Amount": int32(10), "ItemID": int32(1)}}) ||
+		!reflect.DeepEqual(e["List"], []any{
+			&UnionValue{Case: "Heal", Value: map[string]any{"HP": int32(5)}},
+			&UnionValue{Case: "Damage", Value: map[string]any{"Amount": int32(1)}},
+		}) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	if e := td.Entries[1]; !reflect.DeepEqual(e["Effect"], &UnionValue{Case: "Heal", Value: map[string]any{"HP": int32(3)}}) {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+	if e := td.Entries[2]; !reflect.DeepEqual(e["Effect"], &UnionValue{Case: "Heal", Value: map[string]any{}}) {
+		t.Fatalf("entry[2] %v invalid", e)
+	}
+
+	for _, test := range []struct {
+		union, value, message string
+	}{
+		{"union(EffectType):Damage=DamageEffect,Heal", "Stun", "union Effect case Stun not define"},
+		{"union(EffectType):Damage=DamageEffect,Heal", `"{Damage:{},Heal:{}}"`, "Effect must be union"},
+		{"union(EffectType):Damage=DamageEffect,Heal", `"Damage{Amount:200,ItemID:1}"`, "Effect.Damage.Amount=200"},
+		{"union(EffectType):Damage=DamageEffect,Poison", "Heal", "enum EffectType item Poison not define"},
+		{"union(EffectType):Damage=Missing", "Damage", "struct Missing not define"},
+		{"union(EffectType):Damage=Heal,Heal", "Heal", "case Heal struct Heal already used by case Damage"},
+		{"union(EffectType):Damage=DamageEffect,Damage=Heal", "Heal", "case Damage duplicate"},
+		{"union(Missing):Damage=DamageEffect", "Damage", "enum Missing not define"},
+	} {
+		_, err = ParseSources(newSources(test.union, "1,"+test.value+",\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.union, test.value, err)
+		}
+	}
+
+	// 仅检查所选分支的链接
+	_, err = ParseSources(newSources("union(EffectType):Damage=DamageEffect,Heal", ""+
+		`1,"Damage{Amount:1,ItemID:9}",`+"\n"+
+		`2,"Heal{HP:9}","[""Damage{ItemID:2}"",""Damage{ItemID:3}""]"`+"\n",
+	), &Options{CollectErrors: true})
+	diags := Diagnostics(err)
+	if len(diags) != 2 {
+		t.Fatalf("diagnostics %v invalid", diags)
+	}
+	for i, message := range []string{"Effect.Damage.ItemID", "List.Damage.ItemID"} {
+		if diags[i].Field != message {
+			t.Fatalf("diagnostics[%d] %v invalid", i, diags[i])
+		}
+	}
+}
+
+func TestParseStructInherit(t *testing.T) {
+	newSources := func(structs, value string) []Source {
+		return []Source{
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Reward,"Count:int32:""count""","RANGE=Count,1,100|DEFAULT=Count,1",reward` + "\n" +
+				structs)},
+			{Name: "item|Item.csv", Reader: strings.NewReader("ID\nid\nint32\n\n\n1\n2\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader("" +
+				"ID,Item,Coin\n" +
+				"id,item,coin\n" +
+				"int32,ItemReward,CurrencyReward\n" +
+				",,\n" +
+				",,\n" +
+				value)},
+		}
+	}
+	structs := "" +
+		`,ItemReward:Reward,"ItemID:int32:""item""","LINK=ItemID,Item.ID",item reward` + "\n" +
+		`,CurrencyReward : Reward,"Currency:string",,currency reward` + "\n" +
+		`,BonusItemReward:ItemReward,,,bonus item reward` + "\n"
+
+	p, err := ParseSources(newSources(structs, `1,"{Count:5,ItemID:1}","{Currency:gold}"`+"\n"), &Options{Lint: &LintOptions{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	itemReward := p.GetStructByName("ItemReward")
+	if itemReward.Parent == nil || itemReward.Parent.Name != "Reward" ||
+		len(itemReward.Fields) != 2 || itemReward.Fields[0].Name != "Count" ||
+		len(itemReward.OwnFields()) != 1 || itemReward.OwnFields()[0].Name != "ItemID" {
+		t.Fatalf("struct ItemReward %+v invalid", itemReward.Struct)
+	}
+	if sd := p.GetStructByName("BonusItemReward"); sd.Parent != itemReward.Struct || len(sd.Fields) != 2 || len(sd.OwnFields()) != 0 {
+		t.Fatalf("struct BonusItemReward %+v invalid", sd.Struct)
+	}
+	if e := p.GetTableByName("A").Entries[0]; !reflect.DeepEqual(e["Item"], map[string]any{"Count": int32(5), "ItemID": int32(1)}) ||
+		!reflect.DeepEqual(e["Coin"], map[string]any{"Count": int32(1), "Currency": "gold"}) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	for _, diag := range p.Warnings {
+		if diag.Rule == LintUnusedStruct && strings.Contains(diag.Message, "struct Reward ") {
+			t.Fatalf("parent struct reported unused: %v", diag)
+		}
+	}
+
+	for _, test := range []struct {
+		structs, value, message string
+	}{
+		{`,ItemReward:Missing,"ItemID:int32",,` + "\n", "1,,", "struct ItemReward parent: struct Missing not define"},
+		{`,ItemReward:Reward,"Count:int32",,` + "\n", "1,,", "field name Count conflicts with parent struct Reward"},
+		{`,ItemReward:Reward,"ItemID:int32","RANGE=Count,1,5",` + "\n", "1,,", "RANGE: local field[Count] inherited from struct Reward"},
+		{structs, `1,"{Count:200,ItemID:1}",`, "Item.Count=200"},
+		{structs, `1,"{Count:2,ItemID:9}",`, "link errors found"},
+	} {
+		_, err = ParseSources(newSources(test.structs, test.value+"\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.structs, test.value, err)
+		}
+	}
+}
+
+func TestParseTuple(t *testing.T) {
+	newSources := func(structs, typ, rule, value string) []Source {
+		return []Source{
+			{Name: "枚举|EnumKind.tsv", Reader: strings.NewReader("\n\tKind_BEGIN\tint32\tkind\n\tNormal\t1\tnormal\n\tBoss\t2\tboss\n")},
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Spawn,"Pos:vec3:""pos"",Tint:color:""tint""",,spawn` + "\n" + structs)},
+			{Name: "a|A.csv", Reader: strings.NewReader("" +
+				"ID,Pos,Path,Tint,Pair,Spawn\n" +
+				"id,pos,path,tint,pair,spawn\n" +
+				"int32," + typ + ",[]vec2,color,\"tuple[Kind,string]\",Spawn\n" +
+				"," + rule + ",,,,\n" +
+				",,,,,\n" +
+				value)},
+		}
+	}
+
+	p, err := ParseSources(newSources("", "vec3", `"CHECK=Pos.X >= 0 && Pos.Z < 1"`, ""+
+		`1,"1.5, 2,0","1,2;3,4","1,0,0","Boss,boss","{Pos:[1,2,3],Tint:""0,1,0,0.5""}"`+"\n"+
+		`2,"[0,0,0.5]","[[5,6]]","[0.1,0.2,0.3,0.4]","[""Normal"",""normal""]",`+"\n"),
+		&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := p.GetTableByName("A")
+	if s := td.GetFieldByName("Pair").FieldTypeInfo.String(); s != "tuple[Kind,string]" {
+		t.Fatalf("field type %s invalid", s)
+	}
+	if e := td.Entries[0]; !reflect.DeepEqual(e["Pos"], []any{float32(1.5), float32(2), float32(0)}) ||
+		!reflect.DeepEqual(e["Path"], []any{[]any{float32(1), float32(2)}, []any{float32(3), float32(4)}}) ||
+		!reflect.DeepEqual(e["Tint"], []any{float32(1), float32(0), float32(0), float32(1)}) ||
+		!reflect.DeepEqual(e["Pair"], []any{int32(2), "boss"}) ||
+		!reflect.DeepEqual(e["Spawn"], map[string]any{
+			"Pos":  []any{float32(1), float32(2), float32(3)},
+			"Tint": []any{float32(0), float32(1), float32(0), float32(0.5)},
+		}) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	if e := td.Entries[1]; !reflect.DeepEqual(e["Pos"], []any{float32(0), float32(0), float32(0.5)}) ||
+		!reflect.DeepEqual(e["Path"], []any{[]any{float32(5), float32(6)}}) ||
+		!reflect.DeepEqual(e["Tint"], []any{float32(0.1), float32(0.2), float32(0.3), float32(0.4)}) ||
+		!reflect.DeepEqual(e["Pair"], []any{int32(1), "normal"}) {
+		t.Fatalf("entry[1] %v invalid", e)
+	}
+
+	for _, test := range []struct {
+		structs, typ, rule, value, message string
+	}{
+		{"", "vec3", "", `"1,2"`, "Pos vec3 must have 3 elements, got 2"},
+		{"", "vec3", "", `"1,x,2"`, "Pos[1] invalid float"},
+		{"", "vec3", "", `"[true,1,2]"`, "Pos[0] must be number"},
+		{"", "vec3", `"CHECK=Pos.X > 1"`, `"1,2,3"`, "CHECK {Pos.X > 1} failed"},
+		{"", "vec3", `"CHECK=Pos.W > 1"`, `"1,2,3"`, "tuple vec3 element W not found"},
+		{"", "vec3?", "", `"1,2,3"`, "optional type vec3 must be primitive or enum"},
+		{"", "tuple[int32]", "", "1", "element number must in [2,8]"},
+		{"", `"tuple[[]int32,int32]"`, "", `"[[1],2]"`, "tuple element type []int32 must be primitive or enum"},
+		{`,vec2,"X:int32",,` + "\n", "vec3", "", `"1,2,3"`, "custom field type vec2 conflicts with builtin tuple type"},
+	} {
+		_, err = ParseSources(newSources(test.structs, test.typ, test.rule, "1,"+test.value+",,,,\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.value, err)
+		}
+	}
+}
+
+func TestParseText(t *testing.T) {
+	newSources := func(typ, rule, value string) []Source {
+		return []Source{
+			{Name: "结构体|Struct.csv", Reader: strings.NewReader("\n" +
+				`,Reward,"Count:int32:""count"",Tips:text:""tips""",,reward` + "\n")},
+			{Name: "全局|GlobalG.csv", Reader: strings.NewReader("" +
+				",Name,type,value,rule,desc\n" +
+				",Title,i18n,Hello,,title\n")},
+			{Name: "a|A.csv", Reader: strings.NewReader("" +
+				"ID,Name,Tags,Dict,Reward\n" +
+				"id,name,tags,dict,reward\n" +
+				"int32," + typ + ",[]text,map[int32]text,Reward\n" +
+				"," + rule + ",,,\n" +
+				",,,,\n" +
+				value)},
+		}
+	}
+
+	p, err := ParseSources(newSources("text", "NOTEMPTY", ""+
+		`1,Sword,"[""a"",""""]","{""2"":""two"",""1"":""one""}","{Count:1,Tips:""take it""}"`+"\n"+
+		"2,Shield,,,\n"), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := p.GetTableByName("A")
+	if e := td.Entries[0]; e["Name"] != "A.1.Name" ||
+		!reflect.DeepEqual(e["Tags"], []string{"A.1.Tags[0]", ""}) ||
+		!reflect.DeepEqual(e["Dict"], map[int32]any{1: "A.1.Dict[1]", 2: "A.1.Dict[2]"}) ||
+		!reflect.DeepEqual(e["Reward"], map[string]any{"Count": int32(1), "Tips": "A.1.Reward.Tips"}) {
+		t.Fatalf("entry[0] %v invalid", e)
+	}
+	if v := p.GetTableByName("GlobalG").GetEntryByName("Title"); v != "GlobalG.Title" {
+		t.Fatalf("global Title %v invalid", v)
+	}
+	var keys []string
+	for _, text := range p.Texts {
+		keys = append(keys, text.Key+"="+text.Value)
+	}
+	if expected := []string{
+		"A.1.Name=Sword", "A.1.Tags[0]=a", "A.1.Dict[1]=one", "A.1.Dict[2]=two", "A.1.Reward.Tips=take it",
+		"A.2.Name=Shield", "GlobalG.Title=Hello",
+	}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("texts %v invalid", keys)
+	}
+	if text := p.GetText("A.2.Name"); text == nil || text.Table != "A" || text.Value != "Shield" {
+		t.Fatalf("text A.2.Name %v invalid", text)
+	}
+
+	for _, test := range []struct {
+		typ, rule, value, message string
+	}{
+		{"text", "NOTEMPTY", ",", "Name={} gexcels: field-rule notempty value empty"},
+		{"text", `"CHECK=Name == ""Axe"""`, "Sword,", `CHECK {Name == "Axe"} failed`},
+		{"text", "UNIQUE", "Sword,", "field rule UNIQUE on text field"},
+		{"text?", "", "Sword,", "text type text can not be optional"},
+		{`"tuple[int32,i18n]"`, "", `"1,a",`, "text type i18n can not be tuple element"},
+	} {
+		_, err = ParseSources(newSources(test.typ, test.rule, "1,"+test.value+",,\n"), &Options{})
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("%s %s: %v", test.typ, test.rule, err)
+		}
+	}
+
+	_, err = ParseSources([]Source{{Name: "a|A.csv", Reader: strings.NewReader("ID\nid\ntext\n\n\n1\n")}}, &Options{})
+	if err == nil || !strings.Contains(err.Error(), "field ID type cant be text") {
+		t.Fatalf("text ID: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tealeg/xlsx/v3"
+)
+
+// Sheet 数据表抽象, 屏蔽不同数据源(xlsx/csv/tsv...)之间的差异.
+type Sheet interface {
+	// Name 表名
+	Name() string
+
+	// MaxRow 最大行数
+	MaxRow() int
+
+	// MaxCol 最大列数
+	MaxCol() int
+
+	// Cell 获取单元格的值, 超出范围时返回空字符串
+	Cell(row, col int) (string, error)
+}
+
+// Workbook 工作簿抽象, 包含一个或多个 Sheet.
+type Workbook interface {
+	// Sheets 工作簿中的所有表
+	Sheets() []Sheet
+}
+
+// WorkbookDecoder 工作簿解码器.
+// name 为数据源文件名(不含目录), data 为文件内容.
+type WorkbookDecoder func(name string, data []byte) (Workbook, error)
+
+var (
+	workbookDecodersMu sync.RWMutex
+	workbookDecoders   = map[string]WorkbookDecoder{
+		".xlsx": decodeXlsxWorkbook,
+		".csv":  decodeCSVWorkbook(','),
+		".tsv":  decodeCSVWorkbook('\t'),
+	}
+)
+
+// RegisterWorkbookDecoder 注册数据源解码器.
+// ext 为文件扩展名(包含'.'，例如".xlsx"), 重复注册会覆盖之前的解码器.
+func RegisterWorkbookDecoder(ext string, decoder WorkbookDecoder) {
+	if ext == "" || decoder == nil {
+		panic("parse: RegisterWorkbookDecoder ext or decoder nil")
+	}
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	workbookDecodersMu.Lock()
+	defer workbookDecodersMu.Unlock()
+	workbookDecoders[ext] = decoder
+}
+
+// getWorkbookDecoder 根据文件扩展名获取解码器
+func getWorkbookDecoder(ext string) WorkbookDecoder {
+	workbookDecodersMu.RLock()
+	defer workbookDecodersMu.RUnlock()
+	return workbookDecoders[strings.ToLower(ext)]
+}
+
+// decodeWorkbook 根据文件名扩展名解码工作簿
+func decodeWorkbook(name string, data []byte) (Workbook, error) {
+	decoder := getWorkbookDecoder(filepath.Ext(name))
+	if decoder == nil {
+		return nil, fmt.Errorf("workbook %s format not supported", name)
+	}
+	return decoder(name, data)
+}
+
+// xlsxWorkbook xlsx 工作簿
+type xlsxWorkbook struct {
+	sheets []Sheet
+}
+
+func (wb *xlsxWorkbook) Sheets() []Sheet { return wb.sheets }
+
+// xlsxSheet xlsx 表
+type xlsxSheet struct {
+	sheet  *xlsx.Sheet
+	maxRow int
+	maxCol int
+}
+
+func (s *xlsxSheet) Name() string { return s.sheet.Name }
+func (s *xlsxSheet) MaxRow() int  { return s.maxRow }
+func (s *xlsxSheet) MaxCol() int  { return s.maxCol }
+
+func (s *xlsxSheet) Cell(row, col int) (string, error) {
+	// xlsx.Sheet.Cell 在越界时会扩展表格, 这里需要提前拦截.
+	if row < 0 || row >= s.maxRow || col < 0 || col >= s.maxCol {
+		return "", nil
+	}
+	cell, err := s.sheet.Cell(row, col)
+	if err != nil {
+		return "", err
+	}
+	return cell.Value, nil
+}
+
+// decodeXlsxWorkbook 解码 xlsx 工作簿
+func decodeXlsxWorkbook(_ string, data []byte) (Workbook, error) {
+	file, err := xlsx.OpenBinary(data)
+	if err != nil {
+		return nil, err
+	}
+	wb := &xlsxWorkbook{sheets: make([]Sheet, 0, len(file.Sheets))}
+	for _, sheet := range file.Sheets {
+		wb.sheets = append(wb.sheets, &xlsxSheet{
+			sheet:  sheet,
+			maxRow: sheet.MaxRow,
+			maxCol: sheet.MaxCol,
+		})
+	}
+	return wb, nil
+}
+
+// recordSheet 以二维字符串表示的表, 用于 csv/tsv 等纯文本数据源.
+type recordSheet struct {
+	name    string
+	records [][]string
+	maxCol  int
+}
+
+// NewRecordSheet 通过二维字符串创建 Sheet.
+func NewRecordSheet(name string, records [][]string) Sheet {
+	s := &recordSheet{name: name, records: records}
+	for _, record := range records {
+		if len(record) > s.maxCol {
+			s.maxCol = len(record)
+		}
+	}
+	return s
+}
+
+func (s *recordSheet) Name() string { return s.name }
+func (s *recordSheet) MaxRow() int  { return len(s.records) }
+func (s *recordSheet) MaxCol() int  { return s.maxCol }
+
+func (s *recordSheet) Cell(row, col int) (string, error) {
+	if row < 0 || row >= len(s.records) || col < 0 || col >= len(s.records[row]) {
+		return "", nil
+	}
+	return s.records[row][col], nil
+}
+
+// sheetsWorkbook 由若干 Sheet 组成的工作簿
+type sheetsWorkbook []Sheet
+
+func (wb sheetsWorkbook) Sheets() []Sheet { return wb }
+
+// NewWorkbook 通过若干 Sheet 创建工作簿.
+func NewWorkbook(sheets ...Sheet) Workbook {
+	return sheetsWorkbook(sheets)
+}
+
+// utf8BOM UTF-8 BOM 头
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// decodeCSVWorkbook 创建以 comma 分隔的文本工作簿解码器.
+// 一个文件即一个 sheet, sheet 名为去掉扩展名的文件名, 例如 "道具|Item.csv".
+func decodeCSVWorkbook(comma rune) WorkbookDecoder {
+	return func(name string, data []byte) (Workbook, error) {
+		records, err := readCSVRecords(bytes.TrimPrefix(data, utf8BOM), comma)
+		if err != nil {
+			return nil, err
+		}
+		sheetName := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		return NewWorkbook(NewRecordSheet(sheetName, records)), nil
+	}
+}
+
+// readCSVRecords 读取文本数据中的所有记录.
+// encoding/csv 会跳过空行, 这里以空记录补齐, 保证记录索引与文件行号一致.
+func readCSVRecords(data []byte, comma rune) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var (
+		records [][]string
+		lines   int   // 已读取的行数
+		offset  int64 // 已读取的字节数
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 当前记录之前被跳过的空行
+		start, _ := reader.FieldPos(0)
+		for i := lines + 1; i < start; i++ {
+			records = append(records, nil)
+		}
+		records = append(records, record)
+		end := reader.InputOffset()
+		lines += bytes.Count(data[offset:end], []byte{'\n'})
+		offset = end
+	}
+	return records, nil
+}
+
+// sheetRow 表中的一行
+type sheetRow struct {
+	sheet Sheet
+	row   int
+}
+
+// value 获取 col 列的值, 出错时返回空字符串
+func (r sheetRow) value(col int) string {
+	v, _ := r.sheet.Cell(r.row, col)
+	return v
+}
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
 	"sort"
 	"strconv"
@@ -9,12 +10,16 @@ import (
 
 	"github.com/godyy/gexcels"
 	pkg_errors "github.com/pkg/errors"
-	"github.com/tealeg/xlsx/v3"
 )
 
 // Struct 结构体
 type Struct struct {
-	*gexcels.Struct // 基础信息
+	*gexcels.Struct        // 基础信息
+	Path            string // 数据源路径
+	Sheet           string // sheet名
+	Row             int    // 定义所在行号(从1开始)
+
+	defaults map[string]any // 字段默认值
 }
 
 // newStruct 创建结构体
@@ -41,12 +46,15 @@ func (p *Parser) GetStructByName(name string) *Struct {
 
 // parseStructs 解析结构体定义
 func (p *Parser) parseStructs(files []string) error {
+	var errs Errors
 	for _, file := range files {
 		if err := p.parseStructFile(file); err != nil {
-			return err
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
 		}
 	}
-	return nil
+	return errs.err()
 }
 
 // structSheetNameRegexp 结构体sheet名匹配正则表达式
@@ -54,14 +62,14 @@ var structSheetNameRegexp = regexp.MustCompile(`^(.*)\|(Struct\w*)(?:\.([0-9]*))
 
 // parseStructFile 解析结构体定义文件
 func (p *Parser) parseStructFile(path string) error {
-	file, err := xlsx.OpenFile(path)
+	file, err := p.openDefineWorkbook(path)
 	if err != nil {
-		return err
+		return withDiagnostic(err, Diagnostic{Path: path})
 	}
 
-	sheets := make([]*prioritySheetInfo, 0, len(file.Sheets))
-	for _, sheet := range file.Sheets {
-		matches := structSheetNameRegexp.FindStringSubmatch(sheet.Name)
+	sheets := make([]*prioritySheetInfo, 0, len(file.Sheets()))
+	for _, sheet := range file.Sheets() {
+		matches := structSheetNameRegexp.FindStringSubmatch(sheet.Name())
 		if len(matches) != 4 {
 			continue
 		}
@@ -77,66 +85,115 @@ func (p *Parser) parseStructFile(path string) error {
 		return sheets[i].priority < sheets[j].priority
 	})
 
+	var errs Errors
 	for _, sheet := range sheets {
-		if err := p.parseStructSheet(sheet.Sheet); err != nil {
-			return pkg_errors.WithMessagef(err, "struct file(%s).sheet(%s)", path, sheet.Name)
+		if err := p.parseStructSheet(path, sheet.Sheet); err != nil {
+			err = withDiagnostic(
+				withMessagef(err, "struct file(%s).sheet(%s)", path, sheet.Name()),
+				Diagnostic{Path: path, Sheet: sheet.Name()},
+			)
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
 		}
 	}
-	return nil
+	return errs.err()
 }
 
 // parseStructSheet 解析sheet中定义的结构体
-func (p *Parser) parseStructSheet(sheet *xlsx.Sheet) error {
-	if sheet.MaxRow < gexcels.TableStructFirstRow || sheet.MaxCol < gexcels.TableStructCols {
+func (p *Parser) parseStructSheet(path string, sheet Sheet) error {
+	if sheet.MaxRow() < gexcels.TableStructFirstRow || sheet.MaxCol() < gexcels.TableStructCols {
 		return errSheetRowsOrColsNotMatch
 	}
-	for i := gexcels.TableStructFirstRow; i < sheet.MaxRow; i++ {
-		row, err := sheet.Row(i)
-		if err != nil {
-			return pkg_errors.WithMessagef(err, "row[%d]", i)
-		}
-
+	var errs Errors
+	for i := gexcels.TableStructFirstRow; i < sheet.MaxRow(); i++ {
+		row := sheetRow{sheet: sheet, row: i}
 		if isRowComment(row) {
 			continue
 		}
+		rowDiag := Diagnostic{Row: i + 1, Field: strings.TrimSpace(row.value(gexcels.TableStructColName))}
 
-		rowTag := strings.TrimSpace(row.GetCell(gexcels.TableStructColTag).Value)
+		rowTag := strings.TrimSpace(row.value(gexcels.TableStructColTag))
 		if ok, valid := p.checkTag(rowTag); !valid {
-			return fmt.Errorf("row[%d] tag(%s) invalid", i, rowTag)
+			if err := p.collectError(&errs, withDiagnostic(fmt.Errorf("row[%d] tag(%s) invalid", i, rowTag), rowDiag)); err != nil {
+				return err
+			}
+			continue
 		} else if !ok {
 			continue
 		}
 
+		if isUnionRow(row) {
+			ud, err := p.parseUnionRow(row)
+			if err != nil {
+				err = withDiagnostic(pkg_errors.WithMessagef(err, "row[%d] %s", i, row.value(gexcels.TableStructColName)), rowDiag)
+				if err := p.collectError(&errs, err); err != nil {
+					return err
+				}
+				continue
+			}
+			ud.Path, ud.Sheet, ud.Row = path, sheet.Name(), i+1
+			if err := p.addUnion(ud); err != nil {
+				if err := p.collectError(&errs, withDiagnostic(pkg_errors.WithMessagef(err, "add union %s", ud.Name), rowDiag)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
 		sd, err := p.parseStructRow(row)
 		if err != nil {
-			return pkg_errors.WithMessagef(err, "row[%d] %s", i, row.GetCell(gexcels.TableStructColName).Value)
+			err = withDiagnostic(pkg_errors.WithMessagef(err, "row[%d] %s", i, row.value(gexcels.TableStructColName)), rowDiag)
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+			continue
 		}
+		sd.Path, sd.Sheet, sd.Row = path, sheet.Name(), i+1
 		if err := p.addStruct(sd); err != nil {
-			return pkg_errors.WithMessagef(err, "add struct %s", sd.Name)
+			if err := p.collectError(&errs, withDiagnostic(pkg_errors.WithMessagef(err, "add struct %s", sd.Name), rowDiag)); err != nil {
+				return err
+			}
 		}
 	}
 
-	return nil
+	return errs.err()
 }
 
+// structNameRegexp 结构体名称匹配正则表达式
+// 格式：Name[:Parent], 父结构体需在之前定义, 其字段被子结构体继承.
+var structNameRegexp = regexp.MustCompile(`^(` + gexcels.NamePattern + `)(?:\s*:\s*(` + gexcels.NamePattern + `))?$`)
+
 // parseStructRow 解析row中定义的结构体
-func (p *Parser) parseStructRow(row *xlsx.Row) (*Struct, error) {
-	sdName := strings.TrimSpace(row.GetCell(gexcels.TableStructColName).Value)
+func (p *Parser) parseStructRow(row sheetRow) (*Struct, error) {
+	sdName := strings.TrimSpace(row.value(gexcels.TableStructColName))
 	if sdName == "" {
 		return nil, nil
 	}
-	if !gexcels.MatchName(sdName) {
+	matches := structNameRegexp.FindStringSubmatch(sdName)
+	if len(matches) != 3 {
 		return nil, fmt.Errorf("struct name %s invalid", sdName)
 	}
 
-	sdDesc := row.GetCell(gexcels.TableStructColDesc).Value
-	sd := newStruct(sdName, sdDesc)
+	sdDesc := row.value(gexcels.TableStructColDesc)
+	sd := newStruct(matches[1], sdDesc)
 
-	if err := p.parseStructFields(sd, strings.TrimSpace(row.GetCell(gexcels.TableStructColFields).Value)); err != nil {
+	if matches[2] != "" {
+		parent := p.GetStructByName(matches[2])
+		if parent == nil {
+			return nil, pkg_errors.WithMessagef(errStructNotDefine(matches[2]), "struct %s parent", sd.Name)
+		}
+		sd.Parent = parent.Struct
+		if len(parent.defaults) > 0 {
+			sd.defaults = maps.Clone(parent.defaults)
+		}
+	}
+
+	if err := p.parseStructFields(sd, strings.TrimSpace(row.value(gexcels.TableStructColFields))); err != nil {
 		return nil, pkg_errors.WithMessagef(err, " struct %s fields", sd.Name)
 	}
 
-	if err := p.parseStructRule(sd, row.GetCell(gexcels.TableStructColRule).Value); err != nil {
+	if err := p.parseStructRule(sd, row.value(gexcels.TableStructColRule)); err != nil {
 		return nil, err
 	}
 
@@ -145,28 +202,42 @@ func (p *Parser) parseStructRow(row *xlsx.Row) (*Struct, error) {
 
 // parseStructFields 解析所有结构体字段
 func (p *Parser) parseStructFields(sd *Struct, fields string) error {
-	fields = strings.TrimSpace(fields)
-	if fields == "" {
-		return fmt.Errorf("fields empty")
+	var inherited []*gexcels.Field
+	if sd.Parent != nil {
+		inherited = sd.Parent.Fields
 	}
 
-	// 字段定义字符串以前通过正则限制类型表达式形态，但会阻碍 map 等复杂类型。
-	// 这里改为分割 + 逐字段解析，保证类型表达式可扩展（数组/map/自定义类型等）。
-	fieldTokens, err := splitStructFieldTokens(fields)
-	if err != nil {
-		return pkg_errors.WithMessagef(err, "fields (%s) invalid", fields)
+	var fieldTokens []string
+	fields = strings.TrimSpace(fields)
+	if fields != "" {
+		// 字段定义字符串以前通过正则限制类型表达式形态，但会阻碍 map 等复杂类型。
+		// 这里改为分割 + 逐字段解析，保证类型表达式可扩展（数组/map/自定义类型等）。
+		var err error
+		if fieldTokens, err = splitStructFieldTokens(fields); err != nil {
+			return pkg_errors.WithMessagef(err, "fields (%s) invalid", fields)
+		}
+	} else if len(inherited) == 0 {
+		// 子结构体可以仅继承父结构体的字段
+		return fmt.Errorf("fields empty")
 	}
-	if len(fieldTokens) > gexcels.StructMaxField {
-		return fmt.Errorf("field number %d exceed limit, max %d", len(fieldTokens), gexcels.StructMaxField)
+	if n := len(inherited) + len(fieldTokens); n > gexcels.StructMaxField {
+		return fmt.Errorf("field number %d exceed limit, max %d", n, gexcels.StructMaxField)
 	}
 
-	sd.Fields = make([]*gexcels.Field, 0, len(fieldTokens))
-	sd.FieldByName = make(map[string]*gexcels.Field, len(fieldTokens))
+	// 继承的字段排在前面, 与父结构体共享字段定义
+	sd.Fields = make([]*gexcels.Field, 0, len(inherited)+len(fieldTokens))
+	sd.FieldByName = make(map[string]*gexcels.Field, len(inherited)+len(fieldTokens))
+	for _, fd := range inherited {
+		sd.AddField(fd)
+	}
 	for _, token := range fieldTokens {
 		fieldDef, err := p.parseStructFieldToken(token)
 		if err != nil {
 			return pkg_errors.WithMessagef(err, "field[%s]", token)
 		}
+		if sd.Parent != nil && sd.Parent.GetFieldByName(fieldDef.Name) != nil {
+			return fmt.Errorf("field name %s conflicts with parent struct %s", fieldDef.Name, sd.Parent.Name)
+		}
 		if !sd.AddField(fieldDef) {
 			return errFieldNameDuplicate(fieldDef.Name)
 		}
@@ -175,12 +246,13 @@ func (p *Parser) parseStructFields(sd *Struct, fields string) error {
 	return nil
 }
 
-// splitStructFieldTokens 按逗号分割字段定义，但需要跳过 desc 的引号内容。
-// e.g: A:int32:"a,b",MM:map[string]int32:"m"
+// splitStructFieldTokens 按逗号分割字段定义，但需要跳过 desc 的引号内容以及类型中的方括号内容。
+// e.g: A:int32:"a,b",MM:map[string]int32:"m",T:tuple[int32,string]
 func splitStructFieldTokens(s string) ([]string, error) {
 	var (
 		out     []string
 		inQuote bool
+		depth   int
 		start   int
 	)
 	for i := 0; i < len(s); i++ {
@@ -188,7 +260,16 @@ func splitStructFieldTokens(s string) ([]string, error) {
 			inQuote = !inQuote
 			continue
 		}
-		if s[i] == ',' && !inQuote {
+		if inQuote {
+			continue
+		}
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if s[i] == ',' && depth == 0 {
 			token := strings.TrimSpace(s[start:i])
 			if token == "" {
 				return nil, errStructFieldDefineInvalid
@@ -288,11 +369,38 @@ func (p *Parser) parseStructRule(sd *Struct, ruleStr string) error {
 			return errFieldRuleDefineInvalid(rule)
 		}
 
+		var err error
 		switch strings.ToUpper(matches[1]) {
 		case gexcels.FRNLink:
-			return p.parseStructRuleLink(sd, matches[2])
+			err = p.parseStructRuleLink(sd, matches[2])
+		case gexcels.FRNRange, gexcels.FRNRegex, gexcels.FRNLen, gexcels.FRNNotEmpty, gexcels.FRNNullable, gexcels.FRNDefault:
+			err = p.parseStructRuleField(sd, matches[1], matches[2])
 		default:
-			return errFieldRuleInvalid(matches[1])
+			// 自定义规则作用于结构体字段值, 其余内置规则仅作用于配置表字段
+			if gexcels.IsBuiltinFieldRule(matches[1]) {
+				err = errFieldRuleInvalid(matches[1])
+			} else {
+				err = p.parseStructRuleField(sd, matches[1], matches[2])
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, fd := range sd.OwnFields() {
+		if fd.GetFRNullable() != nil && fd.GetFRLink() == nil {
+			return fmt.Errorf("%w on field[%s]", errFieldRuleWithout(gexcels.FRNNullable, gexcels.FRNLink), fd.Name)
+		}
+		def, ok, err := p.parseFieldDefault(fd)
+		if err != nil {
+			return pkg_errors.WithMessagef(err, "field[%s]", fd.Name)
+		}
+		if ok {
+			if sd.defaults == nil {
+				sd.defaults = make(map[string]any)
+			}
+			sd.defaults[fd.Name] = def
 		}
 	}
 
@@ -310,6 +418,9 @@ func (p *Parser) parseStructRuleLink(sd *Struct, value string) error {
 	if localFd == nil {
 		return fmt.Errorf("FRLink local field[%s] not found", localFieldName)
 	}
+	if err := checkStructOwnField(sd, localFieldName); err != nil {
+		return pkg_errors.WithMessage(err, "FRLink")
+	}
 
 	rule := &gexcels.FRLink{}
 	if err := rule.ParseValue(values[2]); err != nil {
@@ -320,3 +431,52 @@ func (p *Parser) parseStructRuleLink(sd *Struct, value string) error {
 	}
 	return nil
 }
+
+// structRuleFieldRegexp 结构体字段规则匹配正则表达式
+// 格式：LocalField[,<RuleValue>]
+var structRuleFieldRegexp = regexp.MustCompile(`^(` + gexcels.NamePattern + `)(?:,(.+))?$`)
+
+// parseStructRuleField 解析作用于结构体字段值的规则
+func (p *Parser) parseStructRuleField(sd *Struct, name, value string) error {
+	values := structRuleFieldRegexp.FindStringSubmatch(value)
+	if len(values) != 3 {
+		return fmt.Errorf("%s value (%s) invalid", name, value)
+	}
+	localFieldName := values[1]
+	localFd := sd.GetFieldByName(localFieldName)
+	if localFd == nil {
+		return fmt.Errorf("%s local field[%s] not found", name, localFieldName)
+	}
+	if err := checkStructOwnField(sd, localFieldName); err != nil {
+		return pkg_errors.WithMessage(err, name)
+	}
+
+	ruleStr := name
+	if values[2] != "" {
+		ruleStr += gexcels.FRNameValueSep + values[2]
+	}
+	rule, err := gexcels.ParseFieldRule(ruleStr)
+	if err != nil {
+		return err
+	}
+	if err := checkFieldRuleType(localFd, rule); err != nil {
+		return err
+	}
+	// 结构体值不属于配置表条目, 自定义规则仅能校验值
+	if _, ok := rule.(gexcels.FRValueValidator); !ok && !gexcels.IsBuiltinFieldRule(rule.FRName()) {
+		return fmt.Errorf("%s on field[%s] not value validator", rule.FRName(), localFieldName)
+	}
+	if !localFd.AddRule(rule) {
+		return fmt.Errorf("multiple %s on field[%s]", rule.FRName(), localFieldName)
+	}
+	return nil
+}
+
+// checkStructOwnField 检查字段是否为结构体自身定义的字段.
+// 继承的字段与父结构体共享, 其规则只能在父结构体中定义.
+func checkStructOwnField(sd *Struct, name string) error {
+	if sd.Parent != nil && sd.Parent.GetFieldByName(name) != nil {
+		return fmt.Errorf("local field[%s] inherited from struct %s", name, sd.Parent.Name)
+	}
+	return nil
+}
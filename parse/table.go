@@ -3,15 +3,19 @@ package parse
 import (
 	"errors"
 	"fmt"
+	"math"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/godyy/gexcels"
 	"github.com/godyy/gexcels/internal/log"
 	pkg_errors "github.com/pkg/errors"
-	"github.com/tealeg/xlsx/v3"
 )
 
 // tableTagRegexp 配置表内标签匹配正则表达式
@@ -19,11 +23,20 @@ var tableTagRegexp = regexp.MustCompile(gexcels.TagPattern + `(?:` + gexcels.Tag
 
 // Table 配置表定义
 type Table struct {
-	*gexcels.Table                            // 基础数据
-	Entries        []gexcels.TableEntry       // for normal
-	entryByID      map[any]gexcels.TableEntry // for normal
-	entryByName    map[string]any             // for global
-	uniqueValues   map[string]bool            // 唯一键值存在映射 [fieldName+fieldValue]
+	*gexcels.Table                                       // 基础数据
+	Path           string                                // 数据源路径
+	Sheet          string                                // sheet名
+	Entries        []gexcels.TableEntry                  // for normal
+	entryRows      []int                                 // for normal, 条目所在行号(从1开始), 与 Entries 一一对应
+	entryByID      map[any]gexcels.TableEntry            // for normal
+	entryByName    map[string]any                        // for global
+	rowByName      map[string]int                        // for global, 字段所在行号(从1开始)
+	lints          []*Diagnostic                         // 单元格lint, 位置相对于sheet
+	uniqueValues   map[string]bool                       // 唯一键值存在映射 [fieldName+fieldValue]
+	entryByUnique  map[string]map[any]gexcels.TableEntry // 唯一字段条目索引, 按需建立
+	checks         []*tableCheck                         // 条目检查
+	excludedFields map[string]bool                       // 被 tag 过滤的字段
+	defaults       map[string]any                        // 字段默认值
 
 	links         []*TableLink         // 外链规则
 	CompositeKeys []*TableCompositeKey // 组合键
@@ -36,12 +49,62 @@ func newTable(name, desc string, isGlobal bool) *Table {
 	}
 }
 
-// addEntry 添加条目
-func (td *Table) addEntry(id any, entry gexcels.TableEntry) {
+// addEntry 添加条目, row 为条目所在行号
+func (td *Table) addEntry(id any, entry gexcels.TableEntry, row int) {
 	td.Entries = append(td.Entries, entry)
+	td.entryRows = append(td.entryRows, row)
 	td.entryByID[id] = entry
 }
 
+// setDefault 设置字段默认值
+func (td *Table) setDefault(fieldName string, value any) {
+	if td.defaults == nil {
+		td.defaults = make(map[string]any)
+	}
+	td.defaults[fieldName] = value
+}
+
+// GetEntryRow 获取第 index 个条目所在行号(从1开始), 未知时返回0
+func (td *Table) GetEntryRow(index int) int {
+	if index < 0 || index >= len(td.entryRows) {
+		return 0
+	}
+	return td.entryRows[index]
+}
+
+// GetFieldRow 获取全局配置表字段所在行号(从1开始), 未知时返回0
+func (td *Table) GetFieldRow(name string) int {
+	return td.rowByName[name]
+}
+
+// restoreEntries 恢复已解析的条目, 并重建条目相关的索引
+func (td *Table) restoreEntries(entries []gexcels.TableEntry, rows []int) error {
+	if len(rows) != len(entries) {
+		return fmt.Errorf("entry rows not match")
+	}
+	td.Entries = make([]gexcels.TableEntry, 0, len(entries))
+	td.entryRows = make([]int, 0, len(entries))
+	td.entryByID = make(map[any]gexcels.TableEntry, len(entries))
+	for i, entry := range entries {
+		id := entry[gexcels.TableFieldIDName]
+		if id == nil || td.hasEntry(id) {
+			return fmt.Errorf("entry %s=%v invalid", gexcels.TableFieldIDName, id)
+		}
+		for _, fd := range td.Fields {
+			if fd.Col != gexcels.TableColFieldID && fd.Unique() {
+				if !td.addUniqueValue(fd.Name, entry[fd.Name]) {
+					return fmt.Errorf("entry %s=%v %s duplicate", gexcels.TableFieldIDName, id, fd.Name)
+				}
+			}
+		}
+		if err := td.addCompositeKeyValue(entry); err != nil {
+			return err
+		}
+		td.addEntry(id, entry, rows[i])
+	}
+	return nil
+}
+
 // hasEntry 是否存在条目
 func (td *Table) hasEntry(id any) bool {
 	_, ok := td.entryByID[id]
@@ -87,14 +150,32 @@ func (td *Table) hasUniqueValue(fieldName string, value any) bool {
 	return td.uniqueValues[key]
 }
 
+// getEntryByUnique 获取唯一字段值为value的条目, 首次调用时建立字段索引
+func (td *Table) getEntryByUnique(fieldName string, value any) gexcels.TableEntry {
+	entries, ok := td.entryByUnique[fieldName]
+	if !ok {
+		entries = make(map[any]gexcels.TableEntry, len(td.Entries))
+		for _, entry := range td.Entries {
+			if v := entry[fieldName]; v != nil {
+				entries[v] = entry
+			}
+		}
+		if td.entryByUnique == nil {
+			td.entryByUnique = make(map[string]map[any]gexcels.TableEntry)
+		}
+		td.entryByUnique[fieldName] = entries
+	}
+	return entries[value]
+}
+
 // addTable 添加表
 func (p *Parser) addTable(td *Table) {
 	p.Tables = append(p.Tables, td)
 	p.tableByName[td.Name] = td
 }
 
-// getTableByName 根据名称获取表
-func (p *Parser) getTableByName(name string) *Table {
+// GetTableByName 根据名称获取表
+func (p *Parser) GetTableByName(name string) *Table {
 	return p.tableByName[name]
 }
 
@@ -105,127 +186,321 @@ func (p *Parser) hasTable(name string) bool {
 }
 
 // parseTables 解析配置表
+// 各配置表文件之间相互独立, 按 Options.Concurrency 并发解析, 再按文件顺序合并结果,
+// 保证 Tables 顺序、重名检测以及错误信息与顺序解析一致.
 func (p *Parser) parseTables(files []string) error {
-	for _, file := range files {
-		if err := p.parseTableFile(file); err != nil {
+	results := make([]*tableFileResult, len(files))
+
+	concurrency := p.options.getConcurrency()
+	if concurrency > len(files) {
+		concurrency = len(files)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		indexes   = make(chan int)
+		failedIdx atomic.Int64 // 已知出错的最小文件索引, 其后的文件无需再解析
+	)
+	failedIdx.Store(math.MaxInt64)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				if int64(idx) > failedIdx.Load() {
+					results[idx] = &tableFileResult{}
+					continue
+				}
+				results[idx] = p.parseTableFile(files[idx])
+				if results[idx].failed() && !p.options.CollectErrors {
+					for {
+						failed := failedIdx.Load()
+						if int64(idx) >= failed || failedIdx.CompareAndSwap(failed, int64(idx)) {
+							break
+						}
+					}
+				}
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var errs Errors
+	for i, file := range files {
+		if err := p.addTablesOfFile(file, results[i]); err != nil {
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+		}
+	}
+	return errs.err()
+}
+
+// tableSheetResult 配置表sheet解析结果
+type tableSheetResult struct {
+	sheet string // sheet名
+	table *Table // 解析出的配置表, 收集错误模式下出错时也不为空
+	err   error  // 解析错误
+}
+
+// tableFileResult 配置表文件解析结果
+type tableFileResult struct {
+	sheets []*tableSheetResult // 各sheet解析结果
+	err    error               // 文件错误
+	cached bool                // 是否由缓存恢复
+}
+
+// failed 是否存在错误
+func (r *tableFileResult) failed() bool {
+	if r.err != nil {
+		return true
+	}
+	for _, sr := range r.sheets {
+		if sr.err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// addTablesOfFile 添加配置表文件的解析结果
+func (p *Parser) addTablesOfFile(path string, result *tableFileResult) error {
+	var errs Errors
+	for _, sr := range result.sheets {
+		if sr.table != nil {
+			if p.hasTable(sr.table.Name) {
+				if err := p.collectError(&errs, fmt.Errorf("[%s][%s]: table name duplicate", path, sr.sheet)); err != nil {
+					return err
+				}
+			} else {
+				// 出错的配置表同样加入, 以便继续检查其它配置表到它的链接
+				p.addTable(sr.table)
+				if sr.err == nil && result.cached {
+					log.PrintfGreen("[%s][%s] parsed (cached)", path, sr.sheet)
+				} else if sr.err == nil {
+					log.PrintfGreen("[%s][%s] parsed", path, sr.sheet)
+				}
+			}
+		}
+		if sr.err != nil {
+			if err := p.collectError(&errs, sr.err); err != nil {
+				return err
+			}
+		}
+	}
+	if result.err != nil {
+		if err := p.collectError(&errs, result.err); err != nil {
 			return err
 		}
 	}
-	return nil
+	return errs.err()
 }
 
 // parseTableFile 解析配置表文件
-func (p *Parser) parseTableFile(path string) error {
-	file, err := xlsx.OpenFile(path)
+func (p *Parser) parseTableFile(path string) *tableFileResult {
+	result := &tableFileResult{}
+	data, err := p.fsys.readFile(path)
 	if err != nil {
-		return err
+		result.err = err
+		return result
+	}
+
+	hash := p.cache.hash(data)
+	if cf := p.cache.load(path, hash, true); cf != nil {
+		// 缓存恢复失败时重新解析
+		if cached := p.restoreTableFile(path, cf); !cached.failed() {
+			return cached
+		}
+	}
+
+	file, err := decodeWorkbook(filepath.Base(path), data)
+	if err != nil {
+		result.err = withDiagnostic(withMessagef(err, "[%s]", path), Diagnostic{Path: path})
+		return result
 	}
-	for _, sheet := range file.Sheets {
+
+	var cacheSheets []*cacheSheet
+	for _, sheet := range file.Sheets() {
 		td, err := p.parseTableOfSheet(sheet)
+		if td != nil {
+			td.Path, td.Sheet = path, sheet.Name()
+		}
+		if err == nil && !p.options.OnlyFields {
+			err = withDiagnostic(p.validateTable(td, false), Diagnostic{Table: td.Name})
+		}
 		if err != nil {
 			if errors.Is(err, errSheetNameInvalid) {
 				continue
 			}
-			return pkg_errors.WithMessagef(err, "[%s][%s]", path, sheet.Name)
+			result.sheets = append(result.sheets, &tableSheetResult{
+				sheet: sheet.Name(),
+				table: td,
+				err: withDiagnostic(
+					withMessagef(err, "[%s][%s]", path, sheet.Name()),
+					Diagnostic{Path: path, Sheet: sheet.Name()},
+				),
+			})
+			if !p.options.CollectErrors {
+				return result
+			}
+			continue
 		}
-		if p.hasTable(td.Name) {
-			return fmt.Errorf("[%s][%s]: table name duplicate", path, sheet.Name)
+		result.sheets = append(result.sheets, &tableSheetResult{sheet: sheet.Name(), table: td})
+
+		if p.cache != nil {
+			cs, err := newTableCacheSheet(sheet, td)
+			if err != nil {
+				result.err = pkg_errors.WithMessagef(err, "[%s][%s] cache", path, sheet.Name())
+				return result
+			}
+			cacheSheets = append(cacheSheets, cs)
 		}
-		p.addTable(td)
-		log.PrintfGreen("[%s][%s] parsed", path, sheet.Name)
 	}
-	return nil
+
+	if !result.failed() {
+		p.cache.store(path, hash, true, cacheSheets)
+	}
+	return result
 }
 
 // tableSheetNameRegexp 配置表sheet名匹配正则表达式
 var tableSheetNameRegexp = regexp.MustCompile(`^(.*)\|(` + gexcels.NamePattern + `)$`)
 
 // parseTableOfSheet 解析sheet中定义的配置表
-func (p *Parser) parseTableOfSheet(sheet *xlsx.Sheet) (*Table, error) {
-	nameMatches := tableSheetNameRegexp.FindStringSubmatch(strings.TrimSpace(sheet.Name))
+func (p *Parser) parseTableOfSheet(sheet Sheet) (*Table, error) {
+	nameMatches := tableSheetNameRegexp.FindStringSubmatch(strings.TrimSpace(sheet.Name()))
 	if len(nameMatches) <= 0 {
 		return nil, errSheetNameInvalid
 	}
 
+	var (
+		td  *Table
+		err error
+	)
 	if strings.HasPrefix(nameMatches[2], gexcels.GlobalTableNamePrefix) {
-		return p.parseGlobalTable(sheet, nameMatches[2], nameMatches[1])
+		td, err = p.parseGlobalTable(sheet, nameMatches[2], nameMatches[1])
 	} else {
-		return p.parseTable(sheet, nameMatches[2], nameMatches[1])
+		td, err = p.parseTable(sheet, nameMatches[2], nameMatches[1])
 	}
+	return td, withDiagnostic(err, Diagnostic{Table: nameMatches[2]})
 }
 
 // parseTable 解析sheet中的配置表内容到td指定的配置表中
-func (p *Parser) parseTable(sheet *xlsx.Sheet, name, desc string) (*Table, error) {
-	if sheet.MaxRow < gexcels.TableRowFirstEntry || sheet.MaxCol < 1 {
+func (p *Parser) parseTable(sheet Sheet, name, desc string) (*Table, error) {
+	if sheet.MaxRow() < gexcels.TableRowFirstEntry || sheet.MaxCol() < 1 {
 		return nil, errSheetRowsOrColsNotMatch
 	}
 
 	td := newTable(name, desc, false)
 
+	var errs Errors
+
 	// 解析字段
 	if err := p.parseTableFields(td, sheet); err != nil {
-		return nil, pkg_errors.WithMessage(err, " fields")
+		if err := p.collectError(&errs, withMessage(err, " fields")); err != nil {
+			return nil, err
+		}
+		// ID 字段无效时无法解析条目
+		if !td.HasField(gexcels.TableFieldIDName) {
+			return td, errs
+		}
+	} else if err := p.compileTableChecks(td); err != nil {
+		if err := p.collectError(&errs, withMessage(err, " fields")); err != nil {
+			return nil, err
+		}
 	}
 
 	// 解析条目
 	if err := p.parseTableEntries(td, sheet); err != nil {
-		return nil, pkg_errors.WithMessage(err, " entries")
+		if err := p.collectError(&errs, withMessage(err, " entries")); err != nil {
+			return nil, err
+		}
 	}
 
-	return td, nil
+	return td, errs.err()
 }
 
 // parseTableFields 解析sheet中的字段定义到td指定的配置表中
-func (p *Parser) parseTableFields(td *Table, sheet *xlsx.Sheet) error {
-	td.Fields = make([]*gexcels.TableField, 0, sheet.MaxCol)
-	td.FieldByName = make(map[string]*gexcels.TableField, sheet.MaxCol)
+func (p *Parser) parseTableFields(td *Table, sheet Sheet) error {
+	td.Fields = make([]*gexcels.TableField, 0, sheet.MaxCol())
+	td.FieldByName = make(map[string]*gexcels.TableField, sheet.MaxCol())
 
-	for i := 0; i < sheet.MaxCol; i++ {
+	var errs Errors
+	for i := 0; i < sheet.MaxCol(); i++ {
 		if i > 0 {
 			tag, err := getSheetValue(sheet, gexcels.TableRowFieldTag, i, true)
 			if err != nil {
-				return pkg_errors.WithMessagef(err, " get coll[%d] tag cell", i)
+				return withDiagnostic(
+					pkg_errors.WithMessagef(err, " get coll[%d] tag cell", i),
+					Diagnostic{Row: gexcels.TableRowFieldTag + 1, Col: i + 1},
+				)
 			}
 			if ok, valid := p.checkTag(tag); !valid {
-				return fmt.Errorf("col[%d] tag(%s) invalid", i, tag)
+				err := withDiagnostic(
+					fmt.Errorf("col[%d] tag(%s) invalid", i, tag),
+					Diagnostic{Row: gexcels.TableRowFieldTag + 1, Col: i + 1},
+				)
+				if err := p.collectError(&errs, err); err != nil {
+					return err
+				}
+				continue
 			} else if !ok {
+				if name, err := getSheetValue(sheet, gexcels.TableRowFieldName, i, true); err == nil {
+					td.excludeField(name)
+				}
 				continue
 			}
 		}
 
 		_, err := p.parseTableField(td, sheet, i)
 		if err != nil {
-			return pkg_errors.WithMessagef(err, "coll[%d]", i)
+			err = withDiagnostic(pkg_errors.WithMessagef(err, "coll[%d]", i), Diagnostic{Col: i + 1})
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+			// ID 字段无效时, 后续字段无法正确解析
+			if i == gexcels.TableColFieldID {
+				break
+			}
 		}
 	}
 
-	return nil
+	return errs.err()
 }
 
 // parseTableField 解析sheet中col列定义的字段到td指定的配置表中
-func (p *Parser) parseTableField(td *Table, sheet *xlsx.Sheet, col int) (*gexcels.TableField, error) {
+func (p *Parser) parseTableField(td *Table, sheet Sheet, col int) (*gexcels.TableField, error) {
+	nameDiag := Diagnostic{Row: gexcels.TableRowFieldName + 1}
 	fieldName, err := getSheetValue(sheet, gexcels.TableRowFieldName, col, true)
 	if err != nil {
-		return nil, pkg_errors.WithMessage(err, "get name cell")
+		return nil, withDiagnostic(pkg_errors.WithMessage(err, "get name cell"), nameDiag)
 	}
 	if fieldName == "" {
 		return nil, nil
 	}
 	if !gexcels.MatchName(fieldName) {
-		return nil, errFieldNameInvalid(fieldName)
+		return nil, withDiagnostic(errFieldNameInvalid(fieldName), nameDiag)
 	}
 	if td.HasField(fieldName) {
-		return nil, errFieldNameDuplicate(fieldName)
+		return nil, withDiagnostic(errFieldNameDuplicate(fieldName), nameDiag)
 	}
 
 	fieldDesc, err := getSheetValue(sheet, gexcels.TableRowFieldDesc, col)
 	if err != nil {
-		return nil, pkg_errors.WithMessage(err, "get desc cell")
+		return nil, withDiagnostic(
+			pkg_errors.WithMessage(err, "get desc cell"),
+			Diagnostic{Row: gexcels.TableRowFieldDesc + 1, Field: fieldName},
+		)
 	}
 
+	typeDiag := Diagnostic{Row: gexcels.TableRowFieldType + 1, Field: fieldName}
 	fieldType, err := getSheetValue(sheet, gexcels.TableRowFieldType, col, true)
 	if err != nil {
-		return nil, pkg_errors.WithMessage(err, "get type cell")
+		return nil, withDiagnostic(pkg_errors.WithMessage(err, "get type cell"), typeDiag)
 	}
 	if fieldType == "" {
 		return nil, nil
@@ -233,7 +508,7 @@ func (p *Parser) parseTableField(td *Table, sheet *xlsx.Sheet, col int) (*gexcel
 
 	fieldTypeInfo, err := p.parseFieldTypeInfo(fieldType)
 	if err != nil {
-		return nil, pkg_errors.WithMessagef(err, "field type (%s)", fieldType)
+		return nil, withDiagnostic(pkg_errors.WithMessagef(err, "field type (%s)", fieldType), typeDiag)
 	}
 
 	fd := gexcels.NewTableField(
@@ -243,10 +518,16 @@ func (p *Parser) parseTableField(td *Table, sheet *xlsx.Sheet, col int) (*gexcel
 
 	if col == gexcels.TableColFieldID {
 		if fd.Name != gexcels.TableFieldIDName {
-			return nil, errFirstFieldMustID
+			return nil, withDiagnostic(errFirstFieldMustID, nameDiag)
 		}
 		if !fd.Type.Primitive() {
-			return nil, errIDNonPrimitive
+			return nil, withDiagnostic(errIDNonPrimitive, typeDiag)
+		}
+		if fd.Optional {
+			return nil, withDiagnostic(errIDOptional, typeDiag)
+		}
+		if fd.Type == gexcels.FTText {
+			return nil, withDiagnostic(errIDText, typeDiag)
 		}
 		fd.AddRule(gexcels.NewFRUnique())
 	}
@@ -254,12 +535,13 @@ func (p *Parser) parseTableField(td *Table, sheet *xlsx.Sheet, col int) (*gexcel
 	td.AddField(fd)
 
 	if col != gexcels.TableColFieldID {
+		ruleDiag := Diagnostic{Row: gexcels.TableRowFieldRule + 1, Field: fieldName}
 		fieldRule, err := getSheetValue(sheet, gexcels.TableRowFieldRule, col, true)
 		if err != nil {
-			return nil, pkg_errors.WithMessage(err, "get fieldRule cell")
+			return nil, withDiagnostic(pkg_errors.WithMessage(err, "get fieldRule cell"), ruleDiag)
 		}
 		if err = p.parseTableFieldRules(td, fd, fieldRule); err != nil {
-			return nil, err
+			return nil, withDiagnostic(err, ruleDiag)
 		}
 		var fieldPath []string
 		var links []*TableLink
@@ -277,90 +559,144 @@ func (p *Parser) parseTableFieldRules(td *Table, fd *gexcels.TableField, s strin
 	}
 
 	rules := strings.Split(s, p.options.FieldRuleSep)
-	for _, rule := range rules {
-		rule = strings.TrimSpace(rule)
+	for i := 0; i < len(rules); i++ {
+		rule := strings.TrimSpace(rules[i])
+		// CHECK 表达式可能包含分隔符, 取单元格剩余的全部内容
+		if strings.HasPrefix(strings.ToUpper(rule), gexcels.FRNCheck+gexcels.FRNameValueSep) {
+			rule = strings.TrimSpace(strings.Join(rules[i:], p.options.FieldRuleSep))
+			i = len(rules)
+		}
 		fr, err := gexcels.ParseFieldRule(rule)
 		if err != nil {
 			return err
 		}
-
-		if !fd.AddRule(fr) {
-			return errFieldRuleMultiple(fr.FRName())
+		if err := p.parseTableFieldRule(td, fd, fr); err != nil {
+			return withDiagnostic(err, Diagnostic{Rule: fr.FRName()})
 		}
+	}
 
-		switch r := fr.(type) {
-		case *gexcels.FRUnique:
-			if td.IsGlobal {
-				return errFieldRuleOnGlobalTable(fr.FRName())
-			}
-			if !fd.Type.Primitive() {
-				return errFieldRuleOnNonPrimitiveField(fr.FRName())
-			}
-		case *gexcels.FRLink:
-			_ = r
-		case *gexcels.FRCompositeKey:
-			if td.IsGlobal {
-				return errFieldRuleOnGlobalTable(fr.FRName())
-			}
-			if !fd.Type.Primitive() {
-				return errFieldRuleOnNonPrimitiveField(fr.FRName())
-			}
-			if !td.addCompositeKey(r.KeyName, r.Index, fd.Name) {
-				return fmt.Errorf("composite-key %s keyIndex %d duplicate", r.KeyName, r.Index)
-			}
-		case *gexcels.FRGroup:
-			if td.IsGlobal {
-				return errFieldRuleOnGlobalTable(fr.FRName())
-			}
-			if !fd.Type.Primitive() {
-				return errFieldRuleOnNonPrimitiveField(fr.FRName())
-			}
-			if !td.addGroup(r.GroupName, r.Index, fd.Name) {
-				return fmt.Errorf("group %s index %d duplicate", r.GroupName, r.Index)
-			}
+	if fd.GetFRNullable() != nil && fd.GetFRLink() == nil {
+		return withDiagnostic(errFieldRuleWithout(gexcels.FRNNullable, gexcels.FRNLink), Diagnostic{Rule: gexcels.FRNNullable})
+	}
+
+	if def, ok, err := p.parseFieldDefault(fd.Field); err != nil {
+		return withDiagnostic(err, Diagnostic{Rule: gexcels.FRNDefault})
+	} else if ok {
+		td.setDefault(fd.Name, def)
+	}
+
+	return nil
+}
+
+// parseTableFieldValue 解析配置表字段值, 单元格未填写时使用字段默认值
+func (p *Parser) parseTableFieldValue(td *Table, fd *gexcels.Field, s string) (any, error) {
+	if def, ok := td.defaults[fd.Name]; ok && strings.TrimSpace(s) == "" {
+		return def, nil
+	}
+	return p.parseFieldValue(fd, s)
+}
+
+// parseTableFieldRule 将规则 fr 应用到配置表字段
+func (p *Parser) parseTableFieldRule(td *Table, fd *gexcels.TableField, fr gexcels.FieldRule) error {
+	if err := checkFieldRuleType(fd.Field, fr); err != nil {
+		return err
+	}
+
+	if !fd.AddRule(fr) {
+		return errFieldRuleMultiple(fr.FRName())
+	}
+
+	switch r := fr.(type) {
+	case *gexcels.FRUnique:
+		if td.IsGlobal {
+			return errFieldRuleOnGlobalTable(fr.FRName())
+		}
+		if !fd.Type.Primitive() {
+			return errFieldRuleOnNonPrimitiveField(fr.FRName())
+		}
+		if fd.Optional {
+			return errFieldRuleOnOptionalField(fr.FRName())
+		}
+		if fd.Type == gexcels.FTText {
+			return errFieldRuleOnTextField(fr.FRName())
+		}
+	case *gexcels.FRLink:
+		_ = r
+	case *gexcels.FRCompositeKey:
+		if td.IsGlobal {
+			return errFieldRuleOnGlobalTable(fr.FRName())
+		}
+		if !fd.Type.Primitive() {
+			return errFieldRuleOnNonPrimitiveField(fr.FRName())
+		}
+		if fd.Optional {
+			return errFieldRuleOnOptionalField(fr.FRName())
+		}
+		if fd.Type == gexcels.FTText {
+			return errFieldRuleOnTextField(fr.FRName())
 		}
+		if !td.addCompositeKey(r.KeyName, r.Index, fd.Name) {
+			return fmt.Errorf("composite-key %s keyIndex %d duplicate", r.KeyName, r.Index)
+		}
+	case *gexcels.FRGroup:
+		if td.IsGlobal {
+			return errFieldRuleOnGlobalTable(fr.FRName())
+		}
+		if !fd.Type.Primitive() {
+			return errFieldRuleOnNonPrimitiveField(fr.FRName())
+		}
+		if fd.Optional {
+			return errFieldRuleOnOptionalField(fr.FRName())
+		}
+		if fd.Type == gexcels.FTText {
+			return errFieldRuleOnTextField(fr.FRName())
+		}
+		if !td.addGroup(r.GroupName, r.Index, fd.Name) {
+			return fmt.Errorf("group %s index %d duplicate", r.GroupName, r.Index)
+		}
+	case *gexcels.FRCheck:
+		td.addCheck(fd, r)
 	}
 
 	return nil
 }
 
 // parseTableEntries 解析sheet中定义的条目数据到td指定的配置表
-func (p *Parser) parseTableEntries(td *Table, sheet *xlsx.Sheet) error {
+func (p *Parser) parseTableEntries(td *Table, sheet Sheet) error {
 	if p.options.OnlyFields {
 		return nil
 	}
 
-	entryCount := sheet.MaxRow - gexcels.TableRowFirstEntry
+	entryCount := sheet.MaxRow() - gexcels.TableRowFirstEntry
 	if entryCount <= 0 {
 		return nil
 	}
 
 	td.Entries = make([]gexcels.TableEntry, 0, entryCount)
+	td.entryRows = make([]int, 0, entryCount)
 	td.entryByID = make(map[any]gexcels.TableEntry, entryCount)
 
 	var (
-		row *xlsx.Row
-		fd  *gexcels.TableField
-		id  any
-		val any
-		err error
+		fd   *gexcels.TableField
+		id   any
+		val  any
+		err  error
+		errs Errors
 	)
 
-	for i := gexcels.TableRowFirstEntry; i < sheet.MaxRow; i++ {
-		row, err = sheet.Row(i)
-		if err != nil {
-			return pkg_errors.WithMessagef(err, "get row[%d]", i+1)
-		}
-
+	for i := gexcels.TableRowFirstEntry; i < sheet.MaxRow(); i++ {
+		row := sheetRow{sheet: sheet, row: i}
 		if isRowComment(row) {
 			continue
 		}
 
 		entry := make(gexcels.TableEntry, len(td.Fields))
+		rowDiag := Diagnostic{Row: i + 1}
 		skip := false
+		failed := false
 		for k := 0; k < len(td.Fields); k++ {
 			fd = td.Fields[k]
-			value := row.GetCell(fd.Col).Value
+			value := row.value(fd.Col)
 
 			if fd.Col == gexcels.TableColFieldID {
 				if value == "" {
@@ -369,17 +705,42 @@ func (p *Parser) parseTableEntries(td *Table, sheet *xlsx.Sheet) error {
 				}
 			}
 
-			val, err = p.parseFieldValue(fd.Field, value)
+			cellDiag := Diagnostic{Row: i + 1, Col: fd.Col + 1, Field: fd.Name}
+			val, err = p.parseTableFieldValue(td, fd.Field, value)
 			if err != nil {
-				return pkg_errors.WithMessagef(err, "row[%d] %s={%s}", i+1, fd.Name, value)
+				err = withDiagnostic(pkg_errors.WithMessagef(err, "row[%d] %s={%s}", i+1, fd.Name, value), cellDiag)
+				if err := p.collectError(&errs, err); err != nil {
+					return err
+				}
+				failed = true
+				continue
+			}
+			if err = p.checkFieldValue(fd.Field, val); err != nil {
+				err = withDiagnostic(pkg_errors.WithMessagef(err, "row[%d]", i+1), cellDiag)
+				if err := p.collectError(&errs, err); err != nil {
+					return err
+				}
+				failed = true
+				continue
 			}
 
 			if fd.Col == gexcels.TableColFieldID {
 				if val == nil {
-					return fmt.Errorf("row[%d] %s empty", i+1, fd.Name)
+					err := withDiagnostic(fmt.Errorf("row[%d] %s empty", i+1, fd.Name), cellDiag)
+					if err := p.collectError(&errs, err); err != nil {
+						return err
+					}
+					failed = true
+					continue
 				}
 				if td.hasEntry(val) {
-					return fmt.Errorf("row[%d] %s=%s duplicate", i+1, fd.Name, value)
+					cellDiag.Rule = gexcels.FRNUnique
+					err := withDiagnostic(fmt.Errorf("row[%d] %s=%s duplicate", i+1, fd.Name, value), cellDiag)
+					if err := p.collectError(&errs, err); err != nil {
+						return err
+					}
+					failed = true
+					continue
 				}
 				id = val
 			}
@@ -387,93 +748,146 @@ func (p *Parser) parseTableEntries(td *Table, sheet *xlsx.Sheet) error {
 			if val != nil {
 				entry[fd.Name] = val
 			}
+			td.lintCell(fd.Field, value, val, cellDiag)
 
 			if fd.Col != gexcels.TableColFieldID && fd.Unique() {
 				if !td.addUniqueValue(fd.Name, val) {
-					return fmt.Errorf("row[%d] %s=%s duplicate", i+1, fd.Name, value)
+					cellDiag.Rule = gexcels.FRNUnique
+					err := withDiagnostic(fmt.Errorf("row[%d] %s=%s duplicate", i+1, fd.Name, value), cellDiag)
+					if err := p.collectError(&errs, err); err != nil {
+						return err
+					}
+					failed = true
 				}
 			}
 		}
 
-		if skip {
+		if skip || failed {
 			continue
 		}
 
 		if err := td.addCompositeKeyValue(entry); err != nil {
-			return err
+			rowDiag.Rule = gexcels.FRNCompositeKey
+			if err := p.collectError(&errs, withDiagnostic(err, rowDiag)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := p.validateEntry(td, entry, i+1, false); err != nil {
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := p.checkEntry(td, entry, i+1); err != nil {
+			if err := p.collectError(&errs, err); err != nil {
+				return err
+			}
+			continue
 		}
 
-		td.addEntry(id, entry)
+		td.addEntry(id, entry, i+1)
 	}
 
-	return nil
+	return errs.err()
 }
 
 // parseGlobalTable 解析sheet中的global配置表到td
-func (p *Parser) parseGlobalTable(sheet *xlsx.Sheet, name, desc string) (*Table, error) {
-	if sheet.MaxRow < gexcels.GlobalTableSkipRows || sheet.MaxCol < gexcels.GlobalTableCols {
+func (p *Parser) parseGlobalTable(sheet Sheet, name, desc string) (*Table, error) {
+	if sheet.MaxRow() < gexcels.GlobalTableSkipRows || sheet.MaxCol() < gexcels.GlobalTableCols {
 		return nil, errSheetRowsOrColsNotMatch
 	}
 
 	td := newTable(name, desc, true)
-	fieldCount := sheet.MaxRow - gexcels.GlobalTableSkipRows
+	fieldCount := sheet.MaxRow() - gexcels.GlobalTableSkipRows
 	td.Fields = make([]*gexcels.TableField, 0, fieldCount)
 	td.FieldByName = make(map[string]*gexcels.TableField, fieldCount)
 	td.entryByName = make(map[string]any, fieldCount)
+	td.rowByName = make(map[string]int, fieldCount)
 
-	for i := gexcels.GlobalTableSkipRows; i < sheet.MaxRow; i++ {
-		row, err := sheet.Row(i)
-		if err != nil {
-			return nil, pkg_errors.WithMessagef(err, "get row[%d]", i+1)
-		}
-
+	var errs Errors
+	for i := gexcels.GlobalTableSkipRows; i < sheet.MaxRow(); i++ {
+		row := sheetRow{sheet: sheet, row: i}
 		if isRowComment(row) {
 			continue
 		}
 
-		rowTag := strings.TrimSpace(row.GetCell(gexcels.GlobalTableColFieldTag).Value)
+		rowTag := strings.TrimSpace(row.value(gexcels.GlobalTableColFieldTag))
 		if ok, valid := p.checkTag(rowTag); !valid {
-			return nil, fmt.Errorf("row[%d] tag(%s) invalid", i+1, rowTag)
+			err := withDiagnostic(
+				fmt.Errorf("row[%d] tag(%s) invalid", i+1, rowTag),
+				Diagnostic{Row: i + 1, Col: gexcels.GlobalTableColFieldTag + 1},
+			)
+			if err := p.collectError(&errs, err); err != nil {
+				return nil, err
+			}
+			continue
 		} else if !ok {
+			td.excludeField(strings.TrimSpace(row.value(gexcels.GlobalTableColFieldName)))
 			continue
 		}
 
 		if err := p.parseGlobalTableField(td, row); err != nil {
-			return nil, pkg_errors.WithMessagef(err, "field {%s}", row.GetCell(gexcels.GlobalTableColFieldName).Value)
+			fieldName := strings.TrimSpace(row.value(gexcels.GlobalTableColFieldName))
+			err = withDiagnostic(
+				pkg_errors.WithMessagef(err, "field {%s}", fieldName),
+				Diagnostic{Row: i + 1, Field: fieldName},
+			)
+			if err := p.collectError(&errs, err); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return td, errs
+	}
+	if err := p.compileTableChecks(td); err != nil {
+		return td, err
+	}
+	if !p.options.OnlyFields {
+		if err := p.validateEntry(td, td.entryByName, 0, false); err != nil {
+			return td, err
+		}
+		if err := p.checkEntry(td, td.entryByName, 0); err != nil {
+			return td, err
 		}
 	}
 	return td, nil
 }
 
 // parseGlobalTableField 解析row定义的字段到global配置表td
-func (p *Parser) parseGlobalTableField(td *Table, row *xlsx.Row) error {
-	fieldName := strings.TrimSpace(row.GetCell(gexcels.GlobalTableColFieldName).Value)
+func (p *Parser) parseGlobalTableField(td *Table, row sheetRow) error {
+	fieldName := strings.TrimSpace(row.value(gexcels.GlobalTableColFieldName))
 	if fieldName == "" {
 		return nil
 	}
 
 	if !gexcels.MatchName(fieldName) {
-		return errFieldNameInvalid(fieldName)
+		return withDiagnostic(errFieldNameInvalid(fieldName), Diagnostic{Col: gexcels.GlobalTableColFieldName + 1})
 	}
 
 	if td.HasField(fieldName) {
-		return errFieldNameDuplicate(fieldName)
+		return withDiagnostic(errFieldNameDuplicate(fieldName), Diagnostic{Col: gexcels.GlobalTableColFieldName + 1})
 	}
 
-	fieldTypeInfo, err := p.parseFieldTypeInfo(row.GetCell(gexcels.GlobalTableColFieldType).Value)
+	fieldTypeInfo, err := p.parseFieldTypeInfo(row.value(gexcels.GlobalTableColFieldType))
 	if err != nil {
-		return err
+		return withDiagnostic(err, Diagnostic{Col: gexcels.GlobalTableColFieldType + 1})
 	}
 
 	fd := gexcels.NewTableField(
-		gexcels.NewField(fieldName, row.GetCell(gexcels.GlobalTableColFieldDesc).Value, fieldTypeInfo),
+		gexcels.NewField(fieldName, row.value(gexcels.GlobalTableColFieldDesc), fieldTypeInfo),
 		0,
 	)
 
 	td.AddField(fd)
+	td.rowByName[fd.Name] = row.row + 1
 
-	if err := p.parseTableFieldRules(td, fd, row.GetCell(gexcels.GlobalTableColFieldRule).Value); err != nil {
-		return err
+	if err := p.parseTableFieldRules(td, fd, row.value(gexcels.GlobalTableColFieldRule)); err != nil {
+		return withDiagnostic(err, Diagnostic{Col: gexcels.GlobalTableColFieldRule + 1})
 	}
 
 	var fieldPath []string
@@ -485,10 +899,16 @@ func (p *Parser) parseGlobalTableField(td *Table, row *xlsx.Row) error {
 		return nil
 	}
 
-	val, err := p.parseFieldValue(fd.Field, row.GetCell(gexcels.GlobalTableColFieldValue).Value)
+	value := row.value(gexcels.GlobalTableColFieldValue)
+	valueDiag := Diagnostic{Row: row.row + 1, Col: gexcels.GlobalTableColFieldValue + 1, Field: fd.Name}
+	val, err := p.parseTableFieldValue(td, fd.Field, value)
 	if err != nil {
-		return err
+		return withDiagnostic(err, valueDiag)
+	}
+	if err := p.checkFieldValue(fd.Field, val); err != nil {
+		return withDiagnostic(err, valueDiag)
 	}
+	td.lintCell(fd.Field, value, val, valueDiag)
 	td.addEntryByName(fd.Name, val)
 
 	return nil
@@ -503,12 +923,20 @@ func convertUniqueValue2String(v any) string {
 		return strconv.FormatInt(int64(o), 10)
 	case int64:
 		return strconv.FormatInt(o, 10)
+	case int8, int16:
+		return strconv.FormatInt(reflect.ValueOf(o).Int(), 10)
+	case uint8, uint16, uint32, uint64:
+		return strconv.FormatUint(reflect.ValueOf(o).Uint(), 10)
 	case float32:
 		return strconv.FormatFloat(float64(o), 'f', -1, 32)
 	case float64:
 		return strconv.FormatFloat(o, 'f', -1, 32)
 	case bool:
 		return strconv.FormatBool(o)
+	case time.Time:
+		return o.Format(time.RFC3339Nano)
+	case time.Duration:
+		return o.String()
 	default:
 		panic("invalid unique value type " + reflect.TypeOf(v).String())
 	}
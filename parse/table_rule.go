@@ -1,11 +1,14 @@
 package parse
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/godyy/gexcels"
 )
@@ -15,8 +18,16 @@ type TableLink struct {
 	srcField []string // 源字段，可以指向结构体内部字段
 	dstTable string   // 目标配置表
 	dstField string   // 目标字段，ID或者unique
+	dstKey   []string // 目标组合键字段, 链接组合键时有效
+	srcKey   []string // 组合键字段对应的源字段
+	filter   string   // 目标条目过滤条件
 	kind     tableLinkKind
 	mapLevel int
+	nullable *gexcels.FRNullable // 可空规则, 空值不检查
+
+	filterExpr      checkNode          // 编译后的过滤条件, 检查链接时编译
+	dstCompositeKey *TableCompositeKey // 目标组合键, 检查链接时查找
+	keyFromTable    bool               // 组合键的值是否取自配置表字段
 }
 
 type tableLinkKind uint8
@@ -26,11 +37,14 @@ const (
 	tableLinkKindMapKey
 )
 
-func newTableLink(srcField []string, dstTable, dstField string, kind tableLinkKind, mapLevel int) *TableLink {
+func newTableLink(srcField []string, target *gexcels.FRLinkTarget, kind tableLinkKind, mapLevel int) *TableLink {
 	return &TableLink{
 		srcField: srcField,
-		dstTable: dstTable,
-		dstField: dstField,
+		dstTable: target.TableName,
+		dstField: target.FieldName,
+		dstKey:   target.KeyFields,
+		srcKey:   target.SrcFields,
+		filter:   target.Filter,
 		kind:     kind,
 		mapLevel: mapLevel,
 	}
@@ -146,28 +160,56 @@ func (td *Table) addCompositeKey(keyName string, keyIndex int, fieldName string)
 // addCompositeKeyValue 添加组合键值
 func (td *Table) addCompositeKeyValue(entry gexcels.TableEntry) error {
 	fieldId := td.GetFieldID()
-	var sb strings.Builder
 	for _, ck := range td.CompositeKeys {
 		fields := ck.FieldNames()
+		values := make([]any, len(fields))
 		for i, field := range fields {
-			if i > 0 {
-				sb.WriteString("_")
-			}
-			value := entry[field]
-			if value == nil {
+			values[i] = entry[field]
+			if values[i] == nil {
 				return fmt.Errorf("row[%s=%v] composite-key %s field %s is nil", fieldId.Name, entry[fieldId.Name], ck.Name, field)
 			}
-			sb.WriteString(convertUniqueValue2String(entry[field]))
 		}
-		value := sb.String()
-		if !td.addUniqueValue(ck.Name, value) {
+		if !td.addUniqueValue(ck.Name, compositeKeyValue(values)) {
 			return fmt.Errorf("row[%s=%v] composite-key %s duplicate", fieldId.Name, entry[fieldId.Name], ck.Name)
 		}
-		sb.Reset()
 	}
 	return nil
 }
 
+// compositeKeyValue 组合键值的字符串形式
+func compositeKeyValue(values []any) string {
+	var sb strings.Builder
+	for i, v := range values {
+		if i > 0 {
+			sb.WriteString("_")
+		}
+		sb.WriteString(convertUniqueValue2String(v))
+	}
+	return sb.String()
+}
+
+// getEntryByCompositeKey 获取组合键 ck 的值为 value 的条目, 首次调用时建立索引
+func (td *Table) getEntryByCompositeKey(ck *TableCompositeKey, value string) gexcels.TableEntry {
+	indexName := gexcels.FRNCompositeKey + ":" + ck.Name
+	entries, ok := td.entryByUnique[indexName]
+	if !ok {
+		fields := ck.FieldNames()
+		entries = make(map[any]gexcels.TableEntry, len(td.Entries))
+		for _, entry := range td.Entries {
+			values := make([]any, len(fields))
+			for i, field := range fields {
+				values[i] = entry[field]
+			}
+			entries[compositeKeyValue(values)] = entry
+		}
+		if td.entryByUnique == nil {
+			td.entryByUnique = make(map[string]map[any]gexcels.TableEntry)
+		}
+		td.entryByUnique[indexName] = entries
+	}
+	return entries[value]
+}
+
 // addGroup 添加分组
 func (td *Table) addGroup(group string, index int, fieldName string) bool {
 	var g *TableGroup
@@ -191,15 +233,18 @@ func (p *Parser) getFieldTableLinks(fd *gexcels.Field, fieldPath *[]string, link
 		srcField = append(srcField, *fieldPath...)
 		srcField = append(srcField, fd.Name)
 
+		nullable := fd.GetFRNullable()
 		if ruleLink.Value != nil {
-			*links = append(*links, newTableLink(srcField, ruleLink.Value.TableName, ruleLink.Value.FieldName, tableLinkKindValue, 0))
+			link := newTableLink(srcField, ruleLink.Value, tableLinkKindValue, 0)
+			link.nullable = nullable
+			*links = append(*links, link)
 		}
 		for level, tgt := range ruleLink.Keys {
-			*links = append(*links, newTableLink(srcField, tgt.TableName, tgt.FieldName, tableLinkKindMapKey, level))
+			*links = append(*links, newTableLink(srcField, tgt, tableLinkKindMapKey, level))
 		}
 	}
 
-	if fd.Type == gexcels.FTStruct || fd.Type == gexcels.FTArray || fd.Type == gexcels.FTMap {
+	if fd.Type == gexcels.FTStruct || fd.Type == gexcels.FTUnion || fd.Type == gexcels.FTArray || fd.Type == gexcels.FTMap {
 		*fieldPath = append(*fieldPath, fd.Name)
 		p.getTypeTableLinks(fd.FieldTypeInfo, fieldPath, links)
 		*fieldPath = (*fieldPath)[:len(*fieldPath)-1]
@@ -217,6 +262,17 @@ func (p *Parser) getTypeTableLinks(ti *gexcels.FieldTypeInfo, fieldPath *[]strin
 		for _, fd := range sd.Fields {
 			p.getFieldTableLinks(fd, fieldPath, links)
 		}
+	case gexcels.FTUnion:
+		// 分支名称作为路径的一部分, e.g. Effect.Damage.ItemID
+		u := p.GetUnionByName(ti.GetName())
+		if u == nil {
+			return
+		}
+		for _, c := range u.Cases {
+			*fieldPath = append(*fieldPath, c.Name)
+			p.getTypeTableLinks(gexcels.NewStructFieldTypeInfo(c.Struct), fieldPath, links)
+			*fieldPath = (*fieldPath)[:len(*fieldPath)-1]
+		}
 	case gexcels.FTArray:
 		p.getTypeTableLinks(ti.GetElementType(), fieldPath, links)
 	case gexcels.FTMap:
@@ -227,13 +283,33 @@ func (p *Parser) getTypeTableLinks(ti *gexcels.FieldTypeInfo, fieldPath *[]strin
 // checkTableLinks 检查配置表td外链的其它配置表
 func (p *Parser) checkTableLinks(td *Table) (errs []error) {
 	for _, link := range td.links {
-		if err := p.checkTableLinkTable(td, link); err != nil {
-			errs = append(errs, err...)
+		diag := td.linkDiagnostic(link)
+		for _, err := range p.checkTableLinkTable(td, link) {
+			errs = append(errs, withDiagnostic(err, diag))
 		}
 	}
 	return
 }
 
+// linkDiagnostic 获取link规则所在单元格的诊断信息
+func (td *Table) linkDiagnostic(link *TableLink) Diagnostic {
+	diag := Diagnostic{
+		Path:  td.Path,
+		Sheet: td.Sheet,
+		Table: td.Name,
+		Field: strings.Join(link.srcField, "."),
+		Rule:  gexcels.FRNLink,
+	}
+	if td.IsGlobal {
+		diag.Row = td.GetFieldRow(link.srcField[0])
+		diag.Col = gexcels.GlobalTableColFieldRule + 1
+	} else if fd := td.GetFieldByName(link.srcField[0]); fd != nil {
+		diag.Row = gexcels.TableRowFieldRule + 1
+		diag.Col = fd.Col + 1
+	}
+	return diag
+}
+
 // checkTableLinkTable 检查配置表td根据link外链的配置表
 func (p *Parser) checkTableLinkTable(td *Table, link *TableLink) (errs []error) {
 	var (
@@ -243,7 +319,7 @@ func (p *Parser) checkTableLinkTable(td *Table, link *TableLink) (errs []error)
 	)
 
 	srcTable = td
-	dstTable = p.getTableByName(link.dstTable)
+	dstTable = p.GetTableByName(link.dstTable)
 	if dstTable == nil {
 		return []error{errTableLink(td.Name, link, "dst table not found")}
 	}
@@ -253,43 +329,76 @@ func (p *Parser) checkTableLinkTable(td *Table, link *TableLink) (errs []error)
 	} else {
 		srcRootField = srcTableField.Field
 	}
-	if dstTableField := dstTable.GetFieldByName(link.dstField); dstTableField == nil {
-		return []error{errTableLink(td.Name, link, "dst field not found")}
-	} else if !dstTableField.Unique() {
-		return []error{errTableLink(td.Name, link, "dst field not Unique or not export method")}
-	} else if !(dstTableField.Type.Primitive() || dstTableField.Type == gexcels.FTEnum) {
-		return []error{errTableLink(td.Name, link, "dst field type not primitive or enum")}
-	} else {
-		dstField = dstTableField.Field
-	}
-
 	path := link.srcField[1:]
 	var validateErr error
-	switch link.kind {
-	case tableLinkKindValue:
-		validateErr = p.validateValueLinkSource(srcRootField.FieldTypeInfo, path, dstField.FieldTypeInfo)
-	case tableLinkKindMapKey:
-		validateErr = p.validateMapKeyLinkSource(srcRootField.FieldTypeInfo, path, link.mapLevel, dstField.FieldTypeInfo)
-	default:
-		validateErr = fmt.Errorf("link kind invalid")
+	if len(link.dstKey) > 0 {
+		validateErr = p.validateCompositeKeyLinkSource(td, srcRootField, path, link, dstTable)
+	} else {
+		if dstTableField := dstTable.GetFieldByName(link.dstField); dstTableField == nil {
+			return []error{errTableLink(td.Name, link, "dst field not found")}
+		} else if !dstTableField.Unique() {
+			return []error{errTableLink(td.Name, link, "dst field not Unique or not export method")}
+		} else if !(dstTableField.Type.Primitive() || dstTableField.Type == gexcels.FTEnum) {
+			return []error{errTableLink(td.Name, link, "dst field type not primitive or enum")}
+		} else {
+			dstField = dstTableField.Field
+		}
+
+		switch link.kind {
+		case tableLinkKindValue:
+			validateErr = p.validateValueLinkSource(srcRootField.FieldTypeInfo, path, dstField.FieldTypeInfo)
+		case tableLinkKindMapKey:
+			validateErr = p.validateMapKeyLinkSource(srcRootField.FieldTypeInfo, path, link.mapLevel, dstField.FieldTypeInfo)
+		default:
+			validateErr = fmt.Errorf("link kind invalid")
+		}
 	}
 	if validateErr != nil {
 		return []error{errTableLink(td.Name, link, "%s", validateErr.Error())}
 	}
 
+	// 过滤条件引用了被 tag 过滤的字段时不检查过滤条件
+	link.filterExpr = nil
+	if link.filter != "" {
+		expr, err := p.compileCheck(dstTable, link.filter)
+		if err != nil && !errors.Is(err, errCheckFieldExcluded) {
+			return []error{errTableLink(td.Name, link, "filter %s", err)}
+		}
+		link.filterExpr = expr
+	}
+
 	if srcTable.IsGlobal {
 		fieldValue := srcTable.GetEntryByName(link.srcField[0])
 		if fieldValue == nil {
 			return []error{errTableLink(td.Name, link, "src field value not found")}
 		}
-		errs = append(errs, p.checkTableLinkValue(srcTable, fieldValue, srcRootField.FieldTypeInfo, path, link, dstTable, dstField)...)
+		valueDiag := Diagnostic{Col: gexcels.GlobalTableColFieldValue + 1}
+		var valueErrs []error
+		if link.keyFromTable {
+			valueErrs = p.checkTableLinkCompositeKeyValue(srcTable, srcTable.entryByName, link, dstTable)
+		} else {
+			valueErrs = p.checkTableLinkValue(srcTable, fieldValue, srcRootField.FieldTypeInfo, path, link, dstTable, dstField)
+		}
+		for _, err := range valueErrs {
+			errs = append(errs, withDiagnostic(err, valueDiag))
+		}
 	} else {
-		for _, srcEntry := range srcTable.Entries {
+		col := srcTable.GetFieldByName(link.srcField[0]).Col + 1
+		for i, srcEntry := range srcTable.Entries {
 			fieldValue := srcEntry[link.srcField[0]]
 			if fieldValue == nil {
 				continue
 			}
-			errs = append(errs, p.checkTableLinkValue(srcTable, fieldValue, srcRootField.FieldTypeInfo, path, link, dstTable, dstField)...)
+			valueDiag := Diagnostic{Row: srcTable.GetEntryRow(i), Col: col}
+			var valueErrs []error
+			if link.keyFromTable {
+				valueErrs = p.checkTableLinkCompositeKeyValue(srcTable, srcEntry, link, dstTable)
+			} else {
+				valueErrs = p.checkTableLinkValue(srcTable, fieldValue, srcRootField.FieldTypeInfo, path, link, dstTable, dstField)
+			}
+			for _, err := range valueErrs {
+				errs = append(errs, withDiagnostic(err, valueDiag))
+			}
 		}
 	}
 
@@ -302,13 +411,17 @@ func (p *Parser) validateValueLinkSource(srcType *gexcels.FieldTypeInfo, path []
 	if err != nil {
 		return err
 	}
-	if leafType.Type != dstType.Type || (leafType.Type == gexcels.FTEnum && leafType.GetName() != dstType.GetName()) {
+	if leafType.Type == gexcels.FTStruct {
+		return fmt.Errorf("LINK on struct must be defined on struct field")
+	}
+	if leafType.Type != dstType.Type || (leafType.Type == gexcels.FTEnum && leafType.GetName() != dstType.GetName()) ||
+		(leafType.Type == gexcels.FTDecimal && leafType.GetScale() != dstType.GetScale()) {
 		return fmt.Errorf("field type not match")
 	}
 	return nil
 }
 
-// getValueLeafType 获取配置表字段的叶子类型
+// getValueLeafType 获取配置表字段的叶子类型, 路径结束于结构体时返回结构体类型
 func (p *Parser) getValueLeafType(ti *gexcels.FieldTypeInfo, path []string) (*gexcels.FieldTypeInfo, error) {
 	switch ti.Type {
 	case gexcels.FTArray:
@@ -317,7 +430,7 @@ func (p *Parser) getValueLeafType(ti *gexcels.FieldTypeInfo, path []string) (*ge
 		return p.getValueLeafType(ti.GetMapValueType(), path)
 	case gexcels.FTStruct:
 		if len(path) == 0 {
-			return nil, fmt.Errorf("LINK on struct must be defined on struct field")
+			return ti, nil
 		}
 		sd := p.GetStructByName(ti.GetName())
 		if sd == nil {
@@ -328,6 +441,12 @@ func (p *Parser) getValueLeafType(ti *gexcels.FieldTypeInfo, path []string) (*ge
 			return nil, fmt.Errorf("src field not found")
 		}
 		return p.getValueLeafType(fd.FieldTypeInfo, path[1:])
+	case gexcels.FTUnion:
+		caseType, err := p.getUnionCaseType(ti, path)
+		if err != nil {
+			return nil, err
+		}
+		return p.getValueLeafType(caseType, path[1:])
 	case gexcels.FTEnum:
 		return ti, nil
 	default:
@@ -341,6 +460,77 @@ func (p *Parser) getValueLeafType(ti *gexcels.FieldTypeInfo, path []string) (*ge
 	}
 }
 
+// getUnionCaseType 获取联合类型 ti 中路径首个元素对应分支的结构体类型
+func (p *Parser) getUnionCaseType(ti *gexcels.FieldTypeInfo, path []string) (*gexcels.FieldTypeInfo, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("LINK on union must be defined on case struct field")
+	}
+	u := p.GetUnionByName(ti.GetName())
+	if u == nil {
+		return nil, fmt.Errorf("union %s not define", ti.GetName())
+	}
+	c := u.GetCaseByName(path[0])
+	if c == nil {
+		return nil, fmt.Errorf("src field not found")
+	}
+	return gexcels.NewStructFieldTypeInfo(c.Struct), nil
+}
+
+// validateCompositeKeyLinkSource 查找组合键链接的目标组合键, 并检查源字段类型是否匹配.
+// 叶子值为结构体时组合键的值取自结构体字段, 否则取自配置表字段.
+func (p *Parser) validateCompositeKeyLinkSource(td *Table, srcRootField *gexcels.Field, path []string, link *TableLink, dstTable *Table) error {
+	link.dstCompositeKey = nil
+	for _, ck := range dstTable.CompositeKeys {
+		if slices.Equal(ck.FieldNames(), link.dstKey) {
+			link.dstCompositeKey = ck
+			break
+		}
+	}
+	if link.dstCompositeKey == nil {
+		return fmt.Errorf("dst composite-key not found")
+	}
+
+	leafType, err := p.getValueLeafType(srcRootField.FieldTypeInfo, path)
+	if err != nil {
+		return err
+	}
+	var getSrcField func(name string) *gexcels.Field
+	switch {
+	case leafType.Type == gexcels.FTStruct:
+		sd := p.GetStructByName(leafType.GetName())
+		if sd == nil {
+			return fmt.Errorf("struct %s not define", leafType.GetName())
+		}
+		getSrcField = sd.GetFieldByName
+		link.keyFromTable = false
+	case len(path) == 0 && (srcRootField.Type.Primitive() || srcRootField.Type == gexcels.FTEnum):
+		if !slices.Contains(link.srcKey, srcRootField.Name) {
+			return fmt.Errorf("src field not in composite-key src fields")
+		}
+		getSrcField = func(name string) *gexcels.Field {
+			if fd := td.GetFieldByName(name); fd != nil {
+				return fd.Field
+			}
+			return nil
+		}
+		link.keyFromTable = true
+	default:
+		return fmt.Errorf("composite-key src must be struct or table field")
+	}
+
+	for i, name := range link.srcKey {
+		srcField := getSrcField(name)
+		if srcField == nil {
+			return fmt.Errorf("src field %s not found", name)
+		}
+		dstField := dstTable.GetFieldByName(link.dstKey[i])
+		if srcField.Type != dstField.Type || (srcField.Type == gexcels.FTDecimal && srcField.GetScale() != dstField.GetScale()) {
+			return fmt.Errorf("src field %s type not match", name)
+		}
+	}
+	return nil
+}
+
 // validateMapKeyLinkSource 检查配置表映射键链接值类型是否匹配
 func (p *Parser) validateMapKeyLinkSource(srcType *gexcels.FieldTypeInfo, path []string, mapLevel int, dstType *gexcels.FieldTypeInfo) error {
 	if mapLevel <= 0 {
@@ -378,6 +568,12 @@ func (p *Parser) findMapKeyTypeAtLevel(ti *gexcels.FieldTypeInfo, path []string,
 			return nil, false, fmt.Errorf("src field not found")
 		}
 		return p.findMapKeyTypeAtLevel(fd.FieldTypeInfo, path[1:], targetLevel, curLevel)
+	case gexcels.FTUnion:
+		caseType, err := p.getUnionCaseType(ti, path)
+		if err != nil {
+			return nil, false, err
+		}
+		return p.findMapKeyTypeAtLevel(caseType, path[1:], targetLevel, curLevel)
 	case gexcels.FTMap:
 		curLevel++
 		if curLevel == targetLevel {
@@ -436,13 +632,16 @@ func (p *Parser) checkValueLinkValue(srcTable *Table, srcValue any, srcType *gex
 		}
 		return errs
 	case gexcels.FTStruct:
-		if len(path) == 0 {
-			return []error{errTableLink(srcTable.Name, link, "LINK on struct must be defined on struct field")}
-		}
 		obj, ok := srcValue.(map[string]any)
 		if !ok {
 			return []error{errTableLink(srcTable.Name, link, "src value not object")}
 		}
+		if len(path) == 0 {
+			if link.dstCompositeKey == nil {
+				return []error{errTableLink(srcTable.Name, link, "LINK on struct must be defined on struct field")}
+			}
+			return p.checkTableLinkCompositeKeyValue(srcTable, obj, link, dstTable)
+		}
 		fieldName := path[0]
 		subValue, ok := obj[fieldName]
 		if !ok || subValue == nil {
@@ -457,6 +656,19 @@ func (p *Parser) checkValueLinkValue(srcTable *Table, srcValue any, srcType *gex
 			return []error{errTableLink(srcTable.Name, link, "src field not found")}
 		}
 		return p.checkValueLinkValue(srcTable, subValue, subField.FieldTypeInfo, path[1:], link, dstTable, dstField)
+	case gexcels.FTUnion:
+		uv, ok := srcValue.(*UnionValue)
+		if !ok {
+			return []error{errTableLink(srcTable.Name, link, "src value not union")}
+		}
+		caseType, err := p.getUnionCaseType(srcType, path)
+		if err != nil {
+			return []error{errTableLink(srcTable.Name, link, "%s", err)}
+		}
+		if uv.Case != path[0] {
+			return nil
+		}
+		return p.checkValueLinkValue(srcTable, uv.Value, caseType, path[1:], link, dstTable, dstField)
 	case gexcels.FTEnum:
 		if len(path) != 0 {
 			return []error{errTableLink(srcTable.Name, link, "src field not found")}
@@ -511,6 +723,19 @@ func (p *Parser) checkMapKeyLinkValue(srcTable *Table, srcValue any, srcType *ge
 			return []error{errTableLink(srcTable.Name, link, "src field not found")}
 		}
 		return p.checkMapKeyLinkValue(srcTable, subValue, subField.FieldTypeInfo, path[1:], link, curMapLevel, dstTable, dstField)
+	case gexcels.FTUnion:
+		uv, ok := srcValue.(*UnionValue)
+		if !ok {
+			return []error{errTableLink(srcTable.Name, link, "src value not union")}
+		}
+		caseType, err := p.getUnionCaseType(srcType, path)
+		if err != nil {
+			return []error{errTableLink(srcTable.Name, link, "%s", err)}
+		}
+		if uv.Case != path[0] {
+			return nil
+		}
+		return p.checkMapKeyLinkValue(srcTable, uv.Value, caseType, path[1:], link, curMapLevel, dstTable, dstField)
 	case gexcels.FTMap:
 		v := reflect.ValueOf(srcValue)
 		if v.Kind() != reflect.Map {
@@ -544,6 +769,10 @@ func (p *Parser) checkMapKeyLinkValue(srcTable *Table, srcValue any, srcType *ge
 
 // checkTableLinkPrimitiveValue 检查配置表链接值是否有效
 func (p *Parser) checkTableLinkPrimitiveValue(srcTable *Table, srcValue any, link *TableLink, dstTable *Table, dstField *gexcels.Field) (errs []error) {
+	if link.nullable != nil && link.nullable.IsNull(srcValue) {
+		return nil
+	}
+
 	vv, err := convertValue2PrimitiveFieldType(srcValue, dstField.Type)
 	if err != nil {
 		return []error{err}
@@ -551,14 +780,71 @@ func (p *Parser) checkTableLinkPrimitiveValue(srcTable *Table, srcValue any, lin
 
 	if dstField.Name == gexcels.TableFieldIDName {
 		if !dstTable.hasEntry(vv) {
-			errs = []error{errTableLink(srcTable.Name, link, "dst.%s=%v not found", dstField.Name, vv)}
+			return []error{errTableLink(srcTable.Name, link, "dst.%s=%v not found", dstField.Name, vv)}
 		}
 	} else {
 		if !dstTable.hasUniqueValue(dstField.Name, vv) {
-			errs = []error{errTableLink(srcTable.Name, link, "dst.%s=%v not found", dstField.Name, vv)}
+			return []error{errTableLink(srcTable.Name, link, "dst.%s=%v not found", dstField.Name, vv)}
+		}
+	}
+
+	if link.filterExpr != nil {
+		var dstEntry gexcels.TableEntry
+		if dstField.Name == gexcels.TableFieldIDName {
+			dstEntry = dstTable.entryByID[vv]
+		} else {
+			dstEntry = dstTable.getEntryByUnique(dstField.Name, vv)
+		}
+		ok, err := evalCheck(link.filterExpr, dstEntry)
+		if err != nil {
+			return []error{errTableLink(srcTable.Name, link, "dst.%s=%v filter %s", dstField.Name, vv, err)}
+		}
+		if !ok {
+			return []error{errTableLink(srcTable.Name, link, "dst.%s=%v not match filter", dstField.Name, vv)}
 		}
 	}
-	return errs
+	return nil
+}
+
+// checkTableLinkCompositeKeyValue 检查组合键链接值是否有效, obj 为结构体值或者配置表条目
+func (p *Parser) checkTableLinkCompositeKeyValue(srcTable *Table, obj map[string]any, link *TableLink, dstTable *Table) (errs []error) {
+	if link.keyFromTable && link.nullable != nil && link.nullable.IsNull(obj[link.srcField[0]]) {
+		return nil
+	}
+
+	values := make([]any, len(link.srcKey))
+	for i, name := range link.srcKey {
+		dstField := dstTable.GetFieldByName(link.dstKey[i])
+		v := obj[name]
+		if v == nil {
+			v = zeroValue(dstField.Type)
+		}
+		vv, err := convertValue2PrimitiveFieldType(v, dstField.Type)
+		if err != nil {
+			return []error{err}
+		}
+		values[i] = vv
+	}
+	key := compositeKeyValue(values)
+	dstKey := "(" + strings.Join(link.dstKey, gexcels.FRValueSep) + ")"
+	dstValue := make([]string, len(values))
+	for i, v := range values {
+		dstValue[i] = convertUniqueValue2String(v)
+	}
+	if !dstTable.hasUniqueValue(link.dstCompositeKey.Name, key) {
+		return []error{errTableLink(srcTable.Name, link, "dst.%s=(%s) not found", dstKey, strings.Join(dstValue, gexcels.FRValueSep))}
+	}
+
+	if link.filterExpr != nil {
+		ok, err := evalCheck(link.filterExpr, dstTable.getEntryByCompositeKey(link.dstCompositeKey, key))
+		if err != nil {
+			return []error{errTableLink(srcTable.Name, link, "dst.%s=(%s) filter %s", dstKey, strings.Join(dstValue, gexcels.FRValueSep), err)}
+		}
+		if !ok {
+			return []error{errTableLink(srcTable.Name, link, "dst.%s=(%s) not match filter", dstKey, strings.Join(dstValue, gexcels.FRValueSep))}
+		}
+	}
+	return nil
 }
 
 // checkLinksBetweenTable 检查配置表之间的链接
@@ -610,6 +896,18 @@ func convertValue2PrimitiveFieldType(val any, ft gexcels.FieldType) (any, error)
 			}
 		}
 
+	case gexcels.FTInt8, gexcels.FTInt16, gexcels.FTUint8, gexcels.FTUint16, gexcels.FTUint32, gexcels.FTUint64:
+		t := reflect.TypeOf(zeroValue(ft))
+		switch val.(type) {
+		case int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+			return reflect.ValueOf(val).Convert(t).Interface(), nil
+		case float32, float64:
+			v := reflect.ValueOf(val)
+			if f := v.Float(); math.Trunc(f) == f {
+				return v.Convert(t).Interface(), nil
+			}
+		}
+
 	case gexcels.FTFloat32:
 		v := reflect.ValueOf(val)
 		t := reflect.TypeOf(float32(0))
@@ -628,11 +926,26 @@ func convertValue2PrimitiveFieldType(val any, ft gexcels.FieldType) (any, error)
 			return v, nil
 		}
 
-	case gexcels.FTString:
+	case gexcels.FTString, gexcels.FTText:
 		if v, ok := val.(string); ok {
 			return v, nil
 		}
 
+	case gexcels.FTDatetime:
+		if v, ok := val.(time.Time); ok {
+			return v, nil
+		}
+
+	case gexcels.FTDuration:
+		if v, ok := val.(time.Duration); ok {
+			return v, nil
+		}
+
+	case gexcels.FTDecimal:
+		if v, ok := val.(int64); ok {
+			return v, nil
+		}
+
 	default:
 		return nil, errFieldTypeInvalid(ft)
 	}
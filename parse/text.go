@@ -0,0 +1,228 @@
+package parse
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/godyy/gexcels"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// TextKeySep 文本key各部分的分隔符
+const TextKeySep = "."
+
+// Text 从文本字段提取的本地化文本
+type Text struct {
+	Key   string // 文本key, 格式为 表名.ID.字段路径, 全局表为 表名.字段路径
+	Table string // 所在配置表
+	Value string // 源语言文本
+}
+
+// GetText 根据key获取文本
+func (p *Parser) GetText(key string) *Text {
+	return p.textByKey[key]
+}
+
+// addText 添加文本
+func (p *Parser) addText(t *Text) error {
+	if _, ok := p.textByKey[t.Key]; ok {
+		return fmt.Errorf("text key %s duplicate", t.Key)
+	}
+	p.Texts = append(p.Texts, t)
+	p.textByKey[t.Key] = t
+	return nil
+}
+
+// extractTexts 提取所有文本字段的值, 并将配置表中的值替换为文本key.
+// 空文本不提取, 保持为空字符串.
+// 需在检查、lint完成后执行, 保证检查作用于原始文本.
+func (p *Parser) extractTexts() error {
+	for _, td := range p.Tables {
+		if td.IsGlobal {
+			for _, fd := range td.Fields {
+				val, ok := td.entryByName[fd.Name]
+				if !ok {
+					continue
+				}
+				newVal, err := p.extractTextValue(td, fd.FieldTypeInfo, val, td.Name+TextKeySep+fd.Name)
+				if err != nil {
+					return pkg_errors.WithMessagef(err, "table %s", td.Name)
+				}
+				td.entryByName[fd.Name] = newVal
+			}
+			continue
+		}
+
+		for _, entry := range td.Entries {
+			prefix := td.Name + TextKeySep + fmt.Sprint(entry[gexcels.TableFieldIDName])
+			for _, fd := range td.Fields {
+				val, ok := entry[fd.Name]
+				if !ok {
+					continue
+				}
+				newVal, err := p.extractTextValue(td, fd.FieldTypeInfo, val, prefix+TextKeySep+fd.Name)
+				if err != nil {
+					return pkg_errors.WithMessagef(err, "table %s", td.Name)
+				}
+				entry[fd.Name] = newVal
+			}
+		}
+	}
+	return nil
+}
+
+// extractTextValue 提取类型为 ti 的值 val 中的文本, 返回替换后的值
+func (p *Parser) extractTextValue(td *Table, ti *gexcels.FieldTypeInfo, val any, key string) (any, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	switch ti.Type {
+	case gexcels.FTText:
+		s, _ := val.(string)
+		if s == "" {
+			return "", nil
+		}
+		if err := p.addText(&Text{Key: key, Table: td.Name, Value: s}); err != nil {
+			return nil, err
+		}
+		return key, nil
+
+	case gexcels.FTStruct:
+		if err := p.extractStructTexts(td, ti, val, key); err != nil {
+			return nil, err
+		}
+		return val, nil
+
+	case gexcels.FTUnion:
+		uv, _ := val.(*UnionValue)
+		if uv == nil {
+			return val, nil
+		}
+		_, st, err := p.ResolveUnionValue(ti, uv)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.extractStructTexts(td, st, uv.Value, key); err != nil {
+			return nil, err
+		}
+		return val, nil
+
+	case gexcels.FTArray:
+		et := ti.GetElementType()
+		if !p.hasText(et) {
+			return val, nil
+		}
+		rv := reflect.ValueOf(val)
+		for i := 0; i < rv.Len(); i++ {
+			elem := rv.Index(i)
+			newVal, err := p.extractTextValue(td, et, elem.Interface(), fmt.Sprintf("%s[%d]", key, i))
+			if err != nil {
+				return nil, err
+			}
+			if newVal == nil {
+				continue
+			}
+			elem.Set(reflect.ValueOf(newVal))
+		}
+		return val, nil
+
+	case gexcels.FTMap:
+		vt := ti.GetMapValueType()
+		if !p.hasText(vt) {
+			return val, nil
+		}
+		rv := reflect.ValueOf(val)
+		keys := rv.MapKeys()
+		// 按key排序, 保证提取顺序稳定
+		slices.SortFunc(keys, compareMapKey)
+		for _, k := range keys {
+			newVal, err := p.extractTextValue(td, vt, rv.MapIndex(k).Interface(), fmt.Sprintf("%s[%v]", key, k.Interface()))
+			if err != nil {
+				return nil, err
+			}
+			if newVal == nil {
+				continue
+			}
+			rv.SetMapIndex(k, reflect.ValueOf(newVal))
+		}
+		return val, nil
+
+	default:
+		return val, nil
+	}
+}
+
+// extractStructTexts 提取结构体值中的文本
+func (p *Parser) extractStructTexts(td *Table, ti *gexcels.FieldTypeInfo, val any, key string) error {
+	m, _ := val.(map[string]any)
+	if m == nil {
+		return nil
+	}
+	sd := p.GetStructByName(ti.GetName())
+	if sd == nil {
+		return fmt.Errorf("struct %s not define", ti.GetName())
+	}
+	for _, fd := range sd.Fields {
+		fv, ok := m[fd.Name]
+		if !ok {
+			continue
+		}
+		newVal, err := p.extractTextValue(td, fd.FieldTypeInfo, fv, key+TextKeySep+fd.Name)
+		if err != nil {
+			return err
+		}
+		m[fd.Name] = newVal
+	}
+	return nil
+}
+
+// hasText 类型 ti 是否包含文本类型
+func (p *Parser) hasText(ti *gexcels.FieldTypeInfo) bool {
+	switch ti.Type {
+	case gexcels.FTText:
+		return true
+	case gexcels.FTStruct:
+		sd := p.GetStructByName(ti.GetName())
+		if sd == nil {
+			return false
+		}
+		for _, fd := range sd.Fields {
+			if p.hasText(fd.FieldTypeInfo) {
+				return true
+			}
+		}
+		return false
+	case gexcels.FTUnion:
+		u := p.GetUnionByName(ti.GetName())
+		if u == nil {
+			return false
+		}
+		for _, c := range u.Cases {
+			if sd := p.GetUnionCaseStruct(c); sd != nil && p.hasText(gexcels.NewStructFieldTypeInfo(sd.Name)) {
+				return true
+			}
+		}
+		return false
+	case gexcels.FTArray:
+		return p.hasText(ti.GetElementType())
+	case gexcels.FTMap:
+		return p.hasText(ti.GetMapValueType())
+	default:
+		return false
+	}
+}
+
+// compareMapKey 比较 map key, key 为整数或字符串
+func compareMapKey(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	default:
+		return cmp.Compare(a.String(), b.String())
+	}
+}
@@ -0,0 +1,124 @@
+package parse
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godyy/gexcels"
+)
+
+// datetimeLayouts 未指定时区的 datetime 格式, 按 Options.TimeZone 解析
+var datetimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+}
+
+// parseDatetimeValue 解析 datetime 字段值.
+// 支持 RFC3339 格式, 未指定时区的常用日期时间格式, 以及 Excel 日期序列值;
+// 结果转换为 UTC, 精度为毫秒.
+func (p *Parser) parseDatetimeValue(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if serial, err := strconv.ParseFloat(s, 64); err == nil {
+		return p.excelSerialToDatetime(serial)
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC().Truncate(time.Millisecond), nil
+	}
+	for _, layout := range datetimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, p.options.location); err == nil {
+			return t.UTC().Truncate(time.Millisecond), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("datetime %s invalid, e.g. 2006-01-02 15:04:05", s)
+}
+
+// excelSerialToDatetime 将 Excel 日期序列值转换为 datetime, 整数部分为天数, 小数部分为当天的时间
+func (p *Parser) excelSerialToDatetime(serial float64) (time.Time, error) {
+	if serial < 0 || serial > 2958465 { // 9999-12-31
+		return time.Time{}, fmt.Errorf("excel date serial %v out of range", serial)
+	}
+	days := math.Floor(serial)
+	ms := math.Round((serial - days) * float64(24*time.Hour/time.Millisecond))
+	// 1900 日期系统, 序列值 0 对应 1899-12-30
+	t := time.Date(1899, time.December, 30, 0, 0, 0, 0, p.options.location)
+	t = t.AddDate(0, 0, int(days)).Add(time.Duration(ms) * time.Millisecond)
+	return t.UTC(), nil
+}
+
+// durationDaysRegexp duration 的天数部分, time.ParseDuration 不支持
+var durationDaysRegexp = regexp.MustCompile(`^([-+]?)(\d+)d(.*)$`)
+
+// parseDurationValue 解析 duration 字段值, 格式同 time.ParseDuration, 额外支持天(d), e.g. 1h30m, 90s, 1d12h
+func parseDurationValue(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	matches := durationDaysRegexp.FindStringSubmatch(s)
+	if matches == nil {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("duration %s invalid, e.g. 1h30m", s)
+		}
+		return d, nil
+	}
+
+	n, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil || n > math.MaxInt64/int64(24*time.Hour) {
+		return 0, fmt.Errorf("duration %s out of range", s)
+	}
+	d := time.Duration(n) * 24 * time.Hour
+	if rest := matches[3]; rest != "" {
+		if rest[0] == '-' || rest[0] == '+' {
+			return 0, fmt.Errorf("duration %s invalid, e.g. 1d12h", s)
+		}
+		r, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("duration %s invalid, e.g. 1d12h", s)
+		}
+		d += r
+	}
+	if matches[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseTimeValue 解析 datetime 或 duration 字段值
+func (p *Parser) parseTimeValue(ft gexcels.FieldType, s string) (any, error) {
+	if ft == gexcels.FTDatetime {
+		return p.parseDatetimeValue(s)
+	}
+	return parseDurationValue(s)
+}
+
+// convertJSONTimeValue 将json raw对象转换为 datetime 或 duration 值, raw 需为字符串
+func (p *Parser) convertJSONTimeValue(ti *gexcels.FieldTypeInfo, raw any, path string) (any, error) {
+	if raw == nil {
+		return zeroValue(ti.Type), nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be string", path)
+	}
+	v, err := p.parseTimeValue(ti.Type, s)
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", path, err)
+	}
+	return v, nil
+}
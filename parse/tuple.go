@@ -0,0 +1,58 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/godyy/gexcels"
+)
+
+// parseTupleFieldValue 解析元组字段值, 支持紧凑写法 1.5,2,0 与 json 数组写法 [1.5,2,0]
+func (p *Parser) parseTupleFieldValue(fd *gexcels.Field, s string) (any, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var raw any = s
+	if strings.HasPrefix(s, "[") {
+		if err := json.Unmarshal([]byte(s), &raw); err != nil {
+			return nil, err
+		}
+	}
+	return p.convertJSONTupleValue(fd.FieldTypeInfo, raw, fd.Name)
+}
+
+// convertJSONTupleValue 将json raw对象转换为元组值, 元组值用 []any 表示.
+// raw 为json数组, 或紧凑写法的字符串, e.g. "1.5,2,0".
+func (p *Parser) convertJSONTupleValue(ti *gexcels.FieldTypeInfo, raw any, path string) ([]any, error) {
+	var arr []any
+	switch v := raw.(type) {
+	case []any:
+		arr = v
+	case string:
+		for _, token := range strings.Split(v, gexcels.TupleElementSep) {
+			arr = append(arr, strings.TrimSpace(token))
+		}
+	default:
+		return nil, fmt.Errorf("%s must be tuple, e.g. 1,2", path)
+	}
+
+	elems := ti.GetTupleElements()
+	if ti.GetName() == gexcels.TupleColor && len(arr) == len(elems)-1 {
+		// 颜色省略 alpha 时不透明
+		arr = append(arr, float64(1))
+	}
+	if len(arr) != len(elems) {
+		return nil, fmt.Errorf("%s %s must have %d elements, got %d", path, ti, len(elems), len(arr))
+	}
+
+	out := make([]any, len(elems))
+	for i, et := range elems {
+		c, err := p.convertFieldJSONValue(et, arr[i], fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = c
+	}
+	return out, nil
+}
@@ -0,0 +1,209 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/godyy/gexcels"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// Union 联合类型
+type Union struct {
+	*gexcels.Union        // 基础信息
+	Path           string // 数据源路径
+	Sheet          string // sheet名
+	Row            int    // 定义所在行号(从1开始)
+}
+
+// UnionValue 联合类型的值
+type UnionValue struct {
+	Case  string         // 分支名称
+	Value map[string]any // 分支结构体的值
+}
+
+// addUnion 添加联合类型
+func (p *Parser) addUnion(u *Union) error {
+	if err := p.addCustomFieldType(gexcels.NewUnionFieldTypeInfo(u.Name)); err != nil {
+		return err
+	}
+	p.Unions = append(p.Unions, u)
+	p.unionByName[u.Name] = u
+	return nil
+}
+
+// GetUnionByName 根据名称获取联合类型
+func (p *Parser) GetUnionByName(name string) *Union {
+	return p.unionByName[name]
+}
+
+// GetUnionCaseStruct 获取联合类型分支的结构体
+func (p *Parser) GetUnionCaseStruct(c *gexcels.UnionCase) *Struct {
+	return p.GetStructByName(c.Struct)
+}
+
+// unionDefineRegexp 联合类型定义匹配正则表达式, 定义在结构体表的字段列.
+// 格式：union(Enum):Case[=Struct],...
+// 分支名称为判别枚举的枚举项名称, 省略结构体时使用与分支同名的结构体.
+var unionDefineRegexp = regexp.MustCompile(`^union\(\s*(` + gexcels.NamePattern + `)\s*\)\s*:(.*)$`)
+
+// unionCaseRegexp 联合类型分支定义匹配正则表达式
+var unionCaseRegexp = regexp.MustCompile(`^(` + gexcels.NamePattern + `)(?:\s*=\s*(` + gexcels.NamePattern + `))?$`)
+
+// isUnionRow 结构体表中的行是否定义联合类型
+func isUnionRow(row sheetRow) bool {
+	return strings.HasPrefix(strings.TrimSpace(row.value(gexcels.TableStructColFields)), "union(")
+}
+
+// parseUnionRow 解析row中定义的联合类型
+func (p *Parser) parseUnionRow(row sheetRow) (*Union, error) {
+	name := strings.TrimSpace(row.value(gexcels.TableStructColName))
+	if !gexcels.MatchName(name) {
+		return nil, fmt.Errorf("union name %s invalid", name)
+	}
+	if rule := strings.TrimSpace(row.value(gexcels.TableStructColRule)); rule != "" {
+		return nil, fmt.Errorf("union %s rule not supported", name)
+	}
+
+	define := strings.TrimSpace(row.value(gexcels.TableStructColFields))
+	matches := unionDefineRegexp.FindStringSubmatch(define)
+	if len(matches) != 3 {
+		return nil, fmt.Errorf("union define (%s) invalid, e.g. union(Enum):Case=Struct", define)
+	}
+	enum := p.GetEnum(matches[1])
+	if enum == nil {
+		return nil, errEnumNotDefine(matches[1])
+	}
+	if enum.Flags {
+		return nil, fmt.Errorf("union discriminator enum %s is flags", enum.Name)
+	}
+
+	u := &Union{Union: gexcels.NewUnion(name, enum.Name, row.value(gexcels.TableStructColDesc))}
+	caseByStruct := make(map[string]string)
+	for _, token := range strings.Split(matches[2], gexcels.StructFieldSep) {
+		caseMatches := unionCaseRegexp.FindStringSubmatch(strings.TrimSpace(token))
+		if len(caseMatches) != 3 {
+			return nil, fmt.Errorf("union case (%s) invalid", strings.TrimSpace(token))
+		}
+		c := &gexcels.UnionCase{Name: caseMatches[1], Struct: caseMatches[2]}
+		if c.Struct == "" {
+			c.Struct = c.Name
+		}
+		if enum.GetItemByName(c.Name) == nil {
+			return nil, errEnumItemNotDefine(enum.Name, c.Name)
+		}
+		if p.GetStructByName(c.Struct) == nil {
+			return nil, pkg_errors.WithMessagef(errStructNotDefine(c.Struct), "case %s", c.Name)
+		}
+		if other, ok := caseByStruct[c.Struct]; ok {
+			return nil, fmt.Errorf("case %s struct %s already used by case %s", c.Name, c.Struct, other)
+		}
+		if !u.AddCase(c) {
+			return nil, fmt.Errorf("case %s duplicate", c.Name)
+		}
+		caseByStruct[c.Struct] = c.Name
+	}
+	return u, nil
+}
+
+// parseUnionFieldValue 解析联合类型字段值, e.g. Damage{Amount:10} 或 {Damage:{Amount:10}}
+func (p *Parser) parseUnionFieldValue(fd *gexcels.Field, s string) (any, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var (
+		uv  *UnionValue
+		err error
+	)
+	if strings.HasPrefix(s, "{") {
+		var raw any
+		if err := json.Unmarshal([]byte(s), &raw); err != nil {
+			return nil, err
+		}
+		uv, err = p.convertJSONUnionValue(fd.FieldTypeInfo, raw, fd.Name)
+	} else {
+		uv, err = p.convertUnionText(fd.FieldTypeInfo, s, fd.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return uv, nil
+}
+
+// convertUnionText 将文本形式的联合类型值转换为 UnionValue.
+// 格式为分支名称加分支结构体的json对象, 结构体可省略, e.g. Damage{Amount:10}, Stun.
+func (p *Parser) convertUnionText(ti *gexcels.FieldTypeInfo, s string, path string) (*UnionValue, error) {
+	s = strings.TrimSpace(s)
+	name, body := s, ""
+	if i := strings.IndexByte(s, '{'); i >= 0 {
+		name, body = strings.TrimSpace(s[:i]), s[i:]
+	}
+
+	var raw any
+	if body != "" {
+		if err := json.Unmarshal([]byte(body), &raw); err != nil {
+			return nil, pkg_errors.WithMessagef(err, "%s case %s", path, name)
+		}
+	}
+	return p.convertUnionCaseValue(ti, name, raw, path)
+}
+
+// convertJSONUnionValue 将json raw对象转换为联合类型值.
+// raw 为仅包含一个键的对象, 键为分支名称, 值为分支结构体, e.g. {"Damage":{"Amount":10}};
+// 也可以是文本形式的字符串, e.g. "Damage{Amount:10}".
+func (p *Parser) convertJSONUnionValue(ti *gexcels.FieldTypeInfo, raw any, path string) (*UnionValue, error) {
+	switch v := raw.(type) {
+	case string:
+		return p.convertUnionText(ti, v, path)
+	case map[string]any:
+		if len(v) == 1 {
+			for name, value := range v {
+				return p.convertUnionCaseValue(ti, name, value, path)
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s must be union, e.g. Damage{Amount:10}", path)
+}
+
+// convertUnionCaseValue 将分支 name 的json raw对象转换为联合类型值, raw 为 nil 时分支结构体的字段均未填写
+func (p *Parser) convertUnionCaseValue(ti *gexcels.FieldTypeInfo, name string, raw any, path string) (*UnionValue, error) {
+	u := p.GetUnionByName(ti.GetName())
+	if u == nil {
+		return nil, fmt.Errorf("union %s not define", ti.GetName())
+	}
+	c := u.GetCaseByName(name)
+	if c == nil {
+		return nil, fmt.Errorf("%s union %s case %s not define", path, u.Name, name)
+	}
+	if raw == nil {
+		raw = map[string]any{}
+	}
+	value, err := p.convertJSONStructValue(gexcels.NewStructFieldTypeInfo(c.Struct), raw, path+"."+name)
+	if err != nil {
+		return nil, err
+	}
+	return &UnionValue{Case: name, Value: value.(map[string]any)}, nil
+}
+
+// ResolveUnionValue 获取联合类型值 uv 的判别枚举值及分支结构体类型
+func (p *Parser) ResolveUnionValue(ti *gexcels.FieldTypeInfo, uv *UnionValue) (disc any, st *gexcels.FieldTypeInfo, err error) {
+	u := p.GetUnionByName(ti.GetName())
+	if u == nil {
+		return nil, nil, fmt.Errorf("union %s not define", ti.GetName())
+	}
+	c := u.GetCaseByName(uv.Case)
+	if c == nil {
+		return nil, nil, fmt.Errorf("union %s case %s not define", u.Name, uv.Case)
+	}
+	enum := p.GetEnum(u.Enum)
+	if enum == nil {
+		return nil, nil, errEnumNotDefine(u.Enum)
+	}
+	disc, ok := enum.GetItemValueByName(c.Name)
+	if !ok {
+		return nil, nil, errEnumItemNotDefine(enum.Name, c.Name)
+	}
+	return disc, gexcels.NewStructFieldTypeInfo(c.Struct), nil
+}
@@ -30,6 +30,7 @@ const StructFieldSep = ","
 type Struct struct {
 	Name        string            // 名称
 	Desc        string            // 描述
+	Parent      *Struct           // 父结构体, 继承的字段排在字段列表前面
 	Fields      []*Field          // 字段
 	FieldByName map[string]*Field // 字段名称到字段映射
 }
@@ -63,6 +64,14 @@ func (sd *Struct) AddField(field *Field) bool {
 	return true
 }
 
+// OwnFields 获取结构体自身定义的字段, 不包含继承自父结构体的字段
+func (sd *Struct) OwnFields() []*Field {
+	if sd.Parent == nil {
+		return sd.Fields
+	}
+	return sd.Fields[len(sd.Parent.Fields):]
+}
+
 // GetFieldByName 根据名称获取字段
 func (sd *Struct) GetFieldByName(name string) *Field {
 	return sd.FieldByName[name]
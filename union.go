@@ -0,0 +1,48 @@
+package gexcels
+
+// UnionCase 联合类型分支
+type UnionCase struct {
+	Name   string // 分支名称, 即判别枚举的枚举项名称
+	Struct string // 分支结构体名称
+}
+
+// Union 联合类型, 在结构体表中定义.
+// 值为判别枚举的某个枚举项及其分支结构体的值.
+type Union struct {
+	Name       string                // 名称
+	Desc       string                // 描述
+	Enum       string                // 判别枚举名称
+	Cases      []*UnionCase          // 分支
+	CaseByName map[string]*UnionCase // 分支名称到分支映射
+}
+
+// NewUnion 创建联合类型
+func NewUnion(name, enum, desc string) *Union {
+	if !MatchName(name) {
+		panic("gexcels: NewUnion: name " + name + " invalid")
+	}
+	return &Union{
+		Name:       name,
+		Desc:       desc,
+		Enum:       enum,
+		CaseByName: make(map[string]*UnionCase),
+	}
+}
+
+// AddCase 添加分支, 分支名称重复时返回 false
+func (u *Union) AddCase(c *UnionCase) bool {
+	if c == nil {
+		panic("gexcels: Union.AddCase: case is nil")
+	}
+	if _, ok := u.CaseByName[c.Name]; ok {
+		return false
+	}
+	u.Cases = append(u.Cases, c)
+	u.CaseByName[c.Name] = c
+	return true
+}
+
+// GetCaseByName 根据名称获取分支
+func (u *Union) GetCaseByName(name string) *UnionCase {
+	return u.CaseByName[name]
+}